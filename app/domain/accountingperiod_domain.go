@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+// AccountingPeriodFormat is the format of the calendar month that names a period.
+const AccountingPeriodFormat = "2006-01"
+
+type AccountingPeriodStatus string
+
+const (
+	AccountingPeriodStatusOpen   AccountingPeriodStatus = "OPEN"
+	AccountingPeriodStatusClosed AccountingPeriodStatus = "CLOSED"
+)
+
+// AccountingPeriod is a calendar month of the ledger, from StartDate up to but not including
+// EndDate in value dates. Closing a period stores the balance of every account at its end and
+// locks it: nothing can be posted with a value date before the end of a closed period anymore.
+type AccountingPeriod struct {
+	ID              int64                   `json:"id" db:"id"`
+	Period          string                  `json:"period" db:"period"`
+	StartDate       time.Time               `json:"start_date" db:"start_date"`
+	EndDate         time.Time               `json:"end_date" db:"end_date"`
+	Status          AccountingPeriodStatus  `json:"status" db:"status"`
+	ClosedAt        *time.Time              `json:"closed_at" db:"closed_at"`
+	CreatedAt       time.Time               `json:"created_at" db:"created_at"`
+	ClosingBalances []*LedgerAccountBalance `json:"closing_balances,omitempty" db:"-"`
+}
+
+func (p *AccountingPeriod) TableName() string {
+	return "accounting_periods"
+}
+
+// NewAccountingPeriod returns the open period of the month named by period, e.g. 2026-03.
+func NewAccountingPeriod(period string) (*AccountingPeriod, error) {
+	startDate, err := time.Parse(AccountingPeriodFormat, period)
+	if err != nil {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	return &AccountingPeriod{
+		Period:    period,
+		StartDate: startDate,
+		EndDate:   startDate.AddDate(0, 1, 0),
+		Status:    AccountingPeriodStatusOpen,
+	}, nil
+}
+
+// Close marks the period closed with the balances of its accounts at its end.
+func (p *AccountingPeriod) Close(closingBalances []*LedgerAccountBalance, at time.Time) error {
+	if p.Status == AccountingPeriodStatusClosed {
+		return errors.ErrAccountingPeriodClosed
+	}
+	if at.Before(p.EndDate) {
+		return errors.ErrAccountingPeriodNotEnded
+	}
+
+	p.Status = AccountingPeriodStatusClosed
+	p.ClosedAt = &at
+	p.ClosingBalances = closingBalances
+
+	return nil
+}
+
+type OpenAccountingPeriodRequest struct {
+	Period string `json:"period" binding:"required"`
+}
+
+// AddAccountBalances adds the postings of movements to the balances of opening, account by
+// account. Accounts that only appear in movements start from zero.
+func AddAccountBalances(opening, movements []*LedgerAccountBalance) []*LedgerAccountBalance {
+	openingByAccount := make(map[string]*LedgerAccountBalance, len(opening))
+	for _, accountBalance := range opening {
+		openingByAccount[accountBalance.AccountID] = accountBalance
+	}
+
+	accountBalances := make([]*LedgerAccountBalance, 0, len(movements))
+	for _, movement := range movements {
+		accountBalance := *movement
+		if openingBalance, ok := openingByAccount[movement.AccountID]; ok {
+			accountBalance.TotalDebit += openingBalance.TotalDebit
+			accountBalance.TotalCredit += openingBalance.TotalCredit
+		}
+		accountBalance.CalculateBalance()
+
+		accountBalances = append(accountBalances, &accountBalance)
+	}
+
+	return accountBalances
+}
+
+type AccountingPeriodRepository interface {
+	Create(ctx context.Context, period *AccountingPeriod) (*AccountingPeriod, error)
+	GetByPeriod(ctx context.Context, period string) (*AccountingPeriod, error)
+	GetAll(ctx context.Context) ([]*AccountingPeriod, error)
+	GetLatestClosed(ctx context.Context, endingBy time.Time) (*AccountingPeriod, error)
+	IsClosed(ctx context.Context, date time.Time) (bool, error)
+	HasOpenBefore(ctx context.Context, startDate time.Time) (bool, error)
+	GetClosingBalances(ctx context.Context, periodID int64) ([]*LedgerAccountBalance, error)
+	Close(ctx context.Context, period *AccountingPeriod) error
+}
+
+type AccountingPeriodUsecase interface {
+	OpenPeriod(ctx context.Context, request *OpenAccountingPeriodRequest) (*AccountingPeriod, error)
+	GetPeriods(ctx context.Context) ([]*AccountingPeriod, error)
+	GetPeriod(ctx context.Context, period string) (*AccountingPeriod, error)
+	ClosePeriod(ctx context.Context, period string) (*AccountingPeriod, error)
+}
@@ -0,0 +1,58 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAccountingPeriod(t *testing.T) {
+	accountingPeriod, err := domain.NewAccountingPeriod("2026-12")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), accountingPeriod.StartDate)
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), accountingPeriod.EndDate)
+	assert.Equal(t, domain.AccountingPeriodStatusOpen, accountingPeriod.Status)
+
+	_, err = domain.NewAccountingPeriod("2026-13")
+	assert.ErrorIs(t, err, domErr.ErrInvalidParameter)
+}
+
+func TestAccountingPeriod_Close(t *testing.T) {
+	accountingPeriod, err := domain.NewAccountingPeriod("2026-03")
+	assert.NoError(t, err)
+
+	err = accountingPeriod.Close(nil, time.Date(2026, 3, 31, 23, 59, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, domErr.ErrAccountingPeriodNotEnded)
+	assert.Equal(t, domain.AccountingPeriodStatusOpen, accountingPeriod.Status)
+
+	closedAt := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, accountingPeriod.Close([]*domain.LedgerAccountBalance{}, closedAt))
+	assert.Equal(t, domain.AccountingPeriodStatusClosed, accountingPeriod.Status)
+	assert.Equal(t, &closedAt, accountingPeriod.ClosedAt)
+
+	err = accountingPeriod.Close(nil, closedAt)
+	assert.ErrorIs(t, err, domErr.ErrAccountingPeriodClosed)
+}
+
+func TestAddAccountBalances(t *testing.T) {
+	opening := []*domain.LedgerAccountBalance{
+		{AccountID: "fee-income", NormalBalance: domain.NormalBalanceCredit, TotalCredit: 100},
+	}
+	movements := []*domain.LedgerAccountBalance{
+		{AccountID: "fee-income", NormalBalance: domain.NormalBalanceCredit, TotalDebit: 10, TotalCredit: 50},
+		{AccountID: "suspense", NormalBalance: domain.NormalBalanceDebit, TotalDebit: 30},
+	}
+
+	accountBalances := domain.AddAccountBalances(opening, movements)
+
+	if assert.Len(t, accountBalances, 2) {
+		assert.Equal(t, int64(140), accountBalances[0].Balance)
+		assert.Equal(t, int64(150), accountBalances[0].TotalCredit)
+		assert.Equal(t, int64(30), accountBalances[1].Balance)
+	}
+	// the movements are not changed
+	assert.Equal(t, int64(50), movements[0].TotalCredit)
+}
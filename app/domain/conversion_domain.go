@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Conversion moves value between two wallets of the same user in different currencies, at the
+// rate of the quote it used.
+type Conversion struct {
+	ID           int64     `json:"id" db:"id"`
+	ReferenceID  string    `json:"reference_id" db:"reference_id"`
+	QuoteID      string    `json:"quote_id" db:"quote_id"`
+	UserID       int64     `json:"user_id" db:"user_id"`
+	FromCurrency string    `json:"from_currency" db:"from_currency"`
+	FromAmount   int64     `json:"from_amount" db:"from_amount"`
+	ToCurrency   string    `json:"to_currency" db:"to_currency"`
+	ToAmount     int64     `json:"to_amount" db:"to_amount"`
+	Rate         string    `json:"rate" db:"rate"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+func (c *Conversion) TableName() string {
+	return "conversions"
+}
+
+type ConvertCurrencyRequest struct {
+	QuoteID string `json:"quote_id" binding:"required,max=50"`
+}
+
+type ConversionRepository interface {
+	Create(ctx context.Context, conversion *Conversion) (*Conversion, error)
+}
+
+type ConversionUsecase interface {
+	ConvertCurrency(ctx context.Context, userID int64, request *ConvertCurrencyRequest) (*Conversion, error)
+}
@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type DisbursementStatus string
+
+const (
+	DisbursementStatusPending   DisbursementStatus = "PENDING"
+	DisbursementStatusSubmitted DisbursementStatus = "SUBMITTED"
+	DisbursementStatusCompleted DisbursementStatus = "COMPLETED"
+	DisbursementStatusFailed    DisbursementStatus = "FAILED"
+	DisbursementStatusReversed  DisbursementStatus = "REVERSED"
+	// DisbursementStatusManualReview is a submitted disbursement whose outcome the provider did
+	// not report before the review deadline, or a pending one whose submission never finished,
+	// which keeps its hold until it is settled. It is also a settled disbursement whose
+	// provider later reported the opposite outcome.
+	DisbursementStatusManualReview DisbursementStatus = "MANUAL_REVIEW"
+)
+
+var disbursementStatusTransitions = map[DisbursementStatus][]DisbursementStatus{
+	DisbursementStatusPending:      {DisbursementStatusSubmitted, DisbursementStatusFailed, DisbursementStatusManualReview},
+	DisbursementStatusSubmitted:    {DisbursementStatusCompleted, DisbursementStatusFailed, DisbursementStatusManualReview},
+	DisbursementStatusManualReview: {DisbursementStatusCompleted, DisbursementStatusFailed},
+	DisbursementStatusCompleted:    {DisbursementStatusReversed, DisbursementStatusManualReview},
+	DisbursementStatusFailed:       {DisbursementStatusManualReview},
+}
+
+func (s DisbursementStatus) CanTransitionTo(next DisbursementStatus) bool {
+	for _, allowed := range disbursementStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Disbursement struct {
+	ID                    int64              `json:"id" db:"id"`
+	ReferenceID           string             `json:"reference_id" db:"reference_id"`
+	UserID                int64              `json:"user_id" db:"user_id"`
+	Amount                int64              `json:"amount" db:"amount"`
+	Currency              string             `json:"currency" db:"currency"`
+	BankCode              string             `json:"bank_code" db:"bank_code"`
+	AccountNo             string             `json:"account_no" db:"account_no"`
+	AccountName           string             `json:"account_name" db:"account_name"`
+	Status                DisbursementStatus `json:"status" db:"status"`
+	Provider              string             `json:"provider" db:"provider"`
+	PartnerDisbursementID string             `json:"partner_disbursement_id" db:"partner_disbursement_id"`
+	FailureReason         string             `json:"failure_reason" db:"failure_reason"`
+	PollAttempts          int                `json:"poll_attempts" db:"poll_attempts"`
+	NextPollAt            *time.Time         `json:"next_poll_at" db:"next_poll_at"`
+	CreatedAt             time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time          `json:"updated_at" db:"updated_at"`
+	SubmittedAt           *time.Time         `json:"submitted_at" db:"submitted_at"`
+	CompletedAt           *time.Time         `json:"completed_at" db:"completed_at"`
+	FailedAt              *time.Time         `json:"failed_at" db:"failed_at"`
+	ReversedAt            *time.Time         `json:"reversed_at" db:"reversed_at"`
+}
+
+func (d *Disbursement) TableName() string {
+	return "disbursements"
+}
+
+func (d *Disbursement) Money() Money {
+	return NewMoney(d.Amount, d.Currency)
+}
+
+// TransitionTo moves the disbursement to status, stamping the matching timestamp unless it
+// had that status before.
+func (d *Disbursement) TransitionTo(status DisbursementStatus, at time.Time) error {
+	if !d.Status.CanTransitionTo(status) {
+		return errors.ErrInvalidDisbursementStatusTransition
+	}
+
+	switch {
+	case status == DisbursementStatusSubmitted && d.SubmittedAt == nil:
+		d.SubmittedAt = &at
+	case status == DisbursementStatusCompleted && d.CompletedAt == nil:
+		d.CompletedAt = &at
+	case status == DisbursementStatusFailed && d.FailedAt == nil:
+		d.FailedAt = &at
+	case status == DisbursementStatusReversed && d.ReversedAt == nil:
+		d.ReversedAt = &at
+	}
+	d.Status = status
+	d.UpdatedAt = at
+
+	return nil
+}
+
+// OutcomeUnknown reports whether the disbursement was sent but its provider never answered,
+// so it may or may not have been paid out. Its amount stays held until a callback, polling or
+// a person settles it.
+func (d *Disbursement) OutcomeUnknown() bool {
+	return d.Status == DisbursementStatusSubmitted && d.PartnerDisbursementID == ""
+}
+
+// SettledStatus is the outcome the amount of the disbursement was settled with, COMPLETED
+// when it was deducted or FAILED when its hold was released, or empty before that.
+func (d *Disbursement) SettledStatus() DisbursementStatus {
+	switch {
+	case d.CompletedAt != nil:
+		return DisbursementStatusCompleted
+	case d.FailedAt != nil:
+		return DisbursementStatusFailed
+	default:
+		return ""
+	}
+}
+
+// DisbursementSettlement is the final outcome of a submitted disbursement, either COMPLETED
+// or FAILED.
+type DisbursementSettlement struct {
+	ReferenceID           string
+	PartnerDisbursementID string
+	Status                DisbursementStatus
+	FailureReason         string
+	// Providers are the providers that may have sent the disbursement, one of which reports
+	// the outcome. They are not checked when an operator settles it by hand.
+	Providers  []string
+	ByOperator bool
+}
+
+// SettleDisbursementRequest is how an operator settles a disbursement in manual review
+// after checking its outcome with the provider.
+type SettleDisbursementRequest struct {
+	Status        DisbursementStatus `json:"status" binding:"required,oneof=COMPLETED FAILED"`
+	FailureReason string             `json:"failure_reason" binding:"max=255"`
+}
+
+// DisbursementPollPolicy decides when a submitted disbursement is checked with its provider
+// again and when it is given up to manual review. A pending disbursement is treated the same
+// once it is stale.
+type DisbursementPollPolicy struct {
+	// Backoff is the wait after the first poll without an outcome, it doubles with every
+	// further one up to MaxBackoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// ReviewDeadline is how long after its submission a disbursement may stay without outcome.
+	ReviewDeadline time.Duration
+	// PendingTimeout is how long a disbursement may stay pending before it counts as left
+	// behind by a submission that never finished, e.g. because the service stopped.
+	PendingTimeout time.Duration
+	// BatchSize is the number of disbursements polled in one run.
+	BatchSize int
+}
+
+// NextPollAt is when a disbursement that was polled attempts times without an outcome is
+// polled again.
+func (p DisbursementPollPolicy) NextPollAt(attempts int, now time.Time) time.Time {
+	wait := p.Backoff
+	for i := 1; i < attempts && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+
+	return now.Add(wait)
+}
+
+// ReviewDue reports whether disbursement is past the review deadline at now.
+func (p DisbursementPollPolicy) ReviewDue(disbursement *Disbursement, now time.Time) bool {
+	submittedAt := disbursement.CreatedAt
+	if disbursement.SubmittedAt != nil {
+		submittedAt = *disbursement.SubmittedAt
+	}
+
+	return !now.Before(submittedAt.Add(p.ReviewDeadline))
+}
+
+// DisbursementPollReport counts what one polling run did with the disbursements it checked.
+type DisbursementPollReport struct {
+	Checked   int `json:"checked"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+	Pending   int `json:"pending"`
+	Escalated int `json:"escalated"`
+	Errors    int `json:"errors"`
+}
+
+type DisbursementRepository interface {
+	Create(ctx context.Context, disbursement *Disbursement) (*Disbursement, error)
+	GetByID(ctx context.Context, id int64) (*Disbursement, error)
+	GetByReferenceID(ctx context.Context, referenceID string) (*Disbursement, error)
+	GetByUserID(ctx context.Context, userID int64) ([]*Disbursement, error)
+	GetByPartnerDisbursementID(ctx context.Context, partnerDisbursementID string) (*Disbursement, error)
+	GetCompletedBetween(ctx context.Context, from, to time.Time) ([]*Disbursement, error)
+	UpdateStatus(ctx context.Context, disbursement *Disbursement, previousStatus DisbursementStatus) error
+	GetDueForPolling(ctx context.Context, now, pendingBefore time.Time, limit int) ([]*Disbursement, error)
+	UpdatePollSchedule(ctx context.Context, disbursement *Disbursement) error
+}
+
+type DisbursementUsecase interface {
+	GetDisbursementByReferenceID(ctx context.Context, referenceID string) (*Disbursement, error)
+	GetDisbursementsByUserID(ctx context.Context, userID int64) ([]*Disbursement, error)
+	GetUserDisbursementByID(ctx context.Context, userID, id int64) (*Disbursement, error)
+}
+
+type DisbursementPollingUsecase interface {
+	PollSubmittedDisbursements(ctx context.Context) (*DisbursementPollReport, error)
+}
+
+type ReferenceIDGenerator interface {
+	Generate() string
+}
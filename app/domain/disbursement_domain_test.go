@@ -0,0 +1,120 @@
+package domain_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisbursementStatus_CanTransitionTo(t *testing.T) {
+	testCases := []struct {
+		from     domain.DisbursementStatus
+		to       domain.DisbursementStatus
+		expected bool
+	}{
+		{domain.DisbursementStatusPending, domain.DisbursementStatusSubmitted, true},
+		{domain.DisbursementStatusPending, domain.DisbursementStatusFailed, true},
+		{domain.DisbursementStatusPending, domain.DisbursementStatusCompleted, false},
+		{domain.DisbursementStatusSubmitted, domain.DisbursementStatusCompleted, true},
+		{domain.DisbursementStatusSubmitted, domain.DisbursementStatusFailed, true},
+		{domain.DisbursementStatusSubmitted, domain.DisbursementStatusReversed, false},
+		{domain.DisbursementStatusSubmitted, domain.DisbursementStatusManualReview, true},
+		{domain.DisbursementStatusManualReview, domain.DisbursementStatusCompleted, true},
+		{domain.DisbursementStatusManualReview, domain.DisbursementStatusFailed, true},
+		{domain.DisbursementStatusManualReview, domain.DisbursementStatusSubmitted, false},
+		{domain.DisbursementStatusPending, domain.DisbursementStatusManualReview, true},
+		{domain.DisbursementStatusCompleted, domain.DisbursementStatusReversed, true},
+		{domain.DisbursementStatusCompleted, domain.DisbursementStatusFailed, false},
+		{domain.DisbursementStatusCompleted, domain.DisbursementStatusManualReview, true},
+		{domain.DisbursementStatusFailed, domain.DisbursementStatusSubmitted, false},
+		{domain.DisbursementStatusFailed, domain.DisbursementStatusManualReview, true},
+		{domain.DisbursementStatusReversed, domain.DisbursementStatusManualReview, false},
+		{domain.DisbursementStatusReversed, domain.DisbursementStatusCompleted, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.from)+"_"+string(tc.to), func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.from.CanTransitionTo(tc.to))
+		})
+	}
+}
+
+func TestDisbursement_TransitionTo(t *testing.T) {
+	at := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Allowed", func(t *testing.T) {
+		disbursement := &domain.Disbursement{Status: domain.DisbursementStatusPending}
+
+		err := disbursement.TransitionTo(domain.DisbursementStatusSubmitted, at)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusSubmitted, disbursement.Status)
+		assert.Equal(t, &at, disbursement.SubmittedAt)
+		assert.Equal(t, at, disbursement.UpdatedAt)
+	})
+
+	t.Run("BackFromManualReview", func(t *testing.T) {
+		completedAt := at.Add(-time.Hour)
+		disbursement := &domain.Disbursement{Status: domain.DisbursementStatusManualReview, CompletedAt: &completedAt}
+
+		err := disbursement.TransitionTo(domain.DisbursementStatusCompleted, at)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusCompleted, disbursement.Status)
+		assert.Equal(t, &completedAt, disbursement.CompletedAt)
+		assert.Equal(t, domain.DisbursementStatusCompleted, disbursement.SettledStatus())
+	})
+
+	t.Run("NotAllowed", func(t *testing.T) {
+		disbursement := &domain.Disbursement{Status: domain.DisbursementStatusFailed}
+
+		err := disbursement.TransitionTo(domain.DisbursementStatusCompleted, at)
+		assert.ErrorIs(t, err, domErr.ErrInvalidDisbursementStatusTransition)
+		assert.Equal(t, domain.DisbursementStatusFailed, disbursement.Status)
+		assert.Nil(t, disbursement.CompletedAt)
+	})
+}
+
+func TestDisbursementPollPolicy_NextPollAt(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	policy := domain.DisbursementPollPolicy{Backoff: 30 * time.Second, MaxBackoff: 5 * time.Minute}
+
+	testCases := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{5, 5 * time.Minute},
+		{50, 5 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(strconv.Itoa(tc.attempts), func(t *testing.T) {
+			assert.Equal(t, now.Add(tc.expected), policy.NextPollAt(tc.attempts, now))
+		})
+	}
+}
+
+func TestDisbursementPollPolicy_ReviewDue(t *testing.T) {
+	createdAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	submittedAt := createdAt.Add(time.Hour)
+	policy := domain.DisbursementPollPolicy{ReviewDeadline: 24 * time.Hour}
+
+	t.Run("FromSubmission", func(t *testing.T) {
+		disbursement := &domain.Disbursement{CreatedAt: createdAt, SubmittedAt: &submittedAt}
+
+		assert.False(t, policy.ReviewDue(disbursement, submittedAt.Add(24*time.Hour-time.Second)))
+		assert.True(t, policy.ReviewDue(disbursement, submittedAt.Add(24*time.Hour)))
+	})
+
+	t.Run("FromCreationWithoutSubmission", func(t *testing.T) {
+		disbursement := &domain.Disbursement{CreatedAt: createdAt}
+
+		assert.True(t, policy.ReviewDue(disbursement, createdAt.Add(24*time.Hour)))
+	})
+}
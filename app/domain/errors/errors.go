@@ -4,8 +4,63 @@ import "errors"
 
 var (
 	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrInsufficientHold    = errors.New("held balance is lower than the amount to capture or release")
 	ErrInternalServerError = errors.New("internal server error")
 	ErrInvalidParameter    = errors.New("invalid parameter")
 	ErrUserNotFound        = errors.New("user not found")
 	ErrPartnerError        = errors.New("partner error")
+
+	ErrNoPayoutProvider = errors.New("no payout provider available for this disbursement")
+	ErrPayoutNotSent    = errors.New("payout request could not be sent")
+
+	ErrBalanceVersionConflict = errors.New("balance was changed concurrently, please retry")
+
+	ErrInvalidJournalEntry   = errors.New("journal entry must post a positive amount to one side of an account")
+	ErrUnbalancedJournal     = errors.New("journal debits and credits do not balance")
+	ErrLedgerAccountNotFound = errors.New("ledger account not found")
+
+	ErrJournalNotFound        = errors.New("journal not found")
+	ErrJournalAlreadyReversed = errors.New("journal has already been reversed")
+	ErrJournalNotReversible   = errors.New("a reversal cannot be reversed, post a correction instead")
+
+	ErrCurrencyMismatch    = errors.New("currencies do not match")
+	ErrMissingCurrency     = errors.New("currency is missing")
+	ErrWalletAlreadyExists = errors.New("user already has a wallet in this currency")
+
+	ErrSameCurrencyConversion  = errors.New("cannot convert a currency into itself")
+	ErrConvertedAmountTooLarge = errors.New("converted amount is too large")
+	ErrFXRateNotFound          = errors.New("no exchange rate for this currency pair")
+	ErrFXQuoteNotFound         = errors.New("fx quote not found")
+	ErrFXQuoteExpired          = errors.New("fx quote has expired, request a new one")
+	ErrFXQuoteAlreadyUsed      = errors.New("fx quote has already been used")
+
+	ErrAccountingPeriodNotFound      = errors.New("accounting period not found")
+	ErrAccountingPeriodAlreadyExists = errors.New("accounting period already exists")
+	ErrAccountingPeriodClosed        = errors.New("accounting period is already closed")
+	ErrAccountingPeriodNotEnded      = errors.New("accounting period has not ended yet")
+	ErrEarlierAccountingPeriodOpen   = errors.New("an earlier accounting period is still open")
+	ErrPostingPeriodClosed           = errors.New("value date falls in a closed accounting period")
+
+	ErrDisbursementNotFound                = errors.New("disbursement not found")
+	ErrInvalidDisbursementStatusTransition = errors.New("invalid disbursement status transition")
+	ErrDisbursementStatusConflict          = errors.New("disbursement status was changed concurrently")
+	ErrDisbursementPartnerMismatch         = errors.New("partner disbursement id does not match the disbursement")
+	ErrDisbursementProviderMismatch        = errors.New("disbursement was not sent with this provider")
+	ErrDisbursementUnderReview             = errors.New("disbursement is under manual review, settle it before reversing its journal")
+	ErrInvalidWebhookSignature             = errors.New("invalid webhook signature")
+
+	ErrUnauthorized = errors.New("missing or invalid admin key")
+
+	ErrReconciliationNotFound = errors.New("reconciliation not found")
+	ErrInvalidSettlementFile  = errors.New("invalid settlement file")
+
+	ErrDuplicateTopUpSourceReference = errors.New("duplicate top-up source reference")
+	ErrTopUpSourceReferenceConflict  = errors.New("source reference already used for a different top-up")
+
+	ErrSelfTransfer      = errors.New("cannot transfer to the same wallet")
+	ErrRecipientNotFound = errors.New("recipient not found")
+
+	ErrDuplicateIdempotencyKey  = errors.New("duplicate idempotency key")
+	ErrIdempotencyKeyConflict   = errors.New("idempotency key already used for a different request")
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
 )
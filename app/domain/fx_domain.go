@@ -0,0 +1,155 @@
+package domain
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+// DefaultFXQuoteTTL is how long a quote locks its rate when no other TTL is configured.
+const DefaultFXQuoteTTL = 30 * time.Second
+
+// currencyExponents lists the currencies whose minor unit is not a hundredth of the major
+// unit, see ISO 4217.
+var currencyExponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyExponent is the number of decimals between the major and the minor unit of currency.
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[currency]; ok {
+		return exponent
+	}
+
+	return 2
+}
+
+// FXRate is how many units of QuoteCurrency one unit of BaseCurrency buys, both in major
+// units. Rate is a decimal string, so rates are stored and compared without rounding.
+type FXRate struct {
+	BaseCurrency  string    `json:"base_currency" db:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency" db:"quote_currency"`
+	Rate          string    `json:"rate" db:"rate"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (f *FXRate) TableName() string {
+	return "fx_rates"
+}
+
+// Validate fails unless the rate is a positive decimal between two different currencies.
+func (f *FXRate) Validate() error {
+	if f.BaseCurrency == f.QuoteCurrency {
+		return errors.ErrSameCurrencyConversion
+	}
+
+	rate, err := f.RateValue()
+	if err != nil || rate.Sign() <= 0 {
+		return errors.ErrInvalidParameter
+	}
+
+	return nil
+}
+
+// RateValue returns Rate as a number. It only fails for rates that were never validated.
+func (f *FXRate) RateValue() (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(f.Rate)
+	if !ok {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	return rate, nil
+}
+
+// ConvertMoney converts amount at rate, in major units of to per major unit of the currency of
+// amount, rounding down to a whole minor unit of to. It fails with ErrConvertedAmountTooLarge
+// when the result does not fit in an int64 amount.
+func ConvertMoney(amount Money, rate *big.Rat, to string) (Money, error) {
+	converted := new(big.Rat).SetInt64(amount.Amount)
+	converted.Mul(converted, rate)
+	converted.Mul(converted, new(big.Rat).SetFrac(pow10(CurrencyExponent(to)), pow10(CurrencyExponent(amount.Currency))))
+
+	minorUnits := new(big.Int).Quo(converted.Num(), converted.Denom())
+	if !minorUnits.IsInt64() {
+		return Money{}, errors.ErrConvertedAmountTooLarge
+	}
+
+	return NewMoney(minorUnits.Int64(), to), nil
+}
+
+func pow10(exponent int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil)
+}
+
+type FXRateInput struct {
+	BaseCurrency  string `json:"base_currency" binding:"required,iso4217"`
+	QuoteCurrency string `json:"quote_currency" binding:"required,iso4217"`
+	Rate          string `json:"rate" binding:"required,numeric"`
+}
+
+// SetFXRatesRequest replaces the rates of the listed currency pairs; other pairs keep theirs.
+// The rates file has the same shape.
+type SetFXRatesRequest struct {
+	Rates []*FXRateInput `json:"rates" binding:"required,min=1,dive"`
+}
+
+// FXQuote locks the rate for converting FromAmount of FromCurrency into ToAmount of
+// ToCurrency until ExpiresAt. A quote can be used for a single conversion.
+type FXQuote struct {
+	ID           int64     `json:"id" db:"id"`
+	ReferenceID  string    `json:"reference_id" db:"reference_id"`
+	UserID       int64     `json:"user_id" db:"user_id"`
+	FromCurrency string    `json:"from_currency" db:"from_currency"`
+	FromAmount   int64     `json:"from_amount" db:"from_amount"`
+	ToCurrency   string    `json:"to_currency" db:"to_currency"`
+	ToAmount     int64     `json:"to_amount" db:"to_amount"`
+	Rate         string    `json:"rate" db:"rate"`
+	ExpiresAt    time.Time `json:"expires_at" db:"expires_at"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+func (q *FXQuote) TableName() string {
+	return "fx_quotes"
+}
+
+func (q *FXQuote) FromMoney() Money {
+	return NewMoney(q.FromAmount, q.FromCurrency)
+}
+
+func (q *FXQuote) ToMoney() Money {
+	return NewMoney(q.ToAmount, q.ToCurrency)
+}
+
+// IsExpired reports whether the rate of the quote is no longer locked at.
+func (q *FXQuote) IsExpired(at time.Time) bool {
+	return !at.Before(q.ExpiresAt)
+}
+
+// CreateFXQuoteRequest asks what Amount of FromCurrency converts into.
+type CreateFXQuoteRequest struct {
+	FromCurrency string `json:"from_currency" binding:"required,iso4217"`
+	ToCurrency   string `json:"to_currency" binding:"required,iso4217"`
+	Amount       int64  `json:"amount" binding:"required,gt=0"`
+}
+
+type FXRateRepository interface {
+	Upsert(ctx context.Context, rate *FXRate) error
+	GetAll(ctx context.Context) ([]*FXRate, error)
+	GetByPair(ctx context.Context, baseCurrency, quoteCurrency string) (*FXRate, error)
+}
+
+type FXQuoteRepository interface {
+	Create(ctx context.Context, quote *FXQuote) (*FXQuote, error)
+	GetByReferenceID(ctx context.Context, referenceID string) (*FXQuote, error)
+}
+
+type FXUsecase interface {
+	GetRates(ctx context.Context) ([]*FXRate, error)
+	SetRates(ctx context.Context, request *SetFXRatesRequest) ([]*FXRate, error)
+	LoadRatesFile(ctx context.Context, path string) error
+	CreateQuote(ctx context.Context, userID int64, request *CreateFXQuoteRequest) (*FXQuote, error)
+}
@@ -0,0 +1,64 @@
+package domain_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFXRate_Validate(t *testing.T) {
+	assert.NoError(t, (&domain.FXRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "16250.50"}).Validate())
+	assert.ErrorIs(t, (&domain.FXRate{BaseCurrency: "USD", QuoteCurrency: "USD", Rate: "1"}).Validate(), domErr.ErrSameCurrencyConversion)
+	assert.ErrorIs(t, (&domain.FXRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "0"}).Validate(), domErr.ErrInvalidParameter)
+	assert.ErrorIs(t, (&domain.FXRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "-2"}).Validate(), domErr.ErrInvalidParameter)
+	assert.ErrorIs(t, (&domain.FXRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "abc"}).Validate(), domErr.ErrInvalidParameter)
+}
+
+func TestConvertMoney(t *testing.T) {
+	rate := func(s string) *big.Rat {
+		r, _ := new(big.Rat).SetString(s)
+		return r
+	}
+
+	testCases := []struct {
+		name     string
+		amount   domain.Money
+		rate     string
+		to       string
+		expected domain.Money
+	}{
+		{"SameExponent", domain.NewMoney(1000, "USD"), "16250.50", "IDR", domain.NewMoney(16250500, "IDR")},
+		{"RoundsDown", domain.NewMoney(100, "IDR"), "0.00006153", "USD", domain.NewMoney(0, "USD")},
+		{"ToZeroDecimals", domain.NewMoney(1050, "USD"), "150", "JPY", domain.NewMoney(1575, "JPY")},
+		{"FromZeroDecimals", domain.NewMoney(1575, "JPY"), "0.0066", "USD", domain.NewMoney(1039, "USD")},
+		{"ToThreeDecimals", domain.NewMoney(100, "USD"), "0.307", "KWD", domain.NewMoney(307, "KWD")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			converted, err := domain.ConvertMoney(tc.amount, rate(tc.rate), tc.to)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, converted)
+		})
+	}
+
+	t.Run("TooLarge", func(t *testing.T) {
+		converted, err := domain.ConvertMoney(domain.NewMoney(math.MaxInt64, "USD"), rate("16250.50"), "IDR")
+		assert.ErrorIs(t, err, domErr.ErrConvertedAmountTooLarge)
+		assert.Equal(t, domain.Money{}, converted)
+	})
+}
+
+func TestFXQuote_IsExpired(t *testing.T) {
+	expiresAt := time.Date(2024, 6, 1, 10, 0, 30, 0, time.UTC)
+	quote := &domain.FXQuote{ExpiresAt: expiresAt}
+
+	assert.False(t, quote.IsExpired(expiresAt.Add(-time.Second)))
+	assert.True(t, quote.IsExpired(expiresAt))
+	assert.True(t, quote.IsExpired(expiresAt.Add(time.Second)))
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultIdempotencyKeyLockTimeout is how long a request holds its key when no other timeout
+// is configured. A key still in progress after that was left behind by a request that never
+// finished, and the next request with it takes it over.
+const DefaultIdempotencyKeyLockTimeout = 5 * time.Minute
+
+// IdempotencyKey is a key claimed by a request. Keys are only unique within their Scope, the
+// user the request was made for, so two users can use the same key.
+type IdempotencyKey struct {
+	Scope              string    `json:"scope" db:"scope"`
+	Key                string    `json:"key" db:"idempotency_key"`
+	RequestFingerprint string    `json:"request_fingerprint" db:"request_fingerprint"`
+	ResponseStatus     int       `json:"response_status" db:"response_status"`
+	ResponseBody       string    `json:"response_body" db:"response_body"`
+	LockedAt           time.Time `json:"locked_at" db:"locked_at"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (i *IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// IsCompleted reports whether the original request has finished and its response is stored.
+func (i *IdempotencyKey) IsCompleted() bool {
+	return i.ResponseStatus != 0
+}
+
+type IdempotencyKeyRepository interface {
+	Create(ctx context.Context, idempotencyKey *IdempotencyKey) error
+	GetByKey(ctx context.Context, scope, key string) (*IdempotencyKey, error)
+	// LockStaleByKey takes over key when it is still in progress and was locked before
+	// lockedBefore, ErrIdempotencyKeyInProgress otherwise.
+	LockStaleByKey(ctx context.Context, scope, key string, lockedBefore time.Time) error
+	UpdateResponseByKey(ctx context.Context, scope, key string, responseStatus int, responseBody string) error
+	DeleteByKey(ctx context.Context, scope, key string) error
+}
+
+type IdempotencyKeyUsecase interface {
+	Begin(ctx context.Context, scope, key, requestFingerprint string) (*IdempotencyKey, error)
+	Complete(ctx context.Context, scope, key string, responseStatus int, responseBody string) error
+	Release(ctx context.Context, scope, key string) error
+}
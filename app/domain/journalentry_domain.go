@@ -1,20 +1,330 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
 
 type JournalEntry struct {
-	ID              int64  `json:"id" db:"id"`
-	AccountID       string `json:"account_id" db:"account_id"`
-	TransactionName string `json:"transaction_name" db:"transaction_name"`
-	DebitAmount     int64  `json:"debit_amount" db:"debit_amount"`
-	CreditAmount    int64  `json:"credit_amount" db:"credit_amount"`
-	Folio           string `json:"folio" db:"folio"`
+	ID                 int64           `json:"id" db:"id"`
+	AccountID          string          `json:"account_id" db:"account_id"`
+	TransactionName    string          `json:"transaction_name" db:"transaction_name"`
+	DebitAmount        int64           `json:"debit_amount" db:"debit_amount"`
+	CreditAmount       int64           `json:"credit_amount" db:"credit_amount"`
+	Currency           string          `json:"currency" db:"currency"`
+	Folio              string          `json:"folio" db:"folio"`
+	TransactionGroupID string          `json:"transaction_group_id" db:"transaction_group_id"`
+	PostedAt           time.Time       `json:"posted_at" db:"posted_at"`
+	ValueDate          time.Time       `json:"value_date" db:"value_date"`
+	Metadata           JournalMetadata `json:"metadata" db:"metadata"`
+	PreviousHash       string          `json:"previous_hash" db:"previous_hash"`
+	Hash               string          `json:"hash" db:"hash"`
 }
 
 func (j *JournalEntry) TableName() string {
 	return "journal_entries"
 }
 
+// GenesisHash is the previous hash of the first journal entry.
+var GenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// journalEntryHashContent is everything the hash of a journal entry covers, in a fixed order.
+type journalEntryHashContent struct {
+	PreviousHash       string          `json:"previous_hash"`
+	AccountID          string          `json:"account_id"`
+	TransactionName    string          `json:"transaction_name"`
+	DebitAmount        int64           `json:"debit_amount"`
+	CreditAmount       int64           `json:"credit_amount"`
+	Currency           string          `json:"currency"`
+	Folio              string          `json:"folio"`
+	TransactionGroupID string          `json:"transaction_group_id"`
+	PostedAt           string          `json:"posted_at"`
+	ValueDate          string          `json:"value_date"`
+	Metadata           JournalMetadata `json:"metadata"`
+}
+
+// ComputeHash returns the SHA-256 of the contents of the entry and its previous hash, so
+// editing an entry changes its hash and deleting one breaks the link of the next entry.
+func (j *JournalEntry) ComputeHash() string {
+	metadata := j.Metadata
+	if metadata == nil {
+		metadata = JournalMetadata{}
+	}
+
+	content, _ := json.Marshal(journalEntryHashContent{
+		PreviousHash:       j.PreviousHash,
+		AccountID:          j.AccountID,
+		TransactionName:    j.TransactionName,
+		DebitAmount:        j.DebitAmount,
+		CreditAmount:       j.CreditAmount,
+		Currency:           j.Currency,
+		Folio:              j.Folio,
+		TransactionGroupID: j.TransactionGroupID,
+		PostedAt:           j.PostedAt.UTC().Format(time.RFC3339Nano),
+		ValueDate:          j.ValueDate.UTC().Format("2006-01-02"),
+		Metadata:           metadata,
+	})
+	sum := sha256.Sum256(content)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// JournalMetadata is free-form context of a journal transaction, such as the user or the
+// partner reference it was posted for. It is stored as a JSON object.
+type JournalMetadata map[string]string
+
+func (m JournalMetadata) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+
+	value, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(value), nil
+}
+
+func (m *JournalMetadata) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), m)
+	case []byte:
+		return json.Unmarshal(src, m)
+	default:
+		return fmt.Errorf("cannot scan %T into JournalMetadata", src)
+	}
+}
+
+// JournalTransaction is the set of journal entries of one business transaction. It is only
+// posted as a whole, and only when its debits and credits add up to the same amount.
+//
+// TransactionGroupID ties together the journals of one business operation, e.g. a
+// disbursement and its later reversal, while the folio identifies a single journal.
+// PostedAt is when the journal was written to the ledger and ValueDate the day it takes
+// effect on the balances; both are set when the journal is posted unless given before.
+type JournalTransaction struct {
+	Folio              string
+	TransactionName    string
+	TransactionGroupID string
+	Currency           string
+	PostedAt           time.Time
+	ValueDate          time.Time
+	Metadata           JournalMetadata
+	Entries            []*JournalEntry
+}
+
+// NewJournalTransaction starts a journal in the default currency, grouped under its own folio.
+func NewJournalTransaction(folio, transactionName string) *JournalTransaction {
+	return &JournalTransaction{
+		Folio:              folio,
+		TransactionName:    transactionName,
+		TransactionGroupID: folio,
+		Currency:           DefaultCurrency,
+	}
+}
+
+// InGroup sets the transaction group the journal belongs to.
+func (j *JournalTransaction) InGroup(transactionGroupID string) *JournalTransaction {
+	j.TransactionGroupID = transactionGroupID
+	return j
+}
+
+// InCurrency sets the currency of the journal. All its accounts must be in that currency.
+func (j *JournalTransaction) InCurrency(currency string) *JournalTransaction {
+	j.Currency = currency
+	return j
+}
+
+// WithMetadata adds key and value to the metadata of the journal.
+func (j *JournalTransaction) WithMetadata(key, value string) *JournalTransaction {
+	if j.Metadata == nil {
+		j.Metadata = JournalMetadata{}
+	}
+	j.Metadata[key] = value
+	return j
+}
+
+// Debit adds an entry debiting amount to accountID.
+func (j *JournalTransaction) Debit(accountID string, amount int64) *JournalTransaction {
+	j.Entries = append(j.Entries, &JournalEntry{
+		AccountID:       accountID,
+		TransactionName: j.TransactionName,
+		DebitAmount:     amount,
+		Folio:           j.Folio,
+	})
+	return j
+}
+
+// Credit adds an entry crediting amount to accountID.
+func (j *JournalTransaction) Credit(accountID string, amount int64) *JournalTransaction {
+	j.Entries = append(j.Entries, &JournalEntry{
+		AccountID:       accountID,
+		TransactionName: j.TransactionName,
+		CreditAmount:    amount,
+		Folio:           j.Folio,
+	})
+	return j
+}
+
+// DebitMoney adds an entry debiting amount to accountID in the currency of amount, for
+// journals that post in more than one currency.
+func (j *JournalTransaction) DebitMoney(accountID string, amount Money) *JournalTransaction {
+	j.Debit(accountID, amount.Amount)
+	j.Entries[len(j.Entries)-1].Currency = amount.Currency
+	return j
+}
+
+// CreditMoney adds an entry crediting amount to accountID in the currency of amount.
+func (j *JournalTransaction) CreditMoney(accountID string, amount Money) *JournalTransaction {
+	j.Credit(accountID, amount.Amount)
+	j.Entries[len(j.Entries)-1].Currency = amount.Currency
+	return j
+}
+
+// Stamp sets the posting time of the journal to postedAt unless it already has one, defaults
+// the value date to the day of posting, and copies the journal fields onto every entry.
+func (j *JournalTransaction) Stamp(postedAt time.Time) {
+	if j.PostedAt.IsZero() {
+		j.PostedAt = postedAt.UTC()
+	}
+	if j.ValueDate.IsZero() {
+		j.ValueDate = j.PostedAt.UTC().Truncate(24 * time.Hour)
+	}
+
+	for _, entry := range j.Entries {
+		if entry.Currency == "" {
+			entry.Currency = j.Currency
+		}
+		entry.TransactionGroupID = j.TransactionGroupID
+		entry.PostedAt = j.PostedAt
+		entry.ValueDate = j.ValueDate
+		entry.Metadata = j.Metadata
+	}
+}
+
+// Validate checks that every entry posts a positive amount to exactly one side of an account,
+// and that the debits and credits of the transaction balance in every currency.
+func (j *JournalTransaction) Validate() error {
+	if j.Folio == "" || j.TransactionGroupID == "" || len(j.Entries) < 2 {
+		return errors.ErrInvalidJournalEntry
+	}
+
+	balances := map[string]int64{}
+	for _, entry := range j.Entries {
+		if entry.AccountID == "" || entry.Folio != j.Folio || entry.DebitAmount < 0 || entry.CreditAmount < 0 {
+			return errors.ErrInvalidJournalEntry
+		}
+		if (entry.DebitAmount == 0) == (entry.CreditAmount == 0) {
+			return errors.ErrInvalidJournalEntry
+		}
+
+		currency := entry.Currency
+		if currency == "" {
+			currency = j.Currency
+		}
+		if currency == "" {
+			return errors.ErrInvalidJournalEntry
+		}
+
+		balances[currency] += entry.DebitAmount - entry.CreditAmount
+	}
+
+	for _, balance := range balances {
+		if balance != 0 {
+			return errors.ErrUnbalancedJournal
+		}
+	}
+
+	return nil
+}
+
+// AccountIDs returns every account the transaction posts to, once.
+func (j *JournalTransaction) AccountIDs() []string {
+	var accountIDs []string
+	seen := map[string]bool{}
+	for _, entry := range j.Entries {
+		if !seen[entry.AccountID] {
+			seen[entry.AccountID] = true
+			accountIDs = append(accountIDs, entry.AccountID)
+		}
+	}
+
+	return accountIDs
+}
+
+const (
+	DefaultJournalEntryPageLimit = 50
+	MaxJournalEntryPageLimit     = 200
+)
+
+// JournalEntryFilter selects journal entries in posting order. Empty fields do not filter,
+// From and To bound the posting time, From inclusive and To exclusive. Cursor is the ID of
+// the last entry of the previous page, the first page starts at 0. On a user statement
+// Currency picks the wallet, the one in the default currency when it is empty.
+type JournalEntryFilter struct {
+	AccountID          string    `form:"account_id"`
+	Currency           string    `form:"currency" binding:"omitempty,iso4217"`
+	Folio              string    `form:"folio"`
+	TransactionGroupID string    `form:"transaction_group_id"`
+	TransactionName    string    `form:"transaction_name"`
+	From               time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To                 time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Cursor             int64     `form:"cursor" binding:"gte=0"`
+	Limit              int       `form:"limit" binding:"gte=0,lte=200"`
+}
+
+// JournalEntryPage is one page of journal entries. NextCursor is 0 on the last page.
+type JournalEntryPage struct {
+	Entries    []*JournalEntry `json:"entries"`
+	NextCursor int64           `json:"next_cursor,omitempty"`
+}
+
+// StatementLine is a posting to a wallet account, signed from the point of view of the user:
+// credits add to the wallet and debits take from it.
+type StatementLine struct {
+	EntryID         int64     `json:"entry_id"`
+	Folio           string    `json:"folio"`
+	TransactionName string    `json:"transaction_name"`
+	Amount          int64     `json:"amount"`
+	RunningBalance  int64     `json:"running_balance"`
+	PostedAt        time.Time `json:"posted_at"`
+	ValueDate       time.Time `json:"value_date"`
+}
+
+// UserStatement is one page of the postings to a user's wallet. OpeningBalance is the balance
+// of the wallet before the first line of the page.
+type UserStatement struct {
+	UserID         int64            `json:"user_id"`
+	AccountID      string           `json:"account_id"`
+	OpeningBalance int64            `json:"opening_balance"`
+	ClosingBalance int64            `json:"closing_balance"`
+	Lines          []*StatementLine `json:"lines"`
+	NextCursor     int64            `json:"next_cursor,omitempty"`
+}
+
 type JournalEntryRepository interface {
-	Create(ctx context.Context, journalEntry *JournalEntry) (*JournalEntry, error)
+	CreateTransaction(ctx context.Context, journal *JournalTransaction) error
+	GetEntries(ctx context.Context, filter *JournalEntryFilter) ([]*JournalEntry, error)
+	GetAccountBalanceBefore(ctx context.Context, accountID string, entryID int64) (*LedgerAccountBalance, error)
+	GetAccountBalance(ctx context.Context, accountID string) (*LedgerAccountBalance, error)
+	GetAccountBalances(ctx context.Context) ([]*LedgerAccountBalance, error)
+	GetAccountBalancesForPeriod(ctx context.Context, from, to time.Time) ([]*LedgerAccountBalance, error)
+}
+
+type JournalEntryUsecase interface {
+	GetJournalEntries(ctx context.Context, filter *JournalEntryFilter) (*JournalEntryPage, error)
+	GetUserStatement(ctx context.Context, userID int64, filter *JournalEntryFilter) (*UserStatement, error)
 }
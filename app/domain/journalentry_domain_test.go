@@ -0,0 +1,195 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournalTransaction_Validate(t *testing.T) {
+	testCases := []struct {
+		name        string
+		journal     *domain.JournalTransaction
+		expectedErr error
+	}{
+		{
+			name: "Balanced",
+			journal: domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+				Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 500).
+				Credit(domain.BankClearingAccountID, 500),
+		},
+		{
+			name: "BalancedWithSplitLegs",
+			journal: domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+				Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 500).
+				Credit(domain.BankClearingAccountID, 450).
+				Credit("fee-income", 50),
+		},
+		{
+			name: "Unbalanced",
+			journal: domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+				Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 500).
+				Credit(domain.BankClearingAccountID, 400),
+			expectedErr: domErr.ErrUnbalancedJournal,
+		},
+		{
+			name: "SingleEntry",
+			journal: domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+				Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 500),
+			expectedErr: domErr.ErrInvalidJournalEntry,
+		},
+		{
+			name: "ZeroAmount",
+			journal: domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+				Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 0).
+				Credit(domain.BankClearingAccountID, 0),
+			expectedErr: domErr.ErrInvalidJournalEntry,
+		},
+		{
+			name: "NegativeAmount",
+			journal: domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+				Debit(domain.WalletAccountID(1, domain.DefaultCurrency), -500).
+				Credit(domain.BankClearingAccountID, -500),
+			expectedErr: domErr.ErrInvalidJournalEntry,
+		},
+		{
+			name: "MissingAccount",
+			journal: domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+				Debit("", 500).
+				Credit(domain.BankClearingAccountID, 500),
+			expectedErr: domErr.ErrInvalidJournalEntry,
+		},
+		{
+			name: "MissingFolio",
+			journal: domain.NewJournalTransaction("", "Balance disbursement").
+				Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 500).
+				Credit(domain.BankClearingAccountID, 500),
+			expectedErr: domErr.ErrInvalidJournalEntry,
+		},
+		{
+			name: "BothSidesOnOneEntry",
+			journal: &domain.JournalTransaction{
+				Folio:              "WLT-1",
+				TransactionGroupID: "WLT-1",
+				Currency:           domain.DefaultCurrency,
+				Entries: []*domain.JournalEntry{
+					{AccountID: domain.WalletAccountID(1, domain.DefaultCurrency), DebitAmount: 500, CreditAmount: 500, Folio: "WLT-1"},
+					{AccountID: domain.BankClearingAccountID, DebitAmount: 100, Folio: "WLT-1"},
+					{AccountID: domain.FundingClearingAccountID, CreditAmount: 100, Folio: "WLT-1"},
+				},
+			},
+			expectedErr: domErr.ErrInvalidJournalEntry,
+		},
+		{
+			name: "UnbalancedPerCurrency",
+			journal: &domain.JournalTransaction{
+				Folio:              "WLT-1",
+				TransactionGroupID: "WLT-1",
+				Currency:           domain.DefaultCurrency,
+				Entries: []*domain.JournalEntry{
+					{AccountID: domain.WalletAccountID(1, domain.DefaultCurrency), DebitAmount: 500, Folio: "WLT-1"},
+					{AccountID: domain.BankClearingAccountID, CreditAmount: 500, Currency: "USD", Folio: "WLT-1"},
+				},
+			},
+			expectedErr: domErr.ErrUnbalancedJournal,
+		},
+		{
+			name: "MissingCurrency",
+			journal: &domain.JournalTransaction{
+				Folio:              "WLT-1",
+				TransactionGroupID: "WLT-1",
+				Entries: []*domain.JournalEntry{
+					{AccountID: domain.WalletAccountID(1, domain.DefaultCurrency), DebitAmount: 500, Folio: "WLT-1"},
+					{AccountID: domain.BankClearingAccountID, CreditAmount: 500, Folio: "WLT-1"},
+				},
+			},
+			expectedErr: domErr.ErrInvalidJournalEntry,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.journal.Validate()
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestJournalTransaction_Stamp(t *testing.T) {
+	postedAt := time.Date(2026, 3, 4, 23, 30, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+	journal := domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+		InGroup("WLT-0").
+		Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 500).
+		Credit(domain.BankClearingAccountID, 500).
+		WithMetadata("user_id", "1")
+	journal.Stamp(postedAt)
+
+	assert.Equal(t, postedAt.UTC(), journal.PostedAt)
+	assert.Equal(t, time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), journal.ValueDate)
+	for _, entry := range journal.Entries {
+		assert.Equal(t, domain.DefaultCurrency, entry.Currency)
+		assert.Equal(t, "WLT-0", entry.TransactionGroupID)
+		assert.Equal(t, journal.PostedAt, entry.PostedAt)
+		assert.Equal(t, journal.ValueDate, entry.ValueDate)
+		assert.Equal(t, domain.JournalMetadata{"user_id": "1"}, entry.Metadata)
+	}
+
+	// a journal keeps the posting time it was given
+	journal.Stamp(postedAt.Add(time.Hour))
+	assert.Equal(t, postedAt.UTC(), journal.PostedAt)
+}
+
+func TestJournalMetadata_ValueAndScan(t *testing.T) {
+	value, err := domain.JournalMetadata{"user_id": "1"}.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"user_id":"1"}`, value)
+
+	value, err = domain.JournalMetadata(nil).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", value)
+
+	var metadata domain.JournalMetadata
+	assert.NoError(t, metadata.Scan([]byte(`{"source_reference":"VA-1"}`)))
+	assert.Equal(t, domain.JournalMetadata{"source_reference": "VA-1"}, metadata)
+	assert.Error(t, metadata.Scan(42))
+}
+
+func TestJournalEntry_ComputeHash(t *testing.T) {
+	newEntry := func() *domain.JournalEntry {
+		return &domain.JournalEntry{
+			AccountID:          domain.WalletAccountID(1, domain.DefaultCurrency),
+			TransactionName:    "Balance disbursement",
+			DebitAmount:        500,
+			Currency:           domain.DefaultCurrency,
+			Folio:              "WLT-1",
+			TransactionGroupID: "WLT-1",
+			PostedAt:           time.Date(2026, 3, 4, 5, 6, 7, 8, time.UTC),
+			ValueDate:          time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+			PreviousHash:       domain.GenesisHash,
+		}
+	}
+	hash := newEntry().ComputeHash()
+	assert.Len(t, hash, 64)
+
+	// the same contents read back from the database hash the same
+	readBack := newEntry()
+	readBack.PostedAt = readBack.PostedAt.In(time.FixedZone("WIB", 7*60*60))
+	readBack.Metadata = domain.JournalMetadata{}
+	assert.Equal(t, hash, readBack.ComputeHash())
+
+	edited := newEntry()
+	edited.DebitAmount = 5000
+	assert.NotEqual(t, hash, edited.ComputeHash())
+
+	relinked := newEntry()
+	relinked.PreviousHash = hash
+	assert.NotEqual(t, hash, relinked.ComputeHash())
+}
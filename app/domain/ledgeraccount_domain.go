@@ -0,0 +1,242 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCurrency is the currency of wallets and ledger accounts that are not given another one.
+const DefaultCurrency = "IDR"
+
+type LedgerAccountType string
+
+const (
+	LedgerAccountTypeAsset     LedgerAccountType = "ASSET"
+	LedgerAccountTypeLiability LedgerAccountType = "LIABILITY"
+	LedgerAccountTypeEquity    LedgerAccountType = "EQUITY"
+	LedgerAccountTypeRevenue   LedgerAccountType = "REVENUE"
+	LedgerAccountTypeExpense   LedgerAccountType = "EXPENSE"
+)
+
+// NormalBalance is the side on which postings increase an account.
+type NormalBalance string
+
+const (
+	NormalBalanceDebit  NormalBalance = "DEBIT"
+	NormalBalanceCredit NormalBalance = "CREDIT"
+)
+
+// NormalBalance returns the side that increases accounts of this type.
+func (t LedgerAccountType) NormalBalance() NormalBalance {
+	switch t {
+	case LedgerAccountTypeAsset, LedgerAccountTypeExpense:
+		return NormalBalanceDebit
+	default:
+		return NormalBalanceCredit
+	}
+}
+
+// Ledger accounts that are not owned by a wallet. Every currency has its own set of them, see
+// CurrencyAccountID.
+const (
+	// BankClearingAccountID holds disbursed money until the bank settles it to the user's bank account.
+	BankClearingAccountID = "bank-clearing"
+	// FundingClearingAccountID holds top-ups until the money arrives from the payment channel.
+	FundingClearingAccountID = "funding-clearing"
+	// FeeIncomeAccountID collects the fees charged to users.
+	FeeIncomeAccountID = "fee-income"
+	// SuspenseAccountID parks amounts that cannot be attributed yet, until they are investigated.
+	SuspenseAccountID = "suspense"
+	// OpeningBalanceAccountID is the counterpart of balances a wallet already had when it was created.
+	OpeningBalanceAccountID = "opening-balance"
+	// FXPositionAccountID takes the other side of currency conversions. Its balances across
+	// currencies are the open currency position of the platform.
+	FXPositionAccountID = "fx-position"
+)
+
+const (
+	walletAccountIDPrefix      = "wallet:"
+	currencyAccountIDSeparator = ":"
+)
+
+// CurrencyAccountID returns the id of the account in currency that accountID stands for.
+// Accounts in the default currency keep their plain id, the others get the currency appended,
+// e.g. bank-clearing:USD.
+func CurrencyAccountID(accountID, currency string) string {
+	if currency == "" || currency == DefaultCurrency {
+		return accountID
+	}
+
+	return accountID + currencyAccountIDSeparator + currency
+}
+
+// WalletAccountID is the ledger account of a user's wallet in currency.
+func WalletAccountID(userID int64, currency string) string {
+	return CurrencyAccountID(walletAccountIDPrefix+strconv.FormatInt(userID, 10), currency)
+}
+
+// WalletUserID returns the user whose wallet accountID is, and false for other accounts.
+func WalletUserID(accountID string) (int64, bool) {
+	if !strings.HasPrefix(accountID, walletAccountIDPrefix) {
+		return 0, false
+	}
+
+	id, _, _ := strings.Cut(strings.TrimPrefix(accountID, walletAccountIDPrefix), currencyAccountIDSeparator)
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return userID, true
+}
+
+// LedgerAccount is an account of the chart of accounts. Journal entries can only be posted
+// to registered accounts. Wallet accounts are owned by their user, system accounts by nobody.
+type LedgerAccount struct {
+	ID            string            `json:"id" db:"id"`
+	Name          string            `json:"name" db:"name"`
+	Type          LedgerAccountType `json:"type" db:"type"`
+	NormalBalance NormalBalance     `json:"normal_balance" db:"normal_balance"`
+	Currency      string            `json:"currency" db:"currency"`
+	OwnerUserID   *int64            `json:"owner_user_id,omitempty" db:"owner_user_id"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+}
+
+func (l *LedgerAccount) TableName() string {
+	return "ledger_accounts"
+}
+
+func NewLedgerAccount(id, name string, accountType LedgerAccountType, currency string) *LedgerAccount {
+	return &LedgerAccount{
+		ID:            id,
+		Name:          name,
+		Type:          accountType,
+		NormalBalance: accountType.NormalBalance(),
+		Currency:      currency,
+	}
+}
+
+// NewWalletLedgerAccount returns the account of a user's wallet in currency. The platform owes
+// wallet balances to its users, so wallets are liabilities.
+func NewWalletLedgerAccount(userID int64, currency, username string) *LedgerAccount {
+	account := NewLedgerAccount(WalletAccountID(userID, currency), "Wallet "+username, LedgerAccountTypeLiability, currency)
+	account.OwnerUserID = &userID
+	return account
+}
+
+// SystemLedgerAccounts is the part of the chart of accounts in currency that exists
+// independently of any user. The accounts of the default currency are created at startup,
+// those of another currency with the first wallet in it.
+func SystemLedgerAccounts(currency string) []*LedgerAccount {
+	return []*LedgerAccount{
+		NewLedgerAccount(CurrencyAccountID(BankClearingAccountID, currency), "Bank clearing", LedgerAccountTypeAsset, currency),
+		NewLedgerAccount(CurrencyAccountID(FundingClearingAccountID, currency), "Funding clearing", LedgerAccountTypeAsset, currency),
+		NewLedgerAccount(CurrencyAccountID(FeeIncomeAccountID, currency), "Fee income", LedgerAccountTypeRevenue, currency),
+		NewLedgerAccount(CurrencyAccountID(SuspenseAccountID, currency), "Suspense", LedgerAccountTypeAsset, currency),
+		NewLedgerAccount(CurrencyAccountID(OpeningBalanceAccountID, currency), "Opening balance", LedgerAccountTypeEquity, currency),
+		NewLedgerAccount(CurrencyAccountID(FXPositionAccountID, currency), "FX position", LedgerAccountTypeEquity, currency),
+	}
+}
+
+// LedgerAccountBalance is the sum of all postings to a ledger account.
+type LedgerAccountBalance struct {
+	AccountID     string            `json:"account_id" db:"account_id"`
+	Name          string            `json:"name" db:"name"`
+	Type          LedgerAccountType `json:"type" db:"type"`
+	NormalBalance NormalBalance     `json:"normal_balance" db:"normal_balance"`
+	Currency      string            `json:"currency" db:"currency"`
+	TotalDebit    int64             `json:"total_debit" db:"total_debit"`
+	TotalCredit   int64             `json:"total_credit" db:"total_credit"`
+	Balance       int64             `json:"balance" db:"-"`
+}
+
+// CalculateBalance sets Balance to the difference of the postings, counted positive on the
+// normal balance side of the account.
+func (b *LedgerAccountBalance) CalculateBalance() {
+	if b.NormalBalance == NormalBalanceDebit {
+		b.Balance = b.TotalDebit - b.TotalCredit
+		return
+	}
+
+	b.Balance = b.TotalCredit - b.TotalDebit
+}
+
+// BalanceMismatch is a wallet whose stored balance differs from the balance of its ledger account.
+type BalanceMismatch struct {
+	ID             int64  `json:"-" db:"id"`
+	VerificationID int64  `json:"-" db:"verification_id"`
+	UserID         int64  `json:"user_id" db:"user_id"`
+	AccountID      string `json:"account_id" db:"account_id"`
+	WalletBalance  int64  `json:"wallet_balance" db:"wallet_balance"`
+	LedgerBalance  int64  `json:"ledger_balance" db:"ledger_balance"`
+	Difference     int64  `json:"difference" db:"difference"`
+}
+
+func (m *BalanceMismatch) TableName() string {
+	return "balance_mismatches"
+}
+
+// BalanceVerificationReport is the result of comparing every wallet with its ledger account.
+// Reports that found a mismatch are stored, so drift found by the worker is not only logged.
+type BalanceVerificationReport struct {
+	ID             int64              `json:"id,omitempty" db:"id"`
+	CheckedAt      time.Time          `json:"checked_at" db:"checked_at"`
+	WalletsChecked int                `json:"wallets_checked" db:"wallets_checked"`
+	Mismatches     []*BalanceMismatch `json:"mismatches" db:"-"`
+}
+
+func (r *BalanceVerificationReport) TableName() string {
+	return "balance_verifications"
+}
+
+// Reasons the hash chain of the journal breaks at an entry.
+const (
+	// HashChainBreakPreviousHash means the entry does not link to the entry before it, because
+	// an entry in between was deleted or inserted.
+	HashChainBreakPreviousHash = "PREVIOUS_HASH_MISMATCH"
+	// HashChainBreakHash means the contents of the entry were changed after it was posted.
+	HashChainBreakHash = "HASH_MISMATCH"
+	// HashChainBreakCheckpoint means the chain does not contain the hash of an entry it had
+	// before, because entries were removed from its end.
+	HashChainBreakCheckpoint = "CHECKPOINT_MISSING"
+)
+
+// HashChainBreak is the first journal entry at which the hash chain does not hold. A missing
+// checkpoint has no entry, its EntryID is zero.
+type HashChainBreak struct {
+	EntryID      int64  `json:"entry_id"`
+	Reason       string `json:"reason"`
+	ExpectedHash string `json:"expected_hash"`
+	ActualHash   string `json:"actual_hash"`
+}
+
+// HashChainReport is the result of walking the hash chain of the journal. LastHash is the
+// hash of the last entry checked. The chain alone cannot show that entries were removed from
+// its end; record LastHash outside the database and pass it back as the checkpoint of a later
+// walk to detect that.
+type HashChainReport struct {
+	CheckedAt      time.Time       `json:"checked_at"`
+	EntriesChecked int             `json:"entries_checked"`
+	Valid          bool            `json:"valid"`
+	LastHash       string          `json:"last_hash"`
+	FirstBreak     *HashChainBreak `json:"first_break,omitempty"`
+}
+
+type LedgerAccountRepository interface {
+	Register(ctx context.Context, account *LedgerAccount) error
+}
+
+type BalanceVerificationRepository interface {
+	Create(ctx context.Context, report *BalanceVerificationReport) (*BalanceVerificationReport, error)
+	GetAll(ctx context.Context) ([]*BalanceVerificationReport, error)
+	GetMismatches(ctx context.Context, verificationID int64) ([]*BalanceMismatch, error)
+}
+
+type LedgerUsecase interface {
+	GetAccountBalance(ctx context.Context, accountID string) (*LedgerAccountBalance, error)
+	VerifyWalletBalances(ctx context.Context) (*BalanceVerificationReport, error)
+	GetBalanceVerifications(ctx context.Context) ([]*BalanceVerificationReport, error)
+	VerifyHashChain(ctx context.Context, checkpoint string) (*HashChainReport, error)
+}
@@ -0,0 +1,75 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerAccountType_NormalBalance(t *testing.T) {
+	testCases := []struct {
+		accountType domain.LedgerAccountType
+		expected    domain.NormalBalance
+	}{
+		{domain.LedgerAccountTypeAsset, domain.NormalBalanceDebit},
+		{domain.LedgerAccountTypeExpense, domain.NormalBalanceDebit},
+		{domain.LedgerAccountTypeLiability, domain.NormalBalanceCredit},
+		{domain.LedgerAccountTypeEquity, domain.NormalBalanceCredit},
+		{domain.LedgerAccountTypeRevenue, domain.NormalBalanceCredit},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.accountType), func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.accountType.NormalBalance())
+		})
+	}
+}
+
+func TestNewWalletLedgerAccount(t *testing.T) {
+	account := domain.NewWalletLedgerAccount(7, domain.DefaultCurrency, "andy123")
+
+	assert.Equal(t, "wallet:7", account.ID)
+	assert.Equal(t, domain.LedgerAccountTypeLiability, account.Type)
+	assert.Equal(t, domain.NormalBalanceCredit, account.NormalBalance)
+	assert.Equal(t, domain.DefaultCurrency, account.Currency)
+	assert.Equal(t, int64(7), *account.OwnerUserID)
+
+	account = domain.NewWalletLedgerAccount(7, "USD", "andy123")
+	assert.Equal(t, "wallet:7:USD", account.ID)
+	assert.Equal(t, "USD", account.Currency)
+}
+
+func TestSystemLedgerAccounts(t *testing.T) {
+	for _, account := range domain.SystemLedgerAccounts("USD") {
+		assert.Equal(t, "USD", account.Currency)
+		assert.Contains(t, account.ID, ":USD")
+	}
+
+	assert.Equal(t, domain.BankClearingAccountID, domain.SystemLedgerAccounts(domain.DefaultCurrency)[0].ID)
+}
+
+func TestLedgerAccountBalance_CalculateBalance(t *testing.T) {
+	asset := &domain.LedgerAccountBalance{NormalBalance: domain.NormalBalanceDebit, TotalDebit: 1000, TotalCredit: 400}
+	asset.CalculateBalance()
+	assert.Equal(t, int64(600), asset.Balance)
+
+	liability := &domain.LedgerAccountBalance{NormalBalance: domain.NormalBalanceCredit, TotalDebit: 1000, TotalCredit: 400}
+	liability.CalculateBalance()
+	assert.Equal(t, int64(-600), liability.Balance)
+}
+
+func TestWalletUserID(t *testing.T) {
+	userID, isWallet := domain.WalletUserID(domain.WalletAccountID(7, domain.DefaultCurrency))
+	assert.True(t, isWallet)
+	assert.Equal(t, int64(7), userID)
+
+	userID, isWallet = domain.WalletUserID(domain.WalletAccountID(7, "USD"))
+	assert.True(t, isWallet)
+	assert.Equal(t, int64(7), userID)
+
+	for _, accountID := range []string{domain.BankClearingAccountID, "wallet:", "wallet:abc"} {
+		_, isWallet := domain.WalletUserID(accountID)
+		assert.False(t, isWallet, accountID)
+	}
+}
@@ -0,0 +1,149 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/krisdioles/ppr-wallet/app/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccountingPeriodUsecase is an autogenerated mock type for the AccountingPeriodUsecase type
+type AccountingPeriodUsecase struct {
+	mock.Mock
+}
+
+// ClosePeriod provides a mock function with given fields: ctx, period
+func (_m *AccountingPeriodUsecase) ClosePeriod(ctx context.Context, period string) (*domain.AccountingPeriod, error) {
+	ret := _m.Called(ctx, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClosePeriod")
+	}
+
+	var r0 *domain.AccountingPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.AccountingPeriod, error)); ok {
+		return rf(ctx, period)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.AccountingPeriod); ok {
+		r0 = rf(ctx, period)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccountingPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, period)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPeriod provides a mock function with given fields: ctx, period
+func (_m *AccountingPeriodUsecase) GetPeriod(ctx context.Context, period string) (*domain.AccountingPeriod, error) {
+	ret := _m.Called(ctx, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPeriod")
+	}
+
+	var r0 *domain.AccountingPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.AccountingPeriod, error)); ok {
+		return rf(ctx, period)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.AccountingPeriod); ok {
+		r0 = rf(ctx, period)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccountingPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, period)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPeriods provides a mock function with given fields: ctx
+func (_m *AccountingPeriodUsecase) GetPeriods(ctx context.Context) ([]*domain.AccountingPeriod, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPeriods")
+	}
+
+	var r0 []*domain.AccountingPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.AccountingPeriod, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.AccountingPeriod); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.AccountingPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenPeriod provides a mock function with given fields: ctx, request
+func (_m *AccountingPeriodUsecase) OpenPeriod(ctx context.Context, request *domain.OpenAccountingPeriodRequest) (*domain.AccountingPeriod, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenPeriod")
+	}
+
+	var r0 *domain.AccountingPeriod
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.OpenAccountingPeriodRequest) (*domain.AccountingPeriod, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.OpenAccountingPeriodRequest) *domain.AccountingPeriod); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccountingPeriod)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.OpenAccountingPeriodRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAccountingPeriodUsecase creates a new instance of AccountingPeriodUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccountingPeriodUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccountingPeriodUsecase {
+	mock := &AccountingPeriodUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
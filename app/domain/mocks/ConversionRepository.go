@@ -0,0 +1,59 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/krisdioles/ppr-wallet/app/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ConversionRepository is an autogenerated mock type for the ConversionRepository type
+type ConversionRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, conversion
+func (_m *ConversionRepository) Create(ctx context.Context, conversion *domain.Conversion) (*domain.Conversion, error) {
+	ret := _m.Called(ctx, conversion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *domain.Conversion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Conversion) (*domain.Conversion, error)); ok {
+		return rf(ctx, conversion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Conversion) *domain.Conversion); ok {
+		r0 = rf(ctx, conversion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Conversion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Conversion) error); ok {
+		r1 = rf(ctx, conversion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewConversionRepository creates a new instance of ConversionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewConversionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ConversionRepository {
+	mock := &ConversionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/krisdioles/ppr-wallet/app/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ConversionUsecase is an autogenerated mock type for the ConversionUsecase type
+type ConversionUsecase struct {
+	mock.Mock
+}
+
+// ConvertCurrency provides a mock function with given fields: ctx, userID, request
+func (_m *ConversionUsecase) ConvertCurrency(ctx context.Context, userID int64, request *domain.ConvertCurrencyRequest) (*domain.Conversion, error) {
+	ret := _m.Called(ctx, userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConvertCurrency")
+	}
+
+	var r0 *domain.Conversion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *domain.ConvertCurrencyRequest) (*domain.Conversion, error)); ok {
+		return rf(ctx, userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *domain.ConvertCurrencyRequest) *domain.Conversion); ok {
+		r0 = rf(ctx, userID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Conversion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *domain.ConvertCurrencyRequest) error); ok {
+		r1 = rf(ctx, userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewConversionUsecase creates a new instance of ConversionUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewConversionUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ConversionUsecase {
+	mock := &ConversionUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/krisdioles/ppr-wallet/app/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FXRateRepository is an autogenerated mock type for the FXRateRepository type
+type FXRateRepository struct {
+	mock.Mock
+}
+
+// GetAll provides a mock function with given fields: ctx
+func (_m *FXRateRepository) GetAll(ctx context.Context) ([]*domain.FXRate, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []*domain.FXRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.FXRate, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.FXRate); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.FXRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByPair provides a mock function with given fields: ctx, baseCurrency, quoteCurrency
+func (_m *FXRateRepository) GetByPair(ctx context.Context, baseCurrency string, quoteCurrency string) (*domain.FXRate, error) {
+	ret := _m.Called(ctx, baseCurrency, quoteCurrency)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByPair")
+	}
+
+	var r0 *domain.FXRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.FXRate, error)); ok {
+		return rf(ctx, baseCurrency, quoteCurrency)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.FXRate); ok {
+		r0 = rf(ctx, baseCurrency, quoteCurrency)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.FXRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, baseCurrency, quoteCurrency)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Upsert provides a mock function with given fields: ctx, rate
+func (_m *FXRateRepository) Upsert(ctx context.Context, rate *domain.FXRate) error {
+	ret := _m.Called(ctx, rate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upsert")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.FXRate) error); ok {
+		r0 = rf(ctx, rate)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewFXRateRepository creates a new instance of FXRateRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFXRateRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FXRateRepository {
+	mock := &FXRateRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,137 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/krisdioles/ppr-wallet/app/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// FXUsecase is an autogenerated mock type for the FXUsecase type
+type FXUsecase struct {
+	mock.Mock
+}
+
+// CreateQuote provides a mock function with given fields: ctx, userID, request
+func (_m *FXUsecase) CreateQuote(ctx context.Context, userID int64, request *domain.CreateFXQuoteRequest) (*domain.FXQuote, error) {
+	ret := _m.Called(ctx, userID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateQuote")
+	}
+
+	var r0 *domain.FXQuote
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *domain.CreateFXQuoteRequest) (*domain.FXQuote, error)); ok {
+		return rf(ctx, userID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *domain.CreateFXQuoteRequest) *domain.FXQuote); ok {
+		r0 = rf(ctx, userID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.FXQuote)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *domain.CreateFXQuoteRequest) error); ok {
+		r1 = rf(ctx, userID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRates provides a mock function with given fields: ctx
+func (_m *FXUsecase) GetRates(ctx context.Context) ([]*domain.FXRate, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRates")
+	}
+
+	var r0 []*domain.FXRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.FXRate, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.FXRate); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.FXRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoadRatesFile provides a mock function with given fields: ctx, path
+func (_m *FXUsecase) LoadRatesFile(ctx context.Context, path string) error {
+	ret := _m.Called(ctx, path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoadRatesFile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, path)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRates provides a mock function with given fields: ctx, request
+func (_m *FXUsecase) SetRates(ctx context.Context, request *domain.SetFXRatesRequest) ([]*domain.FXRate, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRates")
+	}
+
+	var r0 []*domain.FXRate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.SetFXRatesRequest) ([]*domain.FXRate, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.SetFXRatesRequest) []*domain.FXRate); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.FXRate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.SetFXRatesRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewFXUsecase creates a new instance of FXUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFXUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FXUsecase {
+	mock := &FXUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
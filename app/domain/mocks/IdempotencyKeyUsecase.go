@@ -0,0 +1,95 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/krisdioles/ppr-wallet/app/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IdempotencyKeyUsecase is an autogenerated mock type for the IdempotencyKeyUsecase type
+type IdempotencyKeyUsecase struct {
+	mock.Mock
+}
+
+// Begin provides a mock function with given fields: ctx, scope, key, requestFingerprint
+func (_m *IdempotencyKeyUsecase) Begin(ctx context.Context, scope string, key string, requestFingerprint string) (*domain.IdempotencyKey, error) {
+	ret := _m.Called(ctx, scope, key, requestFingerprint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Begin")
+	}
+
+	var r0 *domain.IdempotencyKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*domain.IdempotencyKey, error)); ok {
+		return rf(ctx, scope, key, requestFingerprint)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *domain.IdempotencyKey); ok {
+		r0 = rf(ctx, scope, key, requestFingerprint)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.IdempotencyKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, scope, key, requestFingerprint)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Complete provides a mock function with given fields: ctx, scope, key, responseStatus, responseBody
+func (_m *IdempotencyKeyUsecase) Complete(ctx context.Context, scope string, key string, responseStatus int, responseBody string) error {
+	ret := _m.Called(ctx, scope, key, responseStatus, responseBody)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int, string) error); ok {
+		r0 = rf(ctx, scope, key, responseStatus, responseBody)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Release provides a mock function with given fields: ctx, scope, key
+func (_m *IdempotencyKeyUsecase) Release(ctx context.Context, scope string, key string) error {
+	ret := _m.Called(ctx, scope, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, scope, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewIdempotencyKeyUsecase creates a new instance of IdempotencyKeyUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdempotencyKeyUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdempotencyKeyUsecase {
+	mock := &IdempotencyKeyUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/krisdioles/ppr-wallet/app/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TransferUsecase is an autogenerated mock type for the TransferUsecase type
+type TransferUsecase struct {
+	mock.Mock
+}
+
+// TransferBalance provides a mock function with given fields: ctx, fromUserID, request
+func (_m *TransferUsecase) TransferBalance(ctx context.Context, fromUserID int64, request *domain.TransferBalanceRequest) (*domain.Transfer, error) {
+	ret := _m.Called(ctx, fromUserID, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferBalance")
+	}
+
+	var r0 *domain.Transfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *domain.TransferBalanceRequest) (*domain.Transfer, error)); ok {
+		return rf(ctx, fromUserID, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, *domain.TransferBalanceRequest) *domain.Transfer); ok {
+		r0 = rf(ctx, fromUserID, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Transfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, *domain.TransferBalanceRequest) error); ok {
+		r1 = rf(ctx, fromUserID, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTransferUsecase creates a new instance of TransferUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTransferUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TransferUsecase {
+	mock := &TransferUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"strconv"
+
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+// Money is an amount in the minor units of an ISO 4217 currency, e.g. cents for USD. Amounts
+// in different currencies are never added up or compared with each other.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// NewMoney returns amount in currency, or in the default currency when currency is empty.
+func NewMoney(amount int64, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	return Money{
+		Amount:   amount,
+		Currency: currency,
+	}
+}
+
+// CheckPositive fails for amounts of zero or less, which are never moved.
+func (m Money) CheckPositive() error {
+	if m.Amount <= 0 {
+		return errors.ErrInvalidParameter
+	}
+
+	return nil
+}
+
+// CheckCurrency fails when m is not in currency.
+func (m Money) CheckCurrency(currency string) error {
+	if m.Currency != currency {
+		return errors.ErrCurrencyMismatch
+	}
+
+	return nil
+}
+
+func (m Money) String() string {
+	return strconv.FormatInt(m.Amount, 10) + " " + m.Currency
+}
@@ -0,0 +1,27 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMoney(t *testing.T) {
+	assert.Equal(t, domain.Money{Amount: 500, Currency: domain.DefaultCurrency}, domain.NewMoney(500, ""))
+	assert.Equal(t, domain.Money{Amount: 500, Currency: "USD"}, domain.NewMoney(500, "USD"))
+}
+
+func TestMoney_Checks(t *testing.T) {
+	assert.NoError(t, domain.NewMoney(1, "USD").CheckPositive())
+	assert.ErrorIs(t, domain.NewMoney(0, "USD").CheckPositive(), domErr.ErrInvalidParameter)
+	assert.ErrorIs(t, domain.NewMoney(-1, "USD").CheckPositive(), domErr.ErrInvalidParameter)
+
+	assert.NoError(t, domain.NewMoney(1, "USD").CheckCurrency("USD"))
+	assert.ErrorIs(t, domain.NewMoney(1, "USD").CheckCurrency("IDR"), domErr.ErrCurrencyMismatch)
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "500 USD", domain.NewMoney(500, "USD").String())
+}
@@ -0,0 +1,237 @@
+package domain
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type ReconciliationLineStatus string
+
+const (
+	ReconciliationLineStatusMatched          ReconciliationLineStatus = "MATCHED"
+	ReconciliationLineStatusMissingOnOurSide ReconciliationLineStatus = "MISSING_ON_OUR_SIDE"
+	ReconciliationLineStatusMissingAtBank    ReconciliationLineStatus = "MISSING_AT_BANK"
+	ReconciliationLineStatusAmountMismatch   ReconciliationLineStatus = "AMOUNT_MISMATCH"
+)
+
+// SettlementLine is one line of a settlement file from the bank. LineNo is its line in the
+// file, the header being line 1.
+type SettlementLine struct {
+	LineNo    int
+	Reference string
+	Amount    int64
+	Currency  string
+}
+
+// ParseSettlementFile reads a CSV settlement file. The header names the columns, in any order:
+// reference and amount are required, currency is optional and defaults to the default
+// currency. Amounts are in minor units like everywhere else in the API.
+func ParseSettlementFile(file io.Reader) ([]*SettlementLine, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read the header: %v", errors.ErrInvalidSettlementFile, err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	referenceColumn, hasReference := columns["reference"]
+	amountColumn, hasAmount := columns["amount"]
+	currencyColumn, hasCurrency := columns["currency"]
+	if !hasReference || !hasAmount {
+		return nil, fmt.Errorf("%w: the header needs a reference and an amount column", errors.ErrInvalidSettlementFile)
+	}
+
+	var lines []*SettlementLine
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", errors.ErrInvalidSettlementFile, err)
+		}
+		lineNo, _ := reader.FieldPos(0)
+
+		reference := strings.TrimSpace(record[referenceColumn])
+		if reference == "" {
+			return nil, fmt.Errorf("%w: line %d has no reference", errors.ErrInvalidSettlementFile, lineNo)
+		}
+
+		amount, err := strconv.ParseInt(strings.TrimSpace(record[amountColumn]), 10, 64)
+		if err != nil || amount <= 0 {
+			return nil, fmt.Errorf("%w: line %d has no positive whole amount", errors.ErrInvalidSettlementFile, lineNo)
+		}
+
+		currency := DefaultCurrency
+		if hasCurrency && strings.TrimSpace(record[currencyColumn]) != "" {
+			currency = strings.ToUpper(strings.TrimSpace(record[currencyColumn]))
+		}
+
+		lines = append(lines, &SettlementLine{
+			LineNo:    lineNo,
+			Reference: reference,
+			Amount:    amount,
+			Currency:  currency,
+		})
+	}
+
+	return lines, nil
+}
+
+// ReconciliationRecord is what we recorded for a settlement line: the journal it was posted
+// with and the amount that journal moved.
+type ReconciliationRecord struct {
+	Folio    string
+	Amount   int64
+	Currency string
+}
+
+// ReconciliationLine is the outcome of reconciling one settlement line, or one of our records
+// the file has no line for. Lines missing at the bank have LineNo 0 and BankAmount 0, lines
+// missing on our side have OurAmount 0.
+type ReconciliationLine struct {
+	ID               int64                    `json:"id" db:"id"`
+	ReconciliationID int64                    `json:"reconciliation_id" db:"reconciliation_id"`
+	LineNo           int                      `json:"line_no" db:"line_no"`
+	Reference        string                   `json:"reference" db:"reference"`
+	Currency         string                   `json:"currency" db:"currency"`
+	BankAmount       int64                    `json:"bank_amount" db:"bank_amount"`
+	OurAmount        int64                    `json:"our_amount" db:"our_amount"`
+	Status           ReconciliationLineStatus `json:"status" db:"status"`
+	Folio            string                   `json:"folio" db:"folio"`
+	Note             string                   `json:"note" db:"note"`
+}
+
+func (l *ReconciliationLine) TableName() string {
+	return "reconciliation_lines"
+}
+
+// MatchSettlementLine classifies line against the record we have for it, nil when there is
+// none.
+func MatchSettlementLine(line *SettlementLine, record *ReconciliationRecord) *ReconciliationLine {
+	reconciliationLine := &ReconciliationLine{
+		LineNo:     line.LineNo,
+		Reference:  line.Reference,
+		Currency:   line.Currency,
+		BankAmount: line.Amount,
+		Status:     ReconciliationLineStatusMissingOnOurSide,
+	}
+	if record == nil {
+		return reconciliationLine
+	}
+
+	reconciliationLine.Folio = record.Folio
+	reconciliationLine.OurAmount = record.Amount
+	switch {
+	case record.Currency != line.Currency:
+		reconciliationLine.Status = ReconciliationLineStatusAmountMismatch
+		reconciliationLine.Note = fmt.Sprintf("recorded in %s", record.Currency)
+	case record.Amount != line.Amount:
+		reconciliationLine.Status = ReconciliationLineStatusAmountMismatch
+	default:
+		reconciliationLine.Status = ReconciliationLineStatusMatched
+	}
+
+	return reconciliationLine
+}
+
+// NewMissingAtBankLine is the line of a record the settlement file should have had.
+func NewMissingAtBankLine(reference string, record *ReconciliationRecord) *ReconciliationLine {
+	return &ReconciliationLine{
+		Reference: reference,
+		Currency:  record.Currency,
+		OurAmount: record.Amount,
+		Status:    ReconciliationLineStatusMissingAtBank,
+		Folio:     record.Folio,
+	}
+}
+
+// Reconciliation is the stored result of matching a settlement file of the bank against our
+// records, with the number of lines in every status.
+type Reconciliation struct {
+	ID               int64                 `json:"id" db:"id"`
+	FileName         string                `json:"file_name" db:"file_name"`
+	Provider         string                `json:"provider" db:"provider"`
+	SettlementDate   time.Time             `json:"settlement_date" db:"settlement_date"`
+	Matched          int                   `json:"matched" db:"matched"`
+	MissingOnOurSide int                   `json:"missing_on_our_side" db:"missing_on_our_side"`
+	MissingAtBank    int                   `json:"missing_at_bank" db:"missing_at_bank"`
+	AmountMismatch   int                   `json:"amount_mismatch" db:"amount_mismatch"`
+	CreatedAt        time.Time             `json:"created_at" db:"created_at"`
+	Lines            []*ReconciliationLine `json:"lines,omitempty" db:"-"`
+}
+
+func (r *Reconciliation) TableName() string {
+	return "reconciliations"
+}
+
+// AddLine adds line to the reconciliation and counts it.
+func (r *Reconciliation) AddLine(line *ReconciliationLine) {
+	r.Lines = append(r.Lines, line)
+
+	switch line.Status {
+	case ReconciliationLineStatusMatched:
+		r.Matched++
+	case ReconciliationLineStatusMissingOnOurSide:
+		r.MissingOnOurSide++
+	case ReconciliationLineStatusMissingAtBank:
+		r.MissingAtBank++
+	case ReconciliationLineStatusAmountMismatch:
+		r.AmountMismatch++
+	}
+}
+
+func (r *Reconciliation) Records() [][]string {
+	records := [][]string{{"line_no", "reference", "currency", "bank_amount", "our_amount", "status", "folio", "note"}}
+	for _, line := range r.Lines {
+		records = append(records, []string{
+			strconv.Itoa(line.LineNo),
+			line.Reference,
+			line.Currency,
+			strconv.FormatInt(line.BankAmount, 10),
+			strconv.FormatInt(line.OurAmount, 10),
+			string(line.Status),
+			line.Folio,
+			line.Note,
+		})
+	}
+
+	return records
+}
+
+// ReconcileSettlementRequest names the day a settlement file covers and, optionally, the
+// payout provider it comes from. Disbursements completed on that day with that provider are
+// expected in the file.
+type ReconcileSettlementRequest struct {
+	SettlementDate time.Time `form:"settlement_date" time_format:"2006-01-02" time_utc:"1" binding:"required"`
+	Provider       string    `form:"provider"`
+}
+
+type ReconciliationReportRequest struct {
+	Format ReportFormat `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+type ReconciliationRepository interface {
+	Create(ctx context.Context, reconciliation *Reconciliation) (*Reconciliation, error)
+	GetByID(ctx context.Context, id int64) (*Reconciliation, error)
+	GetAll(ctx context.Context) ([]*Reconciliation, error)
+	GetLines(ctx context.Context, reconciliationID int64) ([]*ReconciliationLine, error)
+}
+
+type ReconciliationUsecase interface {
+	ReconcileSettlement(ctx context.Context, request *ReconcileSettlementRequest, fileName string, file io.Reader) (*Reconciliation, error)
+	GetReconciliations(ctx context.Context) ([]*Reconciliation, error)
+	GetReconciliation(ctx context.Context, id int64) (*Reconciliation, error)
+}
@@ -0,0 +1,113 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSettlementFile(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		file := "Amount,Reference,Currency,Description\n" +
+			"300, WLT-1 ,idr,payout\n" +
+			"\n" +
+			"25,bank-2,,payout\n"
+
+		lines, err := domain.ParseSettlementFile(strings.NewReader(file))
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.SettlementLine{
+			{LineNo: 2, Reference: "WLT-1", Amount: 300, Currency: "IDR"},
+			{LineNo: 4, Reference: "bank-2", Amount: 25, Currency: domain.DefaultCurrency},
+		}, lines)
+	})
+
+	testCases := []struct {
+		name string
+		file string
+	}{
+		{"Empty", ""},
+		{"NoAmountColumn", "reference,currency\nWLT-1,IDR\n"},
+		{"NoReference", "reference,amount\n,300\n"},
+		{"AmountNotWhole", "reference,amount\nWLT-1,3.50\n"},
+		{"AmountNotPositive", "reference,amount\nWLT-1,0\n"},
+		{"MissingField", "reference,amount\nWLT-1\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := domain.ParseSettlementFile(strings.NewReader(tc.file))
+			assert.ErrorIs(t, err, domErr.ErrInvalidSettlementFile)
+		})
+	}
+}
+
+func TestMatchSettlementLine(t *testing.T) {
+	line := &domain.SettlementLine{LineNo: 2, Reference: "bank-1", Amount: 300, Currency: "IDR"}
+
+	testCases := []struct {
+		name     string
+		record   *domain.ReconciliationRecord
+		expected *domain.ReconciliationLine
+	}{
+		{
+			name:   "Matched",
+			record: &domain.ReconciliationRecord{Folio: "WLT-1", Amount: 300, Currency: "IDR"},
+			expected: &domain.ReconciliationLine{
+				LineNo: 2, Reference: "bank-1", Currency: "IDR", BankAmount: 300, OurAmount: 300,
+				Status: domain.ReconciliationLineStatusMatched, Folio: "WLT-1",
+			},
+		},
+		{
+			name:   "AmountMismatch",
+			record: &domain.ReconciliationRecord{Folio: "WLT-1", Amount: 250, Currency: "IDR"},
+			expected: &domain.ReconciliationLine{
+				LineNo: 2, Reference: "bank-1", Currency: "IDR", BankAmount: 300, OurAmount: 250,
+				Status: domain.ReconciliationLineStatusAmountMismatch, Folio: "WLT-1",
+			},
+		},
+		{
+			name:   "CurrencyMismatch",
+			record: &domain.ReconciliationRecord{Folio: "WLT-1", Amount: 300, Currency: "USD"},
+			expected: &domain.ReconciliationLine{
+				LineNo: 2, Reference: "bank-1", Currency: "IDR", BankAmount: 300, OurAmount: 300,
+				Status: domain.ReconciliationLineStatusAmountMismatch, Folio: "WLT-1", Note: "recorded in USD",
+			},
+		},
+		{
+			name: "MissingOnOurSide",
+			expected: &domain.ReconciliationLine{
+				LineNo: 2, Reference: "bank-1", Currency: "IDR", BankAmount: 300,
+				Status: domain.ReconciliationLineStatusMissingOnOurSide,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, domain.MatchSettlementLine(line, tc.record))
+		})
+	}
+}
+
+func TestReconciliation_AddLine(t *testing.T) {
+	reconciliation := &domain.Reconciliation{}
+	for _, status := range []domain.ReconciliationLineStatus{
+		domain.ReconciliationLineStatusMatched,
+		domain.ReconciliationLineStatusMatched,
+		domain.ReconciliationLineStatusMissingOnOurSide,
+		domain.ReconciliationLineStatusAmountMismatch,
+	} {
+		reconciliation.AddLine(&domain.ReconciliationLine{Status: status})
+	}
+	reconciliation.AddLine(domain.NewMissingAtBankLine("WLT-3", &domain.ReconciliationRecord{Folio: "WLT-3", Amount: 100, Currency: "IDR"}))
+
+	assert.Len(t, reconciliation.Lines, 5)
+	assert.Equal(t, 2, reconciliation.Matched)
+	assert.Equal(t, 1, reconciliation.MissingOnOurSide)
+	assert.Equal(t, 1, reconciliation.AmountMismatch)
+	assert.Equal(t, 1, reconciliation.MissingAtBank)
+	assert.Equal(t, []string{"0", "WLT-3", "IDR", "0", "100", "MISSING_AT_BANK", "WLT-3", ""}, reconciliation.Records()[5])
+}
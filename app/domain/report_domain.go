@@ -0,0 +1,255 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// ReportDateFormat is the format of the dates reports are requested for.
+const ReportDateFormat = "2006-01-02"
+
+type ReportFormat string
+
+const (
+	ReportFormatJSON ReportFormat = "json"
+	ReportFormatCSV  ReportFormat = "csv"
+)
+
+// ReportRequest selects the dates and the currency of a report. Dates are whole days in UTC:
+// AsOf and To include the postings valued on that day. A report only covers the accounts in
+// Currency, the default currency when it is empty, so amounts in different currencies are
+// never added up.
+type ReportRequest struct {
+	AsOf     time.Time    `form:"as_of" time_format:"2006-01-02" time_utc:"1"`
+	From     time.Time    `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To       time.Time    `form:"to" time_format:"2006-01-02" time_utc:"1"`
+	Currency string       `form:"currency" binding:"omitempty,iso4217"`
+	Format   ReportFormat `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+// TrialBalanceLine is the total postings to one account, with its balance on the side it falls.
+type TrialBalanceLine struct {
+	AccountID     string            `json:"account_id"`
+	Name          string            `json:"name"`
+	Type          LedgerAccountType `json:"type"`
+	TotalDebit    int64             `json:"total_debit"`
+	TotalCredit   int64             `json:"total_credit"`
+	DebitBalance  int64             `json:"debit_balance"`
+	CreditBalance int64             `json:"credit_balance"`
+}
+
+// TrialBalance lists every account in one currency as of the end of a day. The debit and
+// credit balances of a ledger in which every journal balances add up to the same amount.
+type TrialBalance struct {
+	AsOf               time.Time           `json:"as_of"`
+	Currency           string              `json:"currency"`
+	Lines              []*TrialBalanceLine `json:"lines"`
+	TotalDebitBalance  int64               `json:"total_debit_balance"`
+	TotalCreditBalance int64               `json:"total_credit_balance"`
+	Balanced           bool                `json:"balanced"`
+}
+
+func NewTrialBalance(asOf time.Time, currency string, accountBalances []*LedgerAccountBalance) *TrialBalance {
+	trialBalance := &TrialBalance{
+		AsOf:     asOf,
+		Currency: currency,
+		Lines:    []*TrialBalanceLine{},
+	}
+
+	for _, accountBalance := range accountBalancesIn(currency, accountBalances) {
+		line := &TrialBalanceLine{
+			AccountID:   accountBalance.AccountID,
+			Name:        accountBalance.Name,
+			Type:        accountBalance.Type,
+			TotalDebit:  accountBalance.TotalDebit,
+			TotalCredit: accountBalance.TotalCredit,
+		}
+		if difference := accountBalance.TotalDebit - accountBalance.TotalCredit; difference > 0 {
+			line.DebitBalance = difference
+		} else {
+			line.CreditBalance = -difference
+		}
+
+		trialBalance.Lines = append(trialBalance.Lines, line)
+		trialBalance.TotalDebitBalance += line.DebitBalance
+		trialBalance.TotalCreditBalance += line.CreditBalance
+	}
+	trialBalance.Balanced = trialBalance.TotalDebitBalance == trialBalance.TotalCreditBalance
+
+	return trialBalance
+}
+
+func (t *TrialBalance) Records() [][]string {
+	records := [][]string{{"account_id", "name", "type", "total_debit", "total_credit", "debit_balance", "credit_balance"}}
+	for _, line := range t.Lines {
+		records = append(records, []string{
+			line.AccountID,
+			line.Name,
+			string(line.Type),
+			strconv.FormatInt(line.TotalDebit, 10),
+			strconv.FormatInt(line.TotalCredit, 10),
+			strconv.FormatInt(line.DebitBalance, 10),
+			strconv.FormatInt(line.CreditBalance, 10),
+		})
+	}
+	records = append(records, []string{"", "Total", "", "", "", strconv.FormatInt(t.TotalDebitBalance, 10), strconv.FormatInt(t.TotalCreditBalance, 10)})
+
+	return records
+}
+
+// ReportLine is the balance of one account, counted positive on its normal balance side.
+type ReportLine struct {
+	AccountID string `json:"account_id"`
+	Name      string `json:"name"`
+	Balance   int64  `json:"balance"`
+}
+
+// ReportSection groups the accounts of one type.
+type ReportSection struct {
+	Type  LedgerAccountType `json:"type"`
+	Lines []*ReportLine     `json:"lines"`
+	Total int64             `json:"total"`
+}
+
+// newReportSection collects the accounts of accountType, leaving out accounts without postings.
+// accountBalances have to be in one currency.
+func newReportSection(accountType LedgerAccountType, accountBalances []*LedgerAccountBalance) *ReportSection {
+	section := &ReportSection{
+		Type:  accountType,
+		Lines: []*ReportLine{},
+	}
+
+	for _, accountBalance := range accountBalances {
+		if accountBalance.Type != accountType || accountBalance.TotalDebit == 0 && accountBalance.TotalCredit == 0 {
+			continue
+		}
+
+		section.Lines = append(section.Lines, &ReportLine{
+			AccountID: accountBalance.AccountID,
+			Name:      accountBalance.Name,
+			Balance:   accountBalance.Balance,
+		})
+		section.Total += accountBalance.Balance
+	}
+
+	return section
+}
+
+func (s *ReportSection) records() [][]string {
+	var records [][]string
+	for _, line := range s.Lines {
+		records = append(records, []string{string(s.Type), line.AccountID, line.Name, strconv.FormatInt(line.Balance, 10)})
+	}
+	records = append(records, []string{string(s.Type), "", "Total", strconv.FormatInt(s.Total, 10)})
+
+	return records
+}
+
+// BalanceSheet is the financial position in one currency as of the end of a day. The revenue
+// and expenses that were not closed into an equity account yet are shown as current earnings,
+// so assets equal liabilities plus equity.
+type BalanceSheet struct {
+	AsOf                      time.Time      `json:"as_of"`
+	Currency                  string         `json:"currency"`
+	Assets                    *ReportSection `json:"assets"`
+	Liabilities               *ReportSection `json:"liabilities"`
+	Equity                    *ReportSection `json:"equity"`
+	CurrentEarnings           int64          `json:"current_earnings"`
+	TotalLiabilitiesAndEquity int64          `json:"total_liabilities_and_equity"`
+	Balanced                  bool           `json:"balanced"`
+}
+
+func NewBalanceSheet(asOf time.Time, currency string, accountBalances []*LedgerAccountBalance) *BalanceSheet {
+	accountBalances = accountBalancesIn(currency, accountBalances)
+	balanceSheet := &BalanceSheet{
+		AsOf:            asOf,
+		Currency:        currency,
+		Assets:          newReportSection(LedgerAccountTypeAsset, accountBalances),
+		Liabilities:     newReportSection(LedgerAccountTypeLiability, accountBalances),
+		Equity:          newReportSection(LedgerAccountTypeEquity, accountBalances),
+		CurrentEarnings: netIncome(accountBalances),
+	}
+	balanceSheet.TotalLiabilitiesAndEquity = balanceSheet.Liabilities.Total + balanceSheet.Equity.Total + balanceSheet.CurrentEarnings
+	balanceSheet.Balanced = balanceSheet.Assets.Total == balanceSheet.TotalLiabilitiesAndEquity
+
+	return balanceSheet
+}
+
+func (b *BalanceSheet) Records() [][]string {
+	records := [][]string{{"section", "account_id", "name", "balance"}}
+	records = append(records, b.Assets.records()...)
+	records = append(records, b.Liabilities.records()...)
+	records = append(records, b.Equity.records()...)
+	records = append(records,
+		[]string{string(LedgerAccountTypeEquity), "", "Current earnings", strconv.FormatInt(b.CurrentEarnings, 10)},
+		[]string{"", "", "Total liabilities and equity", strconv.FormatInt(b.TotalLiabilitiesAndEquity, 10)},
+	)
+
+	return records
+}
+
+// IncomeStatement is the revenue and expenses in one currency valued within a period of whole
+// days.
+type IncomeStatement struct {
+	From      time.Time      `json:"from"`
+	To        time.Time      `json:"to"`
+	Currency  string         `json:"currency"`
+	Revenue   *ReportSection `json:"revenue"`
+	Expenses  *ReportSection `json:"expenses"`
+	NetIncome int64          `json:"net_income"`
+}
+
+func NewIncomeStatement(from, to time.Time, currency string, accountBalances []*LedgerAccountBalance) *IncomeStatement {
+	accountBalances = accountBalancesIn(currency, accountBalances)
+	return &IncomeStatement{
+		From:      from,
+		To:        to,
+		Currency:  currency,
+		Revenue:   newReportSection(LedgerAccountTypeRevenue, accountBalances),
+		Expenses:  newReportSection(LedgerAccountTypeExpense, accountBalances),
+		NetIncome: netIncome(accountBalances),
+	}
+}
+
+func (i *IncomeStatement) Records() [][]string {
+	records := [][]string{{"section", "account_id", "name", "balance"}}
+	records = append(records, i.Revenue.records()...)
+	records = append(records, i.Expenses.records()...)
+	records = append(records, []string{"", "", "Net income", strconv.FormatInt(i.NetIncome, 10)})
+
+	return records
+}
+
+// netIncome is revenue minus expenses. accountBalances have to be in one currency.
+func netIncome(accountBalances []*LedgerAccountBalance) int64 {
+	var netIncome int64
+	for _, accountBalance := range accountBalances {
+		switch accountBalance.Type {
+		case LedgerAccountTypeRevenue:
+			netIncome += accountBalance.Balance
+		case LedgerAccountTypeExpense:
+			netIncome -= accountBalance.Balance
+		}
+	}
+
+	return netIncome
+}
+
+// accountBalancesIn returns the balances of the accounts in currency.
+func accountBalancesIn(currency string, accountBalances []*LedgerAccountBalance) []*LedgerAccountBalance {
+	var balances []*LedgerAccountBalance
+	for _, accountBalance := range accountBalances {
+		if accountBalance.Currency == currency {
+			balances = append(balances, accountBalance)
+		}
+	}
+
+	return balances
+}
+
+type ReportUsecase interface {
+	GetTrialBalance(ctx context.Context, asOf time.Time, currency string) (*TrialBalance, error)
+	GetBalanceSheet(ctx context.Context, asOf time.Time, currency string) (*BalanceSheet, error)
+	GetIncomeStatement(ctx context.Context, from, to time.Time, currency string) (*IncomeStatement, error)
+}
@@ -0,0 +1,132 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+// reportAccountBalances is a ledger in which a wallet was opened with 1000, topped up with
+// 500 and charged a fee of 100 from it, and a bank fee of 20 was paid from bank clearing.
+func reportAccountBalances() []*domain.LedgerAccountBalance {
+	accountBalances := []*domain.LedgerAccountBalance{
+		{AccountID: domain.BankClearingAccountID, Name: "Bank clearing", Type: domain.LedgerAccountTypeAsset, NormalBalance: domain.NormalBalanceDebit, TotalCredit: 20},
+		{AccountID: domain.FundingClearingAccountID, Name: "Funding clearing", Type: domain.LedgerAccountTypeAsset, NormalBalance: domain.NormalBalanceDebit, TotalDebit: 500},
+		{AccountID: domain.SuspenseAccountID, Name: "Suspense", Type: domain.LedgerAccountTypeAsset, NormalBalance: domain.NormalBalanceDebit},
+		{AccountID: "wallet:1", Name: "Wallet andy123", Type: domain.LedgerAccountTypeLiability, NormalBalance: domain.NormalBalanceCredit, TotalDebit: 100, TotalCredit: 1500},
+		{AccountID: domain.OpeningBalanceAccountID, Name: "Opening balance", Type: domain.LedgerAccountTypeEquity, NormalBalance: domain.NormalBalanceCredit, TotalDebit: 1000},
+		{AccountID: domain.FeeIncomeAccountID, Name: "Fee income", Type: domain.LedgerAccountTypeRevenue, NormalBalance: domain.NormalBalanceCredit, TotalCredit: 100},
+		{AccountID: "bank-fees", Name: "Bank fees", Type: domain.LedgerAccountTypeExpense, NormalBalance: domain.NormalBalanceDebit, TotalDebit: 20},
+	}
+	for _, accountBalance := range accountBalances {
+		accountBalance.Currency = domain.DefaultCurrency
+		accountBalance.CalculateBalance()
+	}
+
+	return accountBalances
+}
+
+func TestNewTrialBalance(t *testing.T) {
+	asOf := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	trialBalance := domain.NewTrialBalance(asOf, domain.DefaultCurrency, reportAccountBalances())
+
+	assert.Len(t, trialBalance.Lines, 7)
+	assert.Equal(t, int64(20), trialBalance.Lines[0].CreditBalance)
+	assert.Equal(t, int64(1400), trialBalance.Lines[3].CreditBalance)
+	assert.Equal(t, int64(1000), trialBalance.Lines[4].DebitBalance)
+	assert.Equal(t, int64(1520), trialBalance.TotalDebitBalance)
+	assert.Equal(t, int64(1520), trialBalance.TotalCreditBalance)
+	assert.True(t, trialBalance.Balanced)
+
+	records := trialBalance.Records()
+	assert.Len(t, records, 9)
+	assert.Equal(t, []string{"account_id", "name", "type", "total_debit", "total_credit", "debit_balance", "credit_balance"}, records[0])
+	assert.Equal(t, []string{"wallet:1", "Wallet andy123", "LIABILITY", "100", "1500", "0", "1400"}, records[4])
+	assert.Equal(t, []string{"", "Total", "", "", "", "1520", "1520"}, records[8])
+}
+
+func TestNewBalanceSheet(t *testing.T) {
+	balanceSheet := domain.NewBalanceSheet(time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), domain.DefaultCurrency, reportAccountBalances())
+
+	// accounts without postings are left out
+	assert.Len(t, balanceSheet.Assets.Lines, 2)
+	assert.Equal(t, int64(480), balanceSheet.Assets.Total)
+	assert.Equal(t, int64(1400), balanceSheet.Liabilities.Total)
+	assert.Equal(t, int64(-1000), balanceSheet.Equity.Total)
+	assert.Equal(t, int64(80), balanceSheet.CurrentEarnings)
+	assert.Equal(t, int64(480), balanceSheet.TotalLiabilitiesAndEquity)
+	assert.True(t, balanceSheet.Balanced)
+
+	records := balanceSheet.Records()
+	assert.Equal(t, []string{"section", "account_id", "name", "balance"}, records[0])
+	assert.Equal(t, []string{"", "", "Total liabilities and equity", "480"}, records[len(records)-1])
+}
+
+func TestNewIncomeStatement(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	incomeStatement := domain.NewIncomeStatement(from, to, domain.DefaultCurrency, reportAccountBalances())
+
+	assert.Equal(t, []*domain.ReportLine{{AccountID: domain.FeeIncomeAccountID, Name: "Fee income", Balance: 100}}, incomeStatement.Revenue.Lines)
+	assert.Equal(t, int64(20), incomeStatement.Expenses.Total)
+	assert.Equal(t, int64(80), incomeStatement.NetIncome)
+	assert.Equal(t, []string{"", "", "Net income", "80"}, incomeStatement.Records()[5])
+}
+
+func TestReports_MixedCurrencies(t *testing.T) {
+	asOf := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	// a USD wallet opened with 30 that was charged a fee of 2
+	accountBalances := reportAccountBalances()
+	for _, accountBalance := range []*domain.LedgerAccountBalance{
+		{AccountID: "wallet:2:USD", Name: "Wallet brandy345", Type: domain.LedgerAccountTypeLiability, NormalBalance: domain.NormalBalanceCredit, Currency: "USD", TotalDebit: 2, TotalCredit: 30},
+		{AccountID: "opening-balance:USD", Name: "Opening balance", Type: domain.LedgerAccountTypeEquity, NormalBalance: domain.NormalBalanceCredit, Currency: "USD", TotalDebit: 30},
+		{AccountID: "fee-income:USD", Name: "Fee income", Type: domain.LedgerAccountTypeRevenue, NormalBalance: domain.NormalBalanceCredit, Currency: "USD", TotalCredit: 2},
+	} {
+		accountBalance.CalculateBalance()
+		accountBalances = append(accountBalances, accountBalance)
+	}
+
+	t.Run("TrialBalance", func(t *testing.T) {
+		idr := domain.NewTrialBalance(asOf, domain.DefaultCurrency, accountBalances)
+		assert.Equal(t, domain.DefaultCurrency, idr.Currency)
+		assert.Len(t, idr.Lines, 7)
+		assert.Equal(t, int64(1520), idr.TotalDebitBalance)
+		assert.True(t, idr.Balanced)
+
+		usd := domain.NewTrialBalance(asOf, "USD", accountBalances)
+		assert.Equal(t, "USD", usd.Currency)
+		assert.Len(t, usd.Lines, 3)
+		assert.Equal(t, int64(30), usd.TotalDebitBalance)
+		assert.Equal(t, int64(30), usd.TotalCreditBalance)
+		assert.True(t, usd.Balanced)
+	})
+
+	t.Run("BalanceSheet", func(t *testing.T) {
+		idr := domain.NewBalanceSheet(asOf, domain.DefaultCurrency, accountBalances)
+		assert.Equal(t, int64(1400), idr.Liabilities.Total)
+		assert.Equal(t, int64(80), idr.CurrentEarnings)
+		assert.True(t, idr.Balanced)
+
+		usd := domain.NewBalanceSheet(asOf, "USD", accountBalances)
+		assert.Empty(t, usd.Assets.Lines)
+		assert.Equal(t, int64(28), usd.Liabilities.Total)
+		assert.Equal(t, int64(-30), usd.Equity.Total)
+		assert.Equal(t, int64(2), usd.CurrentEarnings)
+		assert.True(t, usd.Balanced)
+	})
+
+	t.Run("IncomeStatement", func(t *testing.T) {
+		idr := domain.NewIncomeStatement(asOf, asOf, domain.DefaultCurrency, accountBalances)
+		assert.Equal(t, int64(100), idr.Revenue.Total)
+		assert.Equal(t, int64(80), idr.NetIncome)
+
+		usd := domain.NewIncomeStatement(asOf, asOf, "USD", accountBalances)
+		assert.Equal(t, []*domain.ReportLine{{AccountID: "fee-income:USD", Name: "Fee income", Balance: 2}}, usd.Revenue.Lines)
+		assert.Equal(t, int64(2), usd.NetIncome)
+	})
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// JournalMetadataReversalOf is the metadata key with which a reversing journal refers to the
+// folio of the journal it reverses.
+const JournalMetadataReversalOf = "reversal_of"
+
+// Reversal undoes a posted journal by posting its mirror image under ReferenceID. The journal
+// itself is never changed, and OriginalFolio is unique, so a journal is reversed at most once.
+type Reversal struct {
+	ID                 int64     `json:"id" db:"id"`
+	ReferenceID        string    `json:"reference_id" db:"reference_id"`
+	OriginalFolio      string    `json:"original_folio" db:"original_folio"`
+	TransactionGroupID string    `json:"transaction_group_id" db:"transaction_group_id"`
+	Reason             string    `json:"reason" db:"reason"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+}
+
+func (r *Reversal) TableName() string {
+	return "reversals"
+}
+
+type ReverseJournalRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// NewReversalJournal mirrors the entries of a posted journal: every debit becomes a credit to
+// the same account and the other way around. The reversal stays in the transaction group of
+// the original and refers to its folio in the metadata.
+func NewReversalJournal(folio string, original []*JournalEntry) *JournalTransaction {
+	first := original[0]
+	journal := NewJournalTransaction(folio, "Reversal of "+first.TransactionName).
+		InGroup(first.TransactionGroupID).
+		InCurrency(first.Currency).
+		WithMetadata(JournalMetadataReversalOf, first.Folio)
+
+	for _, entry := range original {
+		journal.Entries = append(journal.Entries, &JournalEntry{
+			AccountID:       entry.AccountID,
+			TransactionName: journal.TransactionName,
+			DebitAmount:     entry.CreditAmount,
+			CreditAmount:    entry.DebitAmount,
+			Currency:        entry.Currency,
+			Folio:           folio,
+		})
+	}
+
+	return journal
+}
+
+type ReversalRepository interface {
+	Create(ctx context.Context, reversal *Reversal) (*Reversal, error)
+	GetByOriginalFolio(ctx context.Context, originalFolio string) (*Reversal, error)
+}
+
+type ReversalUsecase interface {
+	ReverseJournal(ctx context.Context, folio string, request *ReverseJournalRequest) (*Reversal, error)
+}
@@ -0,0 +1,31 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewReversalJournal(t *testing.T) {
+	original := domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+		InGroup("WLT-0").
+		Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 500).
+		Credit(domain.BankClearingAccountID, 500)
+	original.Stamp(time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC))
+
+	reversal := domain.NewReversalJournal("WLT-2", original.Entries)
+
+	assert.NoError(t, reversal.Validate())
+	assert.Equal(t, "WLT-2", reversal.Folio)
+	assert.Equal(t, "WLT-0", reversal.TransactionGroupID)
+	assert.Equal(t, "Reversal of Balance disbursement", reversal.TransactionName)
+	assert.Equal(t, domain.JournalMetadata{domain.JournalMetadataReversalOf: "WLT-1"}, reversal.Metadata)
+	if assert.Len(t, reversal.Entries, 2) {
+		assert.Equal(t, domain.WalletAccountID(1, domain.DefaultCurrency), reversal.Entries[0].AccountID)
+		assert.Equal(t, int64(500), reversal.Entries[0].CreditAmount)
+		assert.Equal(t, domain.BankClearingAccountID, reversal.Entries[1].AccountID)
+		assert.Equal(t, int64(500), reversal.Entries[1].DebitAmount)
+	}
+}
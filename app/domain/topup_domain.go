@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TopUp is a deposit into a wallet. SourceReference identifies the payment at the funding
+// source and is unique, so the same payment can never credit a wallet twice.
+type TopUp struct {
+	ID              int64     `json:"id" db:"id"`
+	ReferenceID     string    `json:"reference_id" db:"reference_id"`
+	UserID          int64     `json:"user_id" db:"user_id"`
+	Amount          int64     `json:"amount" db:"amount"`
+	Currency        string    `json:"currency" db:"currency"`
+	SourceReference string    `json:"source_reference" db:"source_reference"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+func (t *TopUp) TableName() string {
+	return "top_ups"
+}
+
+// TopUpBalanceRequest credits amount to the wallet in Currency, the default currency when it
+// is left out.
+type TopUpBalanceRequest struct {
+	Amount          int64  `json:"amount" binding:"required,gt=0"`
+	Currency        string `json:"currency" binding:"omitempty,iso4217"`
+	SourceReference string `json:"source_reference" binding:"required,max=100"`
+}
+
+func (t *TopUp) Money() Money {
+	return NewMoney(t.Amount, t.Currency)
+}
+
+func (r *TopUpBalanceRequest) Money() Money {
+	return NewMoney(r.Amount, r.Currency)
+}
+
+type TopUpRepository interface {
+	Create(ctx context.Context, topUp *TopUp) (*TopUp, error)
+	GetBySourceReference(ctx context.Context, sourceReference string) (*TopUp, error)
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "context"
+
+// TransactionManager runs fn in a database transaction. Repository calls made with the
+// context passed to fn take part in that transaction, which commits when fn returns nil
+// and rolls back otherwise.
+type TransactionManager interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Transfer moves money from one wallet to another inside the platform.
+type Transfer struct {
+	ID          int64     `json:"id" db:"id"`
+	ReferenceID string    `json:"reference_id" db:"reference_id"`
+	FromUserID  int64     `json:"from_user_id" db:"from_user_id"`
+	ToUserID    int64     `json:"to_user_id" db:"to_user_id"`
+	Amount      int64     `json:"amount" db:"amount"`
+	Currency    string    `json:"currency" db:"currency"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+func (t *Transfer) TableName() string {
+	return "transfers"
+}
+
+// TransferBalanceRequest moves amount between the wallets in Currency of both users, the
+// default currency when it is left out.
+type TransferBalanceRequest struct {
+	ToUserID int64  `json:"to_user_id" binding:"required,gt=0"`
+	Amount   int64  `json:"amount" binding:"required,gt=0"`
+	Currency string `json:"currency" binding:"omitempty,iso4217"`
+}
+
+func (r *TransferBalanceRequest) Money() Money {
+	return NewMoney(r.Amount, r.Currency)
+}
+
+type TransferRepository interface {
+	Create(ctx context.Context, transfer *Transfer) (*Transfer, error)
+}
+
+type TransferUsecase interface {
+	TransferBalance(ctx context.Context, fromUserID int64, request *TransferBalanceRequest) (*Transfer, error)
+}
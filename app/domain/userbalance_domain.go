@@ -3,15 +3,23 @@ package domain
 import (
 	"context"
 	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
 )
 
+// UserBalance is the wallet of a user in one currency. A user has at most one wallet per
+// currency, and the balances are in the minor units of that currency.
 type UserBalance struct {
 	ID          int64     `json:"id" db:"id"`
+	UserID      int64     `json:"user_id" db:"user_id"`
 	Username    string    `json:"username" db:"username"`
+	Currency    string    `json:"currency" db:"currency"`
 	Balance     int64     `json:"balance" db:"balance"`
+	HeldBalance int64     `json:"held_balance" db:"held_balance"`
 	BankCode    string    `json:"bank_code" db:"bank_code"`
 	AccountNo   string    `json:"account_no" db:"account_no"`
 	AccountName string    `json:"account_name" db:"account_name"`
+	Version     int64     `json:"version" db:"version"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -20,12 +28,111 @@ func (u *UserBalance) TableName() string {
 	return "user_balances"
 }
 
+// AvailableBalance is the part of the balance that is not reserved by a pending disbursement.
+func (u *UserBalance) AvailableBalance() Money {
+	return NewMoney(u.Balance-u.HeldBalance, u.Currency)
+}
+
+// checkAmount fails for amounts that are not positive or not in the currency of the wallet.
+func (u *UserBalance) checkAmount(amount Money) error {
+	if err := amount.CheckPositive(); err != nil {
+		return err
+	}
+
+	return amount.CheckCurrency(u.Currency)
+}
+
+// Hold reserves amount of the available balance.
+func (u *UserBalance) Hold(amount Money) error {
+	if err := u.checkAmount(amount); err != nil {
+		return err
+	}
+	if u.AvailableBalance().Amount < amount.Amount {
+		return errors.ErrInsufficientBalance
+	}
+
+	u.HeldBalance += amount.Amount
+	return nil
+}
+
+// CaptureHold takes amount out of the balance, consuming a previous hold.
+func (u *UserBalance) CaptureHold(amount Money) error {
+	if err := u.checkAmount(amount); err != nil {
+		return err
+	}
+	if u.HeldBalance < amount.Amount {
+		return errors.ErrInsufficientHold
+	}
+
+	u.HeldBalance -= amount.Amount
+	u.Balance -= amount.Amount
+	return nil
+}
+
+// ReleaseHold makes amount of a previous hold available again.
+func (u *UserBalance) ReleaseHold(amount Money) error {
+	if err := u.checkAmount(amount); err != nil {
+		return err
+	}
+	if u.HeldBalance < amount.Amount {
+		return errors.ErrInsufficientHold
+	}
+
+	u.HeldBalance -= amount.Amount
+	return nil
+}
+
+// Debit takes amount out of the available balance.
+func (u *UserBalance) Debit(amount Money) error {
+	if err := u.checkAmount(amount); err != nil {
+		return err
+	}
+	if u.AvailableBalance().Amount < amount.Amount {
+		return errors.ErrInsufficientBalance
+	}
+
+	u.Balance -= amount.Amount
+	return nil
+}
+
+// Credit adds amount to the balance.
+func (u *UserBalance) Credit(amount Money) error {
+	if err := u.checkAmount(amount); err != nil {
+		return err
+	}
+
+	u.Balance += amount.Amount
+	return nil
+}
+
+// DisburseBalanceRequest pays amount out of the wallet in Currency, the default currency when
+// it is left out.
+type DisburseBalanceRequest struct {
+	Amount   int64  `json:"amount" binding:"required,gt=0"`
+	Currency string `json:"currency" binding:"omitempty,iso4217"`
+}
+
+func (r *DisburseBalanceRequest) Money() Money {
+	return NewMoney(r.Amount, r.Currency)
+}
+
+type OpenWalletRequest struct {
+	Currency string `json:"currency" binding:"required,iso4217"`
+}
+
 type UserBalanceRepository interface {
-	GetByID(ctx context.Context, id int64) (*UserBalance, error)
-	UpdateBalanceByID(ctx context.Context, updatedBalance, id int64) error
+	Create(ctx context.Context, userBalance *UserBalance) (*UserBalance, error)
+	GetByUserIDAndCurrency(ctx context.Context, userID int64, currency string) (*UserBalance, error)
+	GetAllByUserID(ctx context.Context, userID int64) ([]*UserBalance, error)
+	GetAll(ctx context.Context) ([]*UserBalance, error)
+	UpdateBalance(ctx context.Context, userBalance *UserBalance) error
 }
 
 type UserBalanceUsecase interface {
-	GetUserBalanceByID(ctx context.Context, id int64) (*UserBalance, error)
-	DisburseBalance(ctx context.Context, id int64) error
+	GetUserBalanceByID(ctx context.Context, id int64, currency string) (*UserBalance, error)
+	GetUserWallets(ctx context.Context, id int64) ([]*UserBalance, error)
+	OpenWallet(ctx context.Context, id int64, request *OpenWalletRequest) (*UserBalance, error)
+	DisburseBalance(ctx context.Context, id int64, request *DisburseBalanceRequest) (*Disbursement, error)
+	SettleDisbursement(ctx context.Context, settlement *DisbursementSettlement) (*Disbursement, error)
+	TopUpBalance(ctx context.Context, id int64, request *TopUpBalanceRequest) (*TopUp, error)
 }
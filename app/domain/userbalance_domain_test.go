@@ -0,0 +1,52 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func idr(amount int64) domain.Money {
+	return domain.NewMoney(amount, "IDR")
+}
+
+func TestUserBalance_Mutations(t *testing.T) {
+	testCases := []struct {
+		name            string
+		mutate          func(*domain.UserBalance) error
+		expectedErr     error
+		expectedBalance int64
+		expectedHeld    int64
+	}{
+		{"Hold", func(u *domain.UserBalance) error { return u.Hold(idr(300)) }, nil, 1000, 500},
+		{"HoldMoreThanAvailable", func(u *domain.UserBalance) error { return u.Hold(idr(900)) }, domErr.ErrInsufficientBalance, 1000, 200},
+		{"CaptureHold", func(u *domain.UserBalance) error { return u.CaptureHold(idr(200)) }, nil, 800, 0},
+		{"CaptureMoreThanHeld", func(u *domain.UserBalance) error { return u.CaptureHold(idr(300)) }, domErr.ErrInsufficientHold, 1000, 200},
+		{"ReleaseHold", func(u *domain.UserBalance) error { return u.ReleaseHold(idr(200)) }, nil, 1000, 0},
+		{"ReleaseMoreThanHeld", func(u *domain.UserBalance) error { return u.ReleaseHold(idr(300)) }, domErr.ErrInsufficientHold, 1000, 200},
+		{"Debit", func(u *domain.UserBalance) error { return u.Debit(idr(800)) }, nil, 200, 200},
+		{"DebitHeldFunds", func(u *domain.UserBalance) error { return u.Debit(idr(900)) }, domErr.ErrInsufficientBalance, 1000, 200},
+		{"Credit", func(u *domain.UserBalance) error { return u.Credit(idr(500)) }, nil, 1500, 200},
+		{"NonPositiveAmount", func(u *domain.UserBalance) error { return u.Credit(idr(0)) }, domErr.ErrInvalidParameter, 1000, 200},
+		{"OtherCurrency", func(u *domain.UserBalance) error { return u.Credit(domain.NewMoney(500, "USD")) }, domErr.ErrCurrencyMismatch, 1000, 200},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userBalance := &domain.UserBalance{Currency: "IDR", Balance: 1000, HeldBalance: 200}
+
+			err := tc.mutate(userBalance)
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expectedBalance, userBalance.Balance)
+			assert.Equal(t, tc.expectedHeld, userBalance.HeldBalance)
+		})
+	}
+}
+
+func TestUserBalance_AvailableBalance(t *testing.T) {
+	userBalance := &domain.UserBalance{Currency: "USD", Balance: 1000, HeldBalance: 200}
+
+	assert.Equal(t, domain.NewMoney(800, "USD"), userBalance.AvailableBalance())
+}
@@ -3,35 +3,58 @@ package external
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"time"
 
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
 	"github.com/krisdioles/ppr-wallet/config"
+	"github.com/krisdioles/ppr-wallet/pkg/httpclient"
+)
+
+const (
+	Bank1DisbursementStatusPending   = "pending"
+	Bank1DisbursementStatusCompleted = "completed"
+	Bank1DisbursementStatusFailed    = "failed"
+
+	// Bank1SignatureHeader carries the hex encoded HMAC-SHA256 of a callback body, keyed with
+	// the webhook secret shared with Bank1.
+	Bank1SignatureHeader = "X-Bank1-Signature"
 )
 
 type Bank1Client struct {
 	hostname             string
 	apikey               string
 	disbursementEndpoint string
-	httpClient           *http.Client
+	httpClient           *httpclient.Client
 }
 
 type IBank1Client interface {
 	CreateDisbursement(ctx context.Context, requestParam *Bank1CreateDisbursementRequest) (*Bank1CreateDisbursementResponse, error)
+	GetDisbursement(ctx context.Context, id string) (*Bank1GetDisbursementResponse, error)
 }
 
+// NewBank1Client creates a client that retries fetching a disbursement but never creating
+// one, as Bank1 may pay out a repeated disbursement twice.
 func NewBank1Client(cfg *config.Bank1Config) IBank1Client {
 	return &Bank1Client{
 		hostname:             cfg.Hostname,
 		apikey:               cfg.APIKey,
 		disbursementEndpoint: cfg.DisbursementEndpoint,
-		httpClient: &http.Client{
-			Transport: http.DefaultTransport,
-			Timeout:   5 * time.Second,
-		},
+		httpClient: httpclient.New(httpclient.Config{
+			Timeout:          cfg.HTTPClient.Timeout,
+			MaxRetries:       cfg.HTTPClient.MaxRetries,
+			BaseBackoff:      cfg.HTTPClient.BaseBackoff,
+			MaxBackoff:       cfg.HTTPClient.MaxBackoff,
+			BreakerThreshold: cfg.HTTPClient.BreakerThreshold,
+			BreakerCooldown:  cfg.HTTPClient.BreakerCooldown,
+		}, httpclient.WithHooks(httpclient.LogHooks("Bank1Client"))),
 	}
 }
 
@@ -65,16 +88,24 @@ type Bank1CreateDisbursementResponseData struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type Bank1GetDisbursementResponse struct {
+	Status  string                              `json:"status"`
+	Message string                              `json:"message"`
+	Data    Bank1CreateDisbursementResponseData `json:"data"`
+}
+
+// CreateDisbursement asks Bank1 to pay out a disbursement. A request without a currency is
+// rejected before it is sent rather than paid out in a currency the wallet may not hold.
 func (c *Bank1Client) CreateDisbursement(ctx context.Context, requestParam *Bank1CreateDisbursementRequest) (*Bank1CreateDisbursementResponse, error) {
 	if requestParam.Amount.Currency == "" {
-		requestParam.Amount.Currency = "IDR"
+		return &Bank1CreateDisbursementResponse{}, errors.ErrMissingCurrency
 	}
 
 	bodyBytes, _ := json.Marshal(requestParam)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s", c.hostname, c.disbursementEndpoint), bytes.NewReader(bodyBytes))
 	if err != nil {
-		return &Bank1CreateDisbursementResponse{}, err
+		return &Bank1CreateDisbursementResponse{}, fmt.Errorf("%w: %v", errors.ErrPayoutNotSent, err)
 	}
 
 	req.Header.Set("X-API-Key", c.apikey)
@@ -86,6 +117,11 @@ func (c *Bank1Client) CreateDisbursement(ctx context.Context, requestParam *Bank
 	}
 	defer res.Body.Close()
 
+	// Bank1 may have created the disbursement before failing, only its other answers are final
+	if res.StatusCode >= http.StatusInternalServerError {
+		return &Bank1CreateDisbursementResponse{}, fmt.Errorf("bank1 create disbursement: %s", res.Status)
+	}
+
 	respBody, err := io.ReadAll(res.Body)
 	if err != nil {
 		return &Bank1CreateDisbursementResponse{}, err
@@ -98,3 +134,69 @@ func (c *Bank1Client) CreateDisbursement(ctx context.Context, requestParam *Bank
 
 	return resp, nil
 }
+
+// GetDisbursement fetches a disbursement by the id Bank1 gave it, to learn its current status.
+// Only a 2xx answer carries the disbursement, any other one is an error.
+func (c *Bank1Client) GetDisbursement(ctx context.Context, id string) (*Bank1GetDisbursementResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s/%s", c.hostname, c.disbursementEndpoint, url.PathEscape(id)), nil)
+	if err != nil {
+		return &Bank1GetDisbursementResponse{}, err
+	}
+
+	req.Header.Set("X-API-Key", c.apikey)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return &Bank1GetDisbursementResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return &Bank1GetDisbursementResponse{}, fmt.Errorf("bank1 get disbursement: %s", res.Status)
+	}
+
+	respBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return &Bank1GetDisbursementResponse{}, err
+	}
+
+	var resp *Bank1GetDisbursementResponse
+	if err = json.Unmarshal(respBody, &resp); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// Bank1DisbursementCallback is the body Bank1 posts once a disbursement it accepted is paid
+// out or has failed.
+type Bank1DisbursementCallback struct {
+	ID          string    `json:"id"`
+	ReferenceID string    `json:"reference_id"`
+	Status      string    `json:"status"`
+	Message     string    `json:"message"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// VerifyBank1Signature reports whether signature is the one Bank1 computes for body with
+// secret. Without a secret no callback is trusted.
+func VerifyBank1Signature(secret string, body []byte, signature string) bool {
+	if secret == "" {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(expected, SignBank1Body(secret, body))
+}
+
+// SignBank1Body returns the HMAC-SHA256 of body keyed with secret.
+func SignBank1Body(secret string, body []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,109 @@
+package external_test
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/external"
+	"github.com/krisdioles/ppr-wallet/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyBank1Signature(t *testing.T) {
+	body := []byte(`{"id":"bank-1","reference_id":"WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7","status":"completed"}`)
+	signature := hex.EncodeToString(external.SignBank1Body("webhook-secret", body))
+
+	testCases := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+		expected  bool
+	}{
+		{"Valid", "webhook-secret", body, signature, true},
+		{"OtherSecret", "another-secret", body, signature, false},
+		{"ChangedBody", "webhook-secret", []byte(`{"id":"bank-1","reference_id":"WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7","status":"failed"}`), signature, false},
+		{"NotHex", "webhook-secret", body, "not-a-signature", false},
+		{"Missing", "webhook-secret", body, "", false},
+		{"NoSecret", "", body, hex.EncodeToString(external.SignBank1Body("", body)), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, external.VerifyBank1Signature(tc.secret, tc.body, tc.signature))
+		})
+	}
+}
+
+func TestBank1Client_Retries(t *testing.T) {
+	ctx := context.Background()
+
+	// the bank is unavailable for the first call of every kind
+	calls := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.Method]++
+		assert.Equal(t, "secret123", r.Header.Get("X-API-Key"))
+
+		if calls[r.Method] == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status":"ok","data":{"id":"bank-1","status":"completed"}}`))
+	}))
+	defer server.Close()
+
+	client := external.NewBank1Client(&config.Bank1Config{
+		Hostname:             server.URL,
+		APIKey:               "secret123",
+		DisbursementEndpoint: "api/v1/disbursement",
+		HTTPClient:           config.HTTPClientConfig{BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+	})
+
+	t.Run("GetDisbursementRetried", func(t *testing.T) {
+		resp, err := client.GetDisbursement(ctx, "bank-1")
+		assert.NoError(t, err)
+		assert.Equal(t, external.Bank1DisbursementStatusCompleted, resp.Data.Status)
+		assert.Equal(t, 2, calls[http.MethodGet])
+	})
+
+	t.Run("CreateDisbursementNotRetried", func(t *testing.T) {
+		// a repeated disbursement could be paid out twice
+		_, err := client.CreateDisbursement(ctx, &external.Bank1CreateDisbursementRequest{ReferenceID: "WLT-1", Amount: external.AmountObj{Total: 100, Currency: "IDR"}})
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls[http.MethodPost])
+	})
+
+	t.Run("CreateDisbursementWithoutCurrency", func(t *testing.T) {
+		_, err := client.CreateDisbursement(ctx, &external.Bank1CreateDisbursementRequest{ReferenceID: "WLT-2", Amount: external.AmountObj{Total: 100}})
+		assert.ErrorIs(t, err, domErr.ErrMissingCurrency)
+		assert.Equal(t, 1, calls[http.MethodPost])
+	})
+}
+
+func TestBank1Client_GetDisbursementErrorStatus(t *testing.T) {
+	for _, statusCode := range []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusTooManyRequests, http.StatusInternalServerError} {
+		t.Run(http.StatusText(statusCode), func(t *testing.T) {
+			// the body looks like a disbursement, only the status tells it is not one
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(statusCode)
+				w.Write([]byte(`{"status":"error","message":"try again","data":{"id":"bank-1","status":"failed"}}`))
+			}))
+			defer server.Close()
+
+			client := external.NewBank1Client(&config.Bank1Config{
+				Hostname:             server.URL,
+				DisbursementEndpoint: "api/v1/disbursement",
+				HTTPClient:           config.HTTPClientConfig{BaseBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+			})
+
+			resp, err := client.GetDisbursement(context.Background(), "bank-1")
+			assert.Error(t, err)
+			assert.Empty(t, resp.Data.Status)
+		})
+	}
+}
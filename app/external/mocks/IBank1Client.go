@@ -0,0 +1,89 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	external "github.com/krisdioles/ppr-wallet/app/external"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// IBank1Client is an autogenerated mock type for the IBank1Client type
+type IBank1Client struct {
+	mock.Mock
+}
+
+// CreateDisbursement provides a mock function with given fields: ctx, requestParam
+func (_m *IBank1Client) CreateDisbursement(ctx context.Context, requestParam *external.Bank1CreateDisbursementRequest) (*external.Bank1CreateDisbursementResponse, error) {
+	ret := _m.Called(ctx, requestParam)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDisbursement")
+	}
+
+	var r0 *external.Bank1CreateDisbursementResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *external.Bank1CreateDisbursementRequest) (*external.Bank1CreateDisbursementResponse, error)); ok {
+		return rf(ctx, requestParam)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *external.Bank1CreateDisbursementRequest) *external.Bank1CreateDisbursementResponse); ok {
+		r0 = rf(ctx, requestParam)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*external.Bank1CreateDisbursementResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *external.Bank1CreateDisbursementRequest) error); ok {
+		r1 = rf(ctx, requestParam)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDisbursement provides a mock function with given fields: ctx, id
+func (_m *IBank1Client) GetDisbursement(ctx context.Context, id string) (*external.Bank1GetDisbursementResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDisbursement")
+	}
+
+	var r0 *external.Bank1GetDisbursementResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*external.Bank1GetDisbursementResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *external.Bank1GetDisbursementResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*external.Bank1GetDisbursementResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewIBank1Client creates a new instance of IBank1Client. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIBank1Client(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IBank1Client {
+	mock := &IBank1Client{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,107 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	external "github.com/krisdioles/ppr-wallet/app/external"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PayoutProvider is an autogenerated mock type for the PayoutProvider type
+type PayoutProvider struct {
+	mock.Mock
+}
+
+// CreatePayout provides a mock function with given fields: ctx, request
+func (_m *PayoutProvider) CreatePayout(ctx context.Context, request *external.PayoutRequest) (*external.PayoutResponse, error) {
+	ret := _m.Called(ctx, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePayout")
+	}
+
+	var r0 *external.PayoutResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *external.PayoutRequest) (*external.PayoutResponse, error)); ok {
+		return rf(ctx, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *external.PayoutRequest) *external.PayoutResponse); ok {
+		r0 = rf(ctx, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*external.PayoutResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *external.PayoutRequest) error); ok {
+		r1 = rf(ctx, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPayout provides a mock function with given fields: ctx, partnerID
+func (_m *PayoutProvider) GetPayout(ctx context.Context, partnerID string) (*external.PayoutStatusResponse, error) {
+	ret := _m.Called(ctx, partnerID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPayout")
+	}
+
+	var r0 *external.PayoutStatusResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*external.PayoutStatusResponse, error)); ok {
+		return rf(ctx, partnerID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *external.PayoutStatusResponse); ok {
+		r0 = rf(ctx, partnerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*external.PayoutStatusResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, partnerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Name provides a mock function with no fields
+func (_m *PayoutProvider) Name() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Name")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// NewPayoutProvider creates a new instance of PayoutProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPayoutProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PayoutProvider {
+	mock := &PayoutProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "github.com/krisdioles/ppr-wallet/app/domain"
+	external "github.com/krisdioles/ppr-wallet/app/external"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PayoutRouter is an autogenerated mock type for the PayoutRouter type
+type PayoutRouter struct {
+	mock.Mock
+}
+
+// Provider provides a mock function with given fields: name
+func (_m *PayoutRouter) Provider(name string) (external.PayoutProvider, error) {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Provider")
+	}
+
+	var r0 external.PayoutProvider
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (external.PayoutProvider, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) external.PayoutProvider); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(external.PayoutProvider)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Route provides a mock function with given fields: bankCode, amount
+func (_m *PayoutRouter) Route(bankCode string, amount domain.Money) (external.PayoutProvider, error) {
+	ret := _m.Called(bankCode, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Route")
+	}
+
+	var r0 external.PayoutProvider
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, domain.Money) (external.PayoutProvider, error)); ok {
+		return rf(bankCode, amount)
+	}
+	if rf, ok := ret.Get(0).(func(string, domain.Money) external.PayoutProvider); ok {
+		r0 = rf(bankCode, amount)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(external.PayoutProvider)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, domain.Money) error); ok {
+		r1 = rf(bankCode, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPayoutRouter creates a new instance of PayoutRouter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPayoutRouter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PayoutRouter {
+	mock := &PayoutRouter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,144 @@
+package external
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/config"
+)
+
+const PayoutProviderTypeBank1 = "bank1"
+
+// PayoutProvider sends money out of the wallet to a bank account.
+type PayoutProvider interface {
+	Name() string
+	CreatePayout(ctx context.Context, request *PayoutRequest) (*PayoutResponse, error)
+	GetPayout(ctx context.Context, partnerID string) (*PayoutStatusResponse, error)
+}
+
+type PayoutStatus string
+
+const (
+	PayoutStatusPending   PayoutStatus = "pending"
+	PayoutStatusCompleted PayoutStatus = "completed"
+	PayoutStatusFailed    PayoutStatus = "failed"
+)
+
+type PayoutRequest struct {
+	ReferenceID string
+	Account     AccountObj
+	Amount      domain.Money
+}
+
+// PayoutResponse is the answer of a provider that was reached. A payout it did not accept
+// comes back with Accepted false and the reason in Message, not as an error. An accepted
+// payout is Completed when the provider paid it out right away rather than reporting the
+// outcome later.
+type PayoutResponse struct {
+	Accepted  bool
+	Completed bool
+	PartnerID string
+	Message   string
+}
+
+// PayoutStatusResponse is the current status of a payout the provider accepted, with the
+// reason in Message when it failed.
+type PayoutStatusResponse struct {
+	PartnerID string
+	Status    PayoutStatus
+	Message   string
+}
+
+// NewPayoutProvider creates the client of a configured provider.
+func NewPayoutProvider(cfg *config.PayoutProviderConfig) (PayoutProvider, error) {
+	switch cfg.Type {
+	case PayoutProviderTypeBank1:
+		return NewBank1PayoutProvider(cfg.Name, NewBank1Client(&cfg.Bank1)), nil
+	default:
+		return nil, fmt.Errorf("unknown payout provider type %q", cfg.Type)
+	}
+}
+
+// PayoutProviderNames returns the names of the configured providers of type providerType,
+// or the name of the default bank1 client when no providers are configured.
+func PayoutProviderNames(cfg *config.Config, providerType string) []string {
+	if len(cfg.Payout.Providers) == 0 {
+		if providerType == PayoutProviderTypeBank1 {
+			return []string{PayoutProviderTypeBank1}
+		}
+
+		return nil
+	}
+
+	var names []string
+	for _, providerCfg := range cfg.Payout.Providers {
+		if providerCfg.Type == providerType {
+			names = append(names, providerCfg.Name)
+		}
+	}
+
+	return names
+}
+
+type Bank1PayoutProvider struct {
+	name   string
+	client IBank1Client
+}
+
+func NewBank1PayoutProvider(name string, client IBank1Client) PayoutProvider {
+	return &Bank1PayoutProvider{
+		name:   name,
+		client: client,
+	}
+}
+
+func (p *Bank1PayoutProvider) Name() string {
+	return p.name
+}
+
+func (p *Bank1PayoutProvider) CreatePayout(ctx context.Context, request *PayoutRequest) (*PayoutResponse, error) {
+	resp, err := p.client.CreateDisbursement(ctx, &Bank1CreateDisbursementRequest{
+		ReferenceID: request.ReferenceID,
+		Account:     request.Account,
+		Amount: AmountObj{
+			Total:    request.Amount.Amount,
+			Currency: request.Amount.Currency,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PayoutResponse{
+		Accepted:  resp.Status == "ok",
+		Completed: resp.Data.Status == Bank1DisbursementStatusCompleted,
+		PartnerID: resp.Data.ID,
+		Message:   resp.Message,
+	}, nil
+}
+
+func (p *Bank1PayoutProvider) GetPayout(ctx context.Context, partnerID string) (*PayoutStatusResponse, error) {
+	resp, err := p.client.GetDisbursement(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Status != "ok" {
+		return nil, fmt.Errorf("get bank1 disbursement %s: %s", partnerID, resp.Message)
+	}
+
+	status := PayoutStatusPending
+	switch resp.Data.Status {
+	case Bank1DisbursementStatusCompleted:
+		status = PayoutStatusCompleted
+	case Bank1DisbursementStatusFailed:
+		status = PayoutStatusFailed
+	}
+
+	return &PayoutStatusResponse{
+		PartnerID: resp.Data.ID,
+		Status:    status,
+		Message:   resp.Message,
+	}, nil
+}
@@ -0,0 +1,82 @@
+package external_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/krisdioles/ppr-wallet/app/external"
+	"github.com/krisdioles/ppr-wallet/app/external/mocks"
+	"github.com/krisdioles/ppr-wallet/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBank1PayoutProvider_GetPayout(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name       string
+		response   *external.Bank1GetDisbursementResponse
+		expected   *external.PayoutStatusResponse
+		expectsErr bool
+	}{
+		{
+			name: "Completed",
+			response: &external.Bank1GetDisbursementResponse{Status: "ok", Data: external.Bank1CreateDisbursementResponseData{
+				ID: "bank-1", Status: external.Bank1DisbursementStatusCompleted,
+			}},
+			expected: &external.PayoutStatusResponse{PartnerID: "bank-1", Status: external.PayoutStatusCompleted},
+		},
+		{
+			name: "Failed",
+			response: &external.Bank1GetDisbursementResponse{Status: "ok", Message: "account closed", Data: external.Bank1CreateDisbursementResponseData{
+				ID: "bank-1", Status: external.Bank1DisbursementStatusFailed,
+			}},
+			expected: &external.PayoutStatusResponse{PartnerID: "bank-1", Status: external.PayoutStatusFailed, Message: "account closed"},
+		},
+		{
+			name: "Pending",
+			response: &external.Bank1GetDisbursementResponse{Status: "ok", Data: external.Bank1CreateDisbursementResponseData{
+				ID: "bank-1", Status: external.Bank1DisbursementStatusPending,
+			}},
+			expected: &external.PayoutStatusResponse{PartnerID: "bank-1", Status: external.PayoutStatusPending},
+		},
+		{
+			name:       "NotOk",
+			response:   &external.Bank1GetDisbursementResponse{Status: "error", Message: "disbursement not found"},
+			expectsErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := new(mocks.IBank1Client)
+			client.On("GetDisbursement", ctx, "bank-1").Return(tc.response, nil)
+
+			result, err := external.NewBank1PayoutProvider("bank1", client).GetPayout(ctx, "bank-1")
+			if tc.expectsErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestPayoutProviderNames(t *testing.T) {
+	t.Run("DefaultsToBank1", func(t *testing.T) {
+		assert.Equal(t, []string{external.PayoutProviderTypeBank1}, external.PayoutProviderNames(&config.Config{}, external.PayoutProviderTypeBank1))
+	})
+
+	t.Run("Providers", func(t *testing.T) {
+		cfg := &config.Config{Payout: config.PayoutConfig{
+			Providers: []config.PayoutProviderConfig{
+				{Name: "bank1-bca", Type: external.PayoutProviderTypeBank1},
+				{Name: "bank2", Type: "bank2"},
+				{Name: "bank1-idr", Type: external.PayoutProviderTypeBank1},
+			},
+		}}
+
+		assert.Equal(t, []string{"bank1-bca", "bank1-idr"}, external.PayoutProviderNames(cfg, external.PayoutProviderTypeBank1))
+	})
+}
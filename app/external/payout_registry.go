@@ -0,0 +1,240 @@
+package external
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/config"
+)
+
+const (
+	defaultUnhealthyThreshold = 3
+	defaultUnhealthyCooldown  = 30 * time.Second
+)
+
+// PayoutRouter picks the provider a disbursement is sent with, and finds it again by name
+// for the disbursements it was sent with.
+type PayoutRouter interface {
+	Route(bankCode string, amount domain.Money) (PayoutProvider, error)
+	Provider(name string) (PayoutProvider, error)
+}
+
+// PayoutRoute limits which disbursements a provider takes. Empty lists and zero amounts
+// match everything.
+type PayoutRoute struct {
+	BankCodes  []string
+	Currencies []string
+	MinAmount  int64
+	MaxAmount  int64
+}
+
+func (r PayoutRoute) Matches(bankCode string, amount domain.Money) bool {
+	if len(r.BankCodes) > 0 && !contains(r.BankCodes, bankCode) {
+		return false
+	}
+	if len(r.Currencies) > 0 && !contains(r.Currencies, amount.Currency) {
+		return false
+	}
+	if r.MinAmount > 0 && amount.Amount < r.MinAmount {
+		return false
+	}
+	if r.MaxAmount > 0 && amount.Amount > r.MaxAmount {
+		return false
+	}
+
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+type registeredPayoutProvider struct {
+	provider PayoutProvider
+	route    PayoutRoute
+
+	consecutiveFailures int
+	unhealthyUntil      time.Time
+}
+
+// PayoutProviderRegistry routes disbursements to the first registered provider whose route
+// matches and that is healthy. A provider whose calls failed UnhealthyThreshold times in a
+// row is skipped until the cooldown has passed, after which a single call is routed to it to
+// decide whether it recovered while the others keep skipping it.
+type PayoutProviderRegistry struct {
+	mu        sync.Mutex
+	providers []*registeredPayoutProvider
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+}
+
+func NewPayoutProviderRegistry(unhealthyThreshold int, unhealthyCooldown time.Duration) *PayoutProviderRegistry {
+	if unhealthyThreshold <= 0 {
+		unhealthyThreshold = defaultUnhealthyThreshold
+	}
+	if unhealthyCooldown <= 0 {
+		unhealthyCooldown = defaultUnhealthyCooldown
+	}
+
+	return &PayoutProviderRegistry{
+		threshold: unhealthyThreshold,
+		cooldown:  unhealthyCooldown,
+		now:       time.Now,
+	}
+}
+
+// NewPayoutProviderRegistryFromConfig registers the configured providers in order, or only
+// the bank1 client when none are configured.
+func NewPayoutProviderRegistryFromConfig(cfg *config.Config) (*PayoutProviderRegistry, error) {
+	registry := NewPayoutProviderRegistry(cfg.Payout.UnhealthyThreshold, cfg.Payout.UnhealthyCooldown)
+
+	if len(cfg.Payout.Providers) == 0 {
+		err := registry.Register(NewBank1PayoutProvider(PayoutProviderTypeBank1, NewBank1Client(&cfg.Bank1)), PayoutRoute{})
+		return registry, err
+	}
+
+	for i := range cfg.Payout.Providers {
+		providerCfg := &cfg.Payout.Providers[i]
+
+		provider, err := NewPayoutProvider(providerCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		err = registry.Register(provider, PayoutRoute{
+			BankCodes:  providerCfg.BankCodes,
+			Currencies: providerCfg.Currencies,
+			MinAmount:  providerCfg.MinAmount,
+			MaxAmount:  providerCfg.MaxAmount,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+// Register adds a provider after the ones registered before it.
+func (r *PayoutProviderRegistry) Register(provider PayoutProvider, route PayoutRoute) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, registered := range r.providers {
+		if registered.provider.Name() == provider.Name() {
+			return fmt.Errorf("payout provider %q is already registered", provider.Name())
+		}
+	}
+
+	r.providers = append(r.providers, &registeredPayoutProvider{provider: provider, route: route})
+	return nil
+}
+
+// Route returns the provider for a disbursement of amount to bankCode, which reports the
+// outcome of its calls back to the registry. amount has to name its currency.
+func (r *PayoutProviderRegistry) Route(bankCode string, amount domain.Money) (PayoutProvider, error) {
+	if amount.Currency == "" {
+		return nil, errors.ErrMissingCurrency
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for _, registered := range r.providers {
+		if !registered.route.Matches(bankCode, amount) || now.Before(registered.unhealthyUntil) {
+			continue
+		}
+
+		// this call probes an unhealthy provider, the others skip it until the probe reports
+		// back, or for another cooldown when the caller never uses the provider
+		if registered.consecutiveFailures >= r.threshold {
+			registered.unhealthyUntil = now.Add(r.cooldown)
+		}
+
+		return &healthTrackingPayoutProvider{registry: r, registered: registered}, nil
+	}
+
+	return nil, errors.ErrNoPayoutProvider
+}
+
+// Provider returns the provider called name, healthy or not.
+func (r *PayoutProviderRegistry) Provider(name string) (PayoutProvider, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, registered := range r.providers {
+		if registered.provider.Name() == name {
+			return &healthTrackingPayoutProvider{registry: r, registered: registered}, nil
+		}
+	}
+
+	return nil, errors.ErrNoPayoutProvider
+}
+
+// Healthy reports whether the provider called name is currently routed to.
+func (r *PayoutProviderRegistry) Healthy(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for _, registered := range r.providers {
+		if registered.provider.Name() == name {
+			return !now.Before(registered.unhealthyUntil)
+		}
+	}
+
+	return false
+}
+
+func (r *PayoutProviderRegistry) recordResult(registered *registeredPayoutProvider, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err == nil {
+		registered.consecutiveFailures = 0
+		registered.unhealthyUntil = time.Time{}
+		return
+	}
+
+	registered.consecutiveFailures++
+	if registered.consecutiveFailures >= r.threshold {
+		registered.unhealthyUntil = r.now().Add(r.cooldown)
+	}
+}
+
+type healthTrackingPayoutProvider struct {
+	registry   *PayoutProviderRegistry
+	registered *registeredPayoutProvider
+}
+
+func (p *healthTrackingPayoutProvider) Name() string {
+	return p.registered.provider.Name()
+}
+
+// CreatePayout counts errors against the health of the provider. A payout the provider
+// answered, accepted or not, shows it is reachable.
+func (p *healthTrackingPayoutProvider) CreatePayout(ctx context.Context, request *PayoutRequest) (*PayoutResponse, error) {
+	resp, err := p.registered.provider.CreatePayout(ctx, request)
+	p.registry.recordResult(p.registered, err)
+
+	return resp, err
+}
+
+func (p *healthTrackingPayoutProvider) GetPayout(ctx context.Context, partnerID string) (*PayoutStatusResponse, error) {
+	resp, err := p.registered.provider.GetPayout(ctx, partnerID)
+	p.registry.recordResult(p.registered, err)
+
+	return resp, err
+}
@@ -0,0 +1,201 @@
+package external_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/external"
+	"github.com/krisdioles/ppr-wallet/app/external/mocks"
+	"github.com/krisdioles/ppr-wallet/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newMockPayoutProvider(name string) *mocks.PayoutProvider {
+	provider := new(mocks.PayoutProvider)
+	provider.On("Name").Return(name).Maybe()
+
+	return provider
+}
+
+func TestPayoutRoute_Matches(t *testing.T) {
+	idr := func(amount int64) domain.Money {
+		return domain.Money{Amount: amount, Currency: "IDR"}
+	}
+
+	testCases := []struct {
+		name     string
+		route    external.PayoutRoute
+		bankCode string
+		amount   domain.Money
+		expected bool
+	}{
+		{"Empty", external.PayoutRoute{}, "bca", idr(100), true},
+		{"BankCode", external.PayoutRoute{BankCodes: []string{"bca", "bni"}}, "bni", idr(100), true},
+		{"OtherBankCode", external.PayoutRoute{BankCodes: []string{"bca"}}, "bni", idr(100), false},
+		{"Currency", external.PayoutRoute{Currencies: []string{"USD"}}, "bca", idr(100), false},
+		{"BelowMinAmount", external.PayoutRoute{MinAmount: 101}, "bca", idr(100), false},
+		{"AtMaxAmount", external.PayoutRoute{MaxAmount: 100}, "bca", idr(100), true},
+		{"AboveMaxAmount", external.PayoutRoute{MaxAmount: 99}, "bca", idr(100), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.route.Matches(tc.bankCode, tc.amount))
+		})
+	}
+}
+
+func TestPayoutProviderRegistry_Route(t *testing.T) {
+	registry := external.NewPayoutProviderRegistry(0, 0)
+	assert.NoError(t, registry.Register(newMockPayoutProvider("small-bca"), external.PayoutRoute{BankCodes: []string{"bca"}, MaxAmount: 1000}))
+	assert.NoError(t, registry.Register(newMockPayoutProvider("usd"), external.PayoutRoute{Currencies: []string{"USD"}}))
+	assert.NoError(t, registry.Register(newMockPayoutProvider("fallback"), external.PayoutRoute{Currencies: []string{"IDR"}}))
+	assert.Error(t, registry.Register(newMockPayoutProvider("usd"), external.PayoutRoute{}))
+
+	testCases := []struct {
+		name     string
+		bankCode string
+		amount   domain.Money
+		expected string
+	}{
+		{"FirstMatch", "bca", domain.Money{Amount: 1000, Currency: "IDR"}, "small-bca"},
+		{"AmountTooLarge", "bca", domain.Money{Amount: 1001, Currency: "IDR"}, "fallback"},
+		{"OtherBank", "bni", domain.Money{Amount: 10, Currency: "IDR"}, "fallback"},
+		{"Currency", "bni", domain.Money{Amount: 10, Currency: "USD"}, "usd"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider, err := registry.Route(tc.bankCode, tc.amount)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, provider.Name())
+		})
+	}
+
+	t.Run("NoMatch", func(t *testing.T) {
+		_, err := registry.Route("bni", domain.Money{Amount: 10, Currency: "SGD"})
+		assert.ErrorIs(t, err, domErr.ErrNoPayoutProvider)
+	})
+
+	t.Run("MissingCurrency", func(t *testing.T) {
+		_, err := registry.Route("bca", domain.Money{Amount: 10})
+		assert.ErrorIs(t, err, domErr.ErrMissingCurrency)
+	})
+}
+
+func TestPayoutProviderRegistry_Health(t *testing.T) {
+	ctx := context.Background()
+	amount := domain.Money{Amount: 100, Currency: "IDR"}
+
+	primary := newMockPayoutProvider("primary")
+	secondary := newMockPayoutProvider("secondary")
+	secondary.On("CreatePayout", ctx, mock.Anything).Return(&external.PayoutResponse{Accepted: true}, nil)
+
+	registry := external.NewPayoutProviderRegistry(2, 50*time.Millisecond)
+	assert.NoError(t, registry.Register(primary, external.PayoutRoute{}))
+	assert.NoError(t, registry.Register(secondary, external.PayoutRoute{}))
+
+	createPayout := func() string {
+		provider, err := registry.Route("bca", amount)
+		assert.NoError(t, err)
+		provider.CreatePayout(ctx, &external.PayoutRequest{Amount: amount})
+		return provider.Name()
+	}
+
+	// a rejected payout still shows the provider is reachable
+	primary.On("CreatePayout", ctx, mock.Anything).Return(&external.PayoutResponse{Accepted: false, Message: "account closed"}, nil).Once()
+	assert.Equal(t, "primary", createPayout())
+
+	primary.On("CreatePayout", ctx, mock.Anything).Return(nil, errors.New("connection refused")).Times(3)
+	assert.Equal(t, "primary", createPayout())
+	assert.True(t, registry.Healthy("primary"))
+	assert.Equal(t, "primary", createPayout())
+	assert.False(t, registry.Healthy("primary"))
+
+	// skipped while unhealthy
+	assert.Equal(t, "secondary", createPayout())
+
+	// after the cooldown only one call probes it, and a single failure makes it unhealthy again
+	time.Sleep(60 * time.Millisecond)
+	probe, err := registry.Route("bca", amount)
+	assert.NoError(t, err)
+	assert.Equal(t, "primary", probe.Name())
+	assert.Equal(t, "secondary", createPayout())
+	probe.CreatePayout(ctx, &external.PayoutRequest{Amount: amount})
+	assert.False(t, registry.Healthy("primary"))
+	assert.Equal(t, "secondary", createPayout())
+
+	// a success makes it healthy for good
+	time.Sleep(60 * time.Millisecond)
+	primary.On("CreatePayout", ctx, mock.Anything).Return(&external.PayoutResponse{Accepted: true}, nil)
+	assert.Equal(t, "primary", createPayout())
+	assert.True(t, registry.Healthy("primary"))
+	assert.Equal(t, "primary", createPayout())
+}
+
+func TestPayoutProviderRegistry_UnusedProbe(t *testing.T) {
+	ctx := context.Background()
+	amount := domain.Money{Amount: 100, Currency: "IDR"}
+
+	primary := newMockPayoutProvider("primary")
+	primary.On("CreatePayout", ctx, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+
+	registry := external.NewPayoutProviderRegistry(1, 50*time.Millisecond)
+	assert.NoError(t, registry.Register(primary, external.PayoutRoute{}))
+
+	provider, err := registry.Route("bca", amount)
+	assert.NoError(t, err)
+	provider.CreatePayout(ctx, &external.PayoutRequest{Amount: amount})
+
+	// the probe is handed out but never used
+	time.Sleep(60 * time.Millisecond)
+	_, err = registry.Route("bca", amount)
+	assert.NoError(t, err)
+	_, err = registry.Route("bca", amount)
+	assert.ErrorIs(t, err, domErr.ErrNoPayoutProvider)
+
+	// it is probed again after another cooldown
+	time.Sleep(60 * time.Millisecond)
+	_, err = registry.Route("bca", amount)
+	assert.NoError(t, err)
+}
+
+func TestNewPayoutProviderRegistryFromConfig(t *testing.T) {
+	t.Run("DefaultsToBank1", func(t *testing.T) {
+		registry, err := external.NewPayoutProviderRegistryFromConfig(&config.Config{})
+		assert.NoError(t, err)
+
+		provider, err := registry.Route("bca", domain.Money{Amount: 100, Currency: "IDR"})
+		assert.NoError(t, err)
+		assert.Equal(t, external.PayoutProviderTypeBank1, provider.Name())
+	})
+
+	t.Run("Providers", func(t *testing.T) {
+		registry, err := external.NewPayoutProviderRegistryFromConfig(&config.Config{Payout: config.PayoutConfig{
+			Providers: []config.PayoutProviderConfig{
+				{Name: "bank1-bca", Type: external.PayoutProviderTypeBank1, BankCodes: []string{"bca"}},
+				{Name: "bank1-idr", Type: external.PayoutProviderTypeBank1, Currencies: []string{"IDR"}},
+			},
+		}})
+		assert.NoError(t, err)
+
+		provider, err := registry.Route("bni", domain.Money{Amount: 100, Currency: "IDR"})
+		assert.NoError(t, err)
+		assert.Equal(t, "bank1-idr", provider.Name())
+
+		_, err = registry.Route("bni", domain.Money{Amount: 100, Currency: "USD"})
+		assert.ErrorIs(t, err, domErr.ErrNoPayoutProvider)
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		_, err := external.NewPayoutProviderRegistryFromConfig(&config.Config{Payout: config.PayoutConfig{
+			Providers: []config.PayoutProviderConfig{{Name: "bank2", Type: "bank2"}},
+		}})
+		assert.Error(t, err)
+	})
+}
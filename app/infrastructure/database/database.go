@@ -8,3 +8,7 @@ import (
 func Init() *sqlx.DB {
 	return sqlite3.Init()
 }
+
+func Open() *sqlx.DB {
+	return sqlite3.Open()
+}
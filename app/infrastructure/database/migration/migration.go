@@ -1,7 +1,11 @@
 package migration
 
 import (
+	"database/sql"
+	"fmt"
 	"log"
+	"strconv"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/krisdioles/ppr-wallet/app/domain"
@@ -10,13 +14,18 @@ import (
 func CreateUserBalancesTable(db *sqlx.DB) {
 	createUserBalancesTableDDL := `CREATE TABLE IF NOT EXISTS user_balances (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
 		username VARCHAR(50) NOT NULL,
+		currency VARCHAR(3) NOT NULL,
 		balance INTEGER,
+		held_balance INTEGER NOT NULL DEFAULT 0,
 		bank_code VARCHAR(50),
 		account_no VARCHAR(50),
 		account_name VARCHAR(100),
+		version INTEGER NOT NULL DEFAULT 0,
 		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (user_id, currency)
 	);`
 
 	log.Println("Create user_balances table...")
@@ -24,8 +33,19 @@ func CreateUserBalancesTable(db *sqlx.DB) {
 	log.Println("user_balances table created, result:", result)
 }
 
+// InsertUserBalancesRecord opens the wallet of userBalance with its ledger accounts. A wallet
+// without a user id belongs to a new user, and one without a currency is in the default currency.
 func InsertUserBalancesRecord(db *sqlx.DB, userBalance domain.UserBalance) {
-	insertUserBalanceDML := `INSERT INTO user_balances(username, balance, bank_code, account_no, account_name) VALUES(:username, :balance, :bank_code, :account_no, :account_name)`
+	if userBalance.UserID == 0 {
+		if err := db.Get(&userBalance.UserID, `SELECT COALESCE(MAX(user_id), 0) + 1 FROM user_balances`); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if userBalance.Currency == "" {
+		userBalance.Currency = domain.DefaultCurrency
+	}
+
+	insertUserBalanceDML := `INSERT INTO user_balances(user_id, username, currency, balance, bank_code, account_no, account_name) VALUES(:user_id, :username, :currency, :balance, :bank_code, :account_no, :account_name)`
 
 	log.Println("Insert user_balances...")
 	result, err := db.NamedExec(insertUserBalanceDML, userBalance)
@@ -33,19 +53,350 @@ func InsertUserBalancesRecord(db *sqlx.DB, userBalance domain.UserBalance) {
 		log.Fatal(err)
 	}
 	log.Println("Insert success. Result:", result)
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, account := range domain.SystemLedgerAccounts(userBalance.Currency) {
+		InsertLedgerAccountRecord(db, *account)
+	}
+	InsertLedgerAccountRecord(db, *domain.NewWalletLedgerAccount(userBalance.UserID, userBalance.Currency, userBalance.Username))
+
+	// the ledger has to explain the balance the wallet starts with
+	if userBalance.Balance > 0 {
+		InsertJournalTransactionRecord(db, domain.NewJournalTransaction(fmt.Sprintf("OPENING-%d", id), "Opening balance").
+			InCurrency(userBalance.Currency).
+			Debit(domain.CurrencyAccountID(domain.OpeningBalanceAccountID, userBalance.Currency), userBalance.Balance).
+			Credit(domain.WalletAccountID(userBalance.UserID, userBalance.Currency), userBalance.Balance).
+			WithMetadata("user_id", strconv.FormatInt(userBalance.UserID, 10)))
+	}
+}
+
+// InsertJournalTransactionRecord posts journal linked into the hash chain of the journal, the
+// same way the service does, so the seeded entries pass the hash chain verification.
+func InsertJournalTransactionRecord(db *sqlx.DB, journal *domain.JournalTransaction) {
+	insertJournalEntryDML := `INSERT INTO journal_entries
+	(account_id, transaction_name, debit_amount, credit_amount, currency, folio, transaction_group_id, posted_at, value_date, metadata, previous_hash, hash) VALUES
+	(:account_id, :transaction_name, :debit_amount, :credit_amount, :currency, :folio, :transaction_group_id, :posted_at, :value_date, :metadata, :previous_hash, :hash)`
+
+	log.Println("Insert journal_entries", journal.Folio, "...")
+	journal.Stamp(time.Now())
+	if err := journal.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	tx := db.MustBegin()
+	previousHash := domain.GenesisHash
+	if err := tx.Get(&previousHash, `SELECT hash FROM journal_entries ORDER BY id DESC LIMIT 1`); err != nil && err != sql.ErrNoRows {
+		log.Fatal(err)
+	}
+	for _, entry := range journal.Entries {
+		entry.PreviousHash = previousHash
+		entry.Hash = entry.ComputeHash()
+		if _, err := tx.NamedExec(insertJournalEntryDML, entry); err != nil {
+			log.Fatal(err)
+		}
+		previousHash = entry.Hash
+	}
+	if err := tx.Commit(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func CreateLedgerAccountsTable(db *sqlx.DB) {
+	createLedgerAccountsTableDDL := `CREATE TABLE IF NOT EXISTS ledger_accounts (
+		id VARCHAR(100) PRIMARY KEY,
+		name VARCHAR(100) NOT NULL,
+		type VARCHAR(20) NOT NULL,
+		normal_balance VARCHAR(10) NOT NULL,
+		currency VARCHAR(3) NOT NULL,
+		owner_user_id INTEGER,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	log.Println("Create ledger_accounts table...")
+	db.MustExec(createLedgerAccountsTableDDL)
+	log.Println("ledger_accounts table created.")
+}
+
+// InsertLedgerAccountRecord registers account, keeping the existing one if it is already registered.
+func InsertLedgerAccountRecord(db *sqlx.DB, account domain.LedgerAccount) {
+	insertLedgerAccountDML := `INSERT INTO ledger_accounts(id, name, type, normal_balance, currency, owner_user_id) VALUES(:id, :name, :type, :normal_balance, :currency, :owner_user_id)
+	ON CONFLICT (id) DO NOTHING`
+
+	log.Println("Insert ledger_accounts", account.ID, "...")
+	if _, err := db.NamedExec(insertLedgerAccountDML, account); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func CreateJournalEntriesTable(db *sqlx.DB) {
 	createJournalEntriesTableDDL := `CREATE TABLE IF NOT EXISTS journal_entries (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		transaction_name VARCHAR(100),
-		account_id INTEGER NOT NULL,
+		account_id VARCHAR(100) NOT NULL REFERENCES ledger_accounts (id),
 		debit_amount INTEGER,
 		credit_amount INTEGER,
-		folio VARCHAR(50)
+		currency VARCHAR(3) NOT NULL,
+		folio VARCHAR(50),
+		transaction_group_id VARCHAR(50) NOT NULL,
+		posted_at TIMESTAMP NOT NULL,
+		value_date DATE NOT NULL,
+		metadata TEXT NOT NULL DEFAULT '{}' CHECK (json_valid(metadata)),
+		previous_hash VARCHAR(64) NOT NULL UNIQUE,
+		hash VARCHAR(64) NOT NULL
 	);`
+	createJournalEntriesAccountIDIndexDDL := `CREATE INDEX IF NOT EXISTS idx_journal_entries_account_id ON journal_entries (account_id);`
+	createJournalEntriesFolioIndexDDL := `CREATE INDEX IF NOT EXISTS idx_journal_entries_folio ON journal_entries (folio);`
+	createJournalEntriesTransactionGroupIDIndexDDL := `CREATE INDEX IF NOT EXISTS idx_journal_entries_transaction_group_id ON journal_entries (transaction_group_id);`
 
 	log.Println("Create journal_entries table...")
 	db.MustExec(createJournalEntriesTableDDL)
+	db.MustExec(createJournalEntriesAccountIDIndexDDL)
+	db.MustExec(createJournalEntriesFolioIndexDDL)
+	db.MustExec(createJournalEntriesTransactionGroupIDIndexDDL)
 	log.Println("journal_entries table created.")
 }
+
+func CreateIdempotencyKeysTable(db *sqlx.DB) {
+	createIdempotencyKeysTableDDL := `CREATE TABLE IF NOT EXISTS idempotency_keys (
+		scope VARCHAR(50) NOT NULL DEFAULT '',
+		idempotency_key VARCHAR(255) NOT NULL,
+		request_fingerprint VARCHAR(64) NOT NULL,
+		response_status INTEGER NOT NULL DEFAULT 0,
+		response_body TEXT NOT NULL DEFAULT '',
+		locked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (scope, idempotency_key)
+	);`
+
+	log.Println("Create idempotency_keys table...")
+	db.MustExec(createIdempotencyKeysTableDDL)
+	log.Println("idempotency_keys table created.")
+}
+
+func CreateDisbursementsTable(db *sqlx.DB) {
+	createDisbursementsTableDDL := `CREATE TABLE IF NOT EXISTS disbursements (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		reference_id VARCHAR(50) NOT NULL UNIQUE,
+		user_id INTEGER NOT NULL,
+		amount INTEGER NOT NULL,
+		currency VARCHAR(3) NOT NULL,
+		bank_code VARCHAR(50),
+		account_no VARCHAR(50),
+		account_name VARCHAR(100),
+		status VARCHAR(20) NOT NULL,
+		provider VARCHAR(50) NOT NULL DEFAULT '',
+		partner_disbursement_id VARCHAR(100) NOT NULL DEFAULT '',
+		failure_reason VARCHAR(255) NOT NULL DEFAULT '',
+		poll_attempts INTEGER NOT NULL DEFAULT 0,
+		next_poll_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		submitted_at TIMESTAMP,
+		completed_at TIMESTAMP,
+		failed_at TIMESTAMP,
+		reversed_at TIMESTAMP
+	);`
+	createDisbursementsUserIDIndexDDL := `CREATE INDEX IF NOT EXISTS idx_disbursements_user_id ON disbursements (user_id);`
+	createDisbursementsStatusIndexDDL := `CREATE INDEX IF NOT EXISTS idx_disbursements_status_next_poll_at ON disbursements (status, next_poll_at);`
+	createDisbursementsPartnerIDIndexDDL := `CREATE INDEX IF NOT EXISTS idx_disbursements_partner_disbursement_id ON disbursements (partner_disbursement_id);`
+	createDisbursementsCompletedAtIndexDDL := `CREATE INDEX IF NOT EXISTS idx_disbursements_completed_at ON disbursements (completed_at);`
+
+	log.Println("Create disbursements table...")
+	db.MustExec(createDisbursementsTableDDL)
+	db.MustExec(createDisbursementsUserIDIndexDDL)
+	db.MustExec(createDisbursementsStatusIndexDDL)
+	db.MustExec(createDisbursementsPartnerIDIndexDDL)
+	db.MustExec(createDisbursementsCompletedAtIndexDDL)
+	log.Println("disbursements table created.")
+}
+
+func CreateTopUpsTable(db *sqlx.DB) {
+	createTopUpsTableDDL := `CREATE TABLE IF NOT EXISTS top_ups (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		reference_id VARCHAR(50) NOT NULL UNIQUE,
+		user_id INTEGER NOT NULL,
+		amount INTEGER NOT NULL,
+		currency VARCHAR(3) NOT NULL,
+		source_reference VARCHAR(100) NOT NULL UNIQUE,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	log.Println("Create top_ups table...")
+	db.MustExec(createTopUpsTableDDL)
+	log.Println("top_ups table created.")
+}
+
+func CreateTransfersTable(db *sqlx.DB) {
+	createTransfersTableDDL := `CREATE TABLE IF NOT EXISTS transfers (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		reference_id VARCHAR(50) NOT NULL UNIQUE,
+		from_user_id INTEGER NOT NULL,
+		to_user_id INTEGER NOT NULL,
+		amount INTEGER NOT NULL,
+		currency VARCHAR(3) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+	createTransfersFromUserIDIndexDDL := `CREATE INDEX IF NOT EXISTS idx_transfers_from_user_id ON transfers (from_user_id);`
+	createTransfersToUserIDIndexDDL := `CREATE INDEX IF NOT EXISTS idx_transfers_to_user_id ON transfers (to_user_id);`
+
+	log.Println("Create transfers table...")
+	db.MustExec(createTransfersTableDDL)
+	db.MustExec(createTransfersFromUserIDIndexDDL)
+	db.MustExec(createTransfersToUserIDIndexDDL)
+	log.Println("transfers table created.")
+}
+
+func CreateReversalsTable(db *sqlx.DB) {
+	createReversalsTableDDL := `CREATE TABLE IF NOT EXISTS reversals (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		reference_id VARCHAR(50) NOT NULL UNIQUE,
+		original_folio VARCHAR(50) NOT NULL UNIQUE,
+		transaction_group_id VARCHAR(50) NOT NULL,
+		reason VARCHAR(255) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	log.Println("Create reversals table...")
+	db.MustExec(createReversalsTableDDL)
+	log.Println("reversals table created.")
+}
+
+func CreateAccountingPeriodsTable(db *sqlx.DB) {
+	createAccountingPeriodsTableDDL := `CREATE TABLE IF NOT EXISTS accounting_periods (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		period VARCHAR(7) NOT NULL UNIQUE,
+		start_date DATE NOT NULL,
+		end_date DATE NOT NULL,
+		status VARCHAR(10) NOT NULL,
+		closed_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+	createAccountingPeriodClosingBalancesTableDDL := `CREATE TABLE IF NOT EXISTS accounting_period_closing_balances (
+		period_id INTEGER NOT NULL REFERENCES accounting_periods (id),
+		account_id VARCHAR(100) NOT NULL REFERENCES ledger_accounts (id),
+		currency VARCHAR(3) NOT NULL,
+		total_debit INTEGER NOT NULL,
+		total_credit INTEGER NOT NULL,
+		PRIMARY KEY (period_id, account_id)
+	);`
+
+	log.Println("Create accounting_periods table...")
+	db.MustExec(createAccountingPeriodsTableDDL)
+	db.MustExec(createAccountingPeriodClosingBalancesTableDDL)
+	log.Println("accounting_periods table created.")
+}
+
+func CreateFXRatesTable(db *sqlx.DB) {
+	createFXRatesTableDDL := `CREATE TABLE IF NOT EXISTS fx_rates (
+		base_currency VARCHAR(3) NOT NULL,
+		quote_currency VARCHAR(3) NOT NULL,
+		rate VARCHAR(50) NOT NULL,
+		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (base_currency, quote_currency)
+	);`
+
+	log.Println("Create fx_rates table...")
+	db.MustExec(createFXRatesTableDDL)
+	log.Println("fx_rates table created.")
+}
+
+func CreateFXQuotesTable(db *sqlx.DB) {
+	createFXQuotesTableDDL := `CREATE TABLE IF NOT EXISTS fx_quotes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		reference_id VARCHAR(50) NOT NULL UNIQUE,
+		user_id INTEGER NOT NULL,
+		from_currency VARCHAR(3) NOT NULL,
+		from_amount INTEGER NOT NULL,
+		to_currency VARCHAR(3) NOT NULL,
+		to_amount INTEGER NOT NULL,
+		rate VARCHAR(50) NOT NULL,
+		expires_at TIMESTAMP NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	log.Println("Create fx_quotes table...")
+	db.MustExec(createFXQuotesTableDDL)
+	log.Println("fx_quotes table created.")
+}
+
+func CreateConversionsTable(db *sqlx.DB) {
+	createConversionsTableDDL := `CREATE TABLE IF NOT EXISTS conversions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		reference_id VARCHAR(50) NOT NULL UNIQUE,
+		quote_id VARCHAR(50) NOT NULL UNIQUE REFERENCES fx_quotes (reference_id),
+		user_id INTEGER NOT NULL,
+		from_currency VARCHAR(3) NOT NULL,
+		from_amount INTEGER NOT NULL,
+		to_currency VARCHAR(3) NOT NULL,
+		to_amount INTEGER NOT NULL,
+		rate VARCHAR(50) NOT NULL,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+	createConversionsUserIDIndexDDL := `CREATE INDEX IF NOT EXISTS idx_conversions_user_id ON conversions (user_id);`
+
+	log.Println("Create conversions table...")
+	db.MustExec(createConversionsTableDDL)
+	db.MustExec(createConversionsUserIDIndexDDL)
+	log.Println("conversions table created.")
+}
+
+func CreateReconciliationsTable(db *sqlx.DB) {
+	createReconciliationsTableDDL := `CREATE TABLE IF NOT EXISTS reconciliations (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		file_name VARCHAR(255) NOT NULL,
+		provider VARCHAR(50) NOT NULL DEFAULT '',
+		settlement_date DATE NOT NULL,
+		matched INTEGER NOT NULL DEFAULT 0,
+		missing_on_our_side INTEGER NOT NULL DEFAULT 0,
+		missing_at_bank INTEGER NOT NULL DEFAULT 0,
+		amount_mismatch INTEGER NOT NULL DEFAULT 0,
+		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+	);`
+	createReconciliationLinesTableDDL := `CREATE TABLE IF NOT EXISTS reconciliation_lines (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		reconciliation_id INTEGER NOT NULL REFERENCES reconciliations (id),
+		line_no INTEGER NOT NULL,
+		reference VARCHAR(100) NOT NULL,
+		currency VARCHAR(3) NOT NULL,
+		bank_amount INTEGER NOT NULL,
+		our_amount INTEGER NOT NULL,
+		status VARCHAR(20) NOT NULL,
+		folio VARCHAR(100) NOT NULL DEFAULT '',
+		note VARCHAR(255) NOT NULL DEFAULT ''
+	);`
+	createReconciliationLinesReconciliationIDIndexDDL := `CREATE INDEX IF NOT EXISTS idx_reconciliation_lines_reconciliation_id ON reconciliation_lines (reconciliation_id);`
+
+	log.Println("Create reconciliations table...")
+	db.MustExec(createReconciliationsTableDDL)
+	db.MustExec(createReconciliationLinesTableDDL)
+	db.MustExec(createReconciliationLinesReconciliationIDIndexDDL)
+	log.Println("reconciliations table created.")
+}
+
+func CreateBalanceVerificationsTable(db *sqlx.DB) {
+	createBalanceVerificationsTableDDL := `CREATE TABLE IF NOT EXISTS balance_verifications (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		checked_at TIMESTAMP NOT NULL,
+		wallets_checked INTEGER NOT NULL DEFAULT 0
+	);`
+	createBalanceMismatchesTableDDL := `CREATE TABLE IF NOT EXISTS balance_mismatches (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		verification_id INTEGER NOT NULL REFERENCES balance_verifications (id),
+		user_id INTEGER NOT NULL,
+		account_id VARCHAR(100) NOT NULL,
+		wallet_balance INTEGER NOT NULL,
+		ledger_balance INTEGER NOT NULL,
+		difference INTEGER NOT NULL
+	);`
+	createBalanceMismatchesVerificationIDIndexDDL := `CREATE INDEX IF NOT EXISTS idx_balance_mismatches_verification_id ON balance_mismatches (verification_id);`
+
+	log.Println("Create balance_verifications table...")
+	db.MustExec(createBalanceVerificationsTableDDL)
+	db.MustExec(createBalanceMismatchesTableDDL)
+	db.MustExec(createBalanceMismatchesVerificationIDIndexDDL)
+	log.Println("balance_verifications table created.")
+}
@@ -10,6 +10,18 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// transactions take the write lock up front and wait for it instead of failing with SQLITE_BUSY
+const dataSourceName = "./database.db?_busy_timeout=5000&_txlock=immediate&_foreign_keys=on"
+
+// Open connects to the existing database.db without migrating it.
+func Open() *sqlx.DB {
+	if _, err := os.Stat("database.db"); err != nil {
+		log.Fatal(err)
+	}
+
+	return sqlx.MustConnect("sqlite3", dataSourceName)
+}
+
 func Init() *sqlx.DB {
 	os.Remove("database.db")
 
@@ -21,10 +33,26 @@ func Init() *sqlx.DB {
 	file.Close()
 	log.Println("sqlite3 database.db created.")
 
-	sqliteDb := sqlx.MustConnect("sqlite3", "./database.db")
+	sqliteDb := sqlx.MustConnect("sqlite3", dataSourceName)
 
+	migration.CreateLedgerAccountsTable(sqliteDb)
 	migration.CreateUserBalancesTable(sqliteDb)
 	migration.CreateJournalEntriesTable(sqliteDb)
+	migration.CreateAccountingPeriodsTable(sqliteDb)
+	migration.CreateIdempotencyKeysTable(sqliteDb)
+	migration.CreateDisbursementsTable(sqliteDb)
+	migration.CreateTopUpsTable(sqliteDb)
+	migration.CreateTransfersTable(sqliteDb)
+	migration.CreateReversalsTable(sqliteDb)
+	migration.CreateFXRatesTable(sqliteDb)
+	migration.CreateFXQuotesTable(sqliteDb)
+	migration.CreateConversionsTable(sqliteDb)
+	migration.CreateReconciliationsTable(sqliteDb)
+	migration.CreateBalanceVerificationsTable(sqliteDb)
+
+	for _, account := range domain.SystemLedgerAccounts(domain.DefaultCurrency) {
+		migration.InsertLedgerAccountRecord(sqliteDb, *account)
+	}
 
 	// development purpose, should be deleted when ready to be pushed to prod
 	migration.InsertUserBalancesRecord(sqliteDb, domain.UserBalance{
@@ -7,13 +7,51 @@ import (
 )
 
 type Repository struct {
-	UserBalanceRepository  domain.UserBalanceRepository
-	JournalEntryRepository domain.JournalEntryRepository
+	TransactionManager domain.TransactionManager
+
+	UserBalanceRepository   domain.UserBalanceRepository
+	JournalEntryRepository  domain.JournalEntryRepository
+	LedgerAccountRepository domain.LedgerAccountRepository
+
+	AccountingPeriodRepository domain.AccountingPeriodRepository
+
+	IdempotencyKeyRepository domain.IdempotencyKeyRepository
+	DisbursementRepository   domain.DisbursementRepository
+	TopUpRepository          domain.TopUpRepository
+	TransferRepository       domain.TransferRepository
+	ReversalRepository       domain.ReversalRepository
+
+	FXRateRepository     domain.FXRateRepository
+	FXQuoteRepository    domain.FXQuoteRepository
+	ConversionRepository domain.ConversionRepository
+
+	ReconciliationRepository      domain.ReconciliationRepository
+	BalanceVerificationRepository domain.BalanceVerificationRepository
 }
 
 func InitRepositories(db *sqlx.DB) *Repository {
+	accountingPeriodRepository := repository.NewAccountingPeriodRepository(db)
+
 	return &Repository{
-		UserBalanceRepository:  repository.NewUserBalanceRepository(db),
-		JournalEntryRepository: repository.NewJournalEntryRepository(db),
+		TransactionManager: repository.NewTransactionManager(db),
+
+		UserBalanceRepository:   repository.NewUserBalanceRepository(db),
+		JournalEntryRepository:  repository.NewJournalEntryRepository(db, accountingPeriodRepository),
+		LedgerAccountRepository: repository.NewLedgerAccountRepository(db),
+
+		AccountingPeriodRepository: accountingPeriodRepository,
+
+		IdempotencyKeyRepository: repository.NewIdempotencyKeyRepository(db),
+		DisbursementRepository:   repository.NewDisbursementRepository(db),
+		TopUpRepository:          repository.NewTopUpRepository(db),
+		TransferRepository:       repository.NewTransferRepository(db),
+		ReversalRepository:       repository.NewReversalRepository(db),
+
+		FXRateRepository:     repository.NewFXRateRepository(db),
+		FXQuoteRepository:    repository.NewFXQuoteRepository(db),
+		ConversionRepository: repository.NewConversionRepository(db),
+
+		ReconciliationRepository:      repository.NewReconciliationRepository(db),
+		BalanceVerificationRepository: repository.NewBalanceVerificationRepository(db),
 	}
 }
@@ -1,21 +1,67 @@
 package provider
 
 import (
+	"log"
+
 	"github.com/krisdioles/ppr-wallet/app/domain"
 	"github.com/krisdioles/ppr-wallet/app/external"
 	"github.com/krisdioles/ppr-wallet/app/usecase"
 	"github.com/krisdioles/ppr-wallet/config"
+	"github.com/krisdioles/ppr-wallet/pkg/refid"
 )
 
+const referenceIDPrefix = "WLT"
+
 type Usecase struct {
-	UserBalanceUsecase domain.UserBalanceUsecase
-	Bank1Client        external.Bank1Client
+	UserBalanceUsecase     domain.UserBalanceUsecase
+	PayoutProviderRegistry *external.PayoutProviderRegistry
+
+	IdempotencyKeyUsecase      domain.IdempotencyKeyUsecase
+	DisbursementUsecase        domain.DisbursementUsecase
+	DisbursementPollingUsecase domain.DisbursementPollingUsecase
+	TransferUsecase            domain.TransferUsecase
+	LedgerUsecase              domain.LedgerUsecase
+	JournalEntryUsecase        domain.JournalEntryUsecase
+	ReportUsecase              domain.ReportUsecase
+	ReversalUsecase            domain.ReversalUsecase
+
+	AccountingPeriodUsecase domain.AccountingPeriodUsecase
+	ReconciliationUsecase   domain.ReconciliationUsecase
+
+	FXUsecase         domain.FXUsecase
+	ConversionUsecase domain.ConversionUsecase
 }
 
 func InitUsecases(cfg *config.Config, repo *Repository) *Usecase {
-	bank1Client := external.NewBank1Client(&cfg.Bank1)
+	payoutProviderRegistry, err := external.NewPayoutProviderRegistryFromConfig(cfg)
+	if err != nil {
+		log.Fatal("init payout providers err: ", err)
+	}
+	referenceIDGenerator := refid.NewGenerator(referenceIDPrefix)
+	userBalanceUsecase := usecase.NewUserBalanceUsecase(repo.TransactionManager, repo.UserBalanceRepository, repo.JournalEntryRepository, repo.LedgerAccountRepository, repo.DisbursementRepository, repo.TopUpRepository, payoutProviderRegistry, referenceIDGenerator)
 
 	return &Usecase{
-		UserBalanceUsecase: usecase.NewUserBalanceUsecase(repo.UserBalanceRepository, repo.JournalEntryRepository, bank1Client),
+		UserBalanceUsecase:     userBalanceUsecase,
+		PayoutProviderRegistry: payoutProviderRegistry,
+
+		IdempotencyKeyUsecase: usecase.NewIdempotencyKeyUsecase(repo.IdempotencyKeyRepository, cfg.Server.IdempotencyKeyLockTimeout),
+		DisbursementUsecase:   usecase.NewDisbursementUsecase(repo.DisbursementRepository, repo.UserBalanceRepository),
+		DisbursementPollingUsecase: usecase.NewDisbursementPollingUsecase(repo.DisbursementRepository, userBalanceUsecase, payoutProviderRegistry, domain.DisbursementPollPolicy{
+			Backoff:        cfg.Worker.DisbursementPollBackoff,
+			MaxBackoff:     cfg.Worker.DisbursementPollMaxBackoff,
+			ReviewDeadline: cfg.Worker.DisbursementReviewDeadline,
+			PendingTimeout: cfg.Worker.DisbursementPendingTimeout,
+		}),
+		TransferUsecase:     usecase.NewTransferUsecase(repo.TransactionManager, repo.UserBalanceRepository, repo.JournalEntryRepository, repo.TransferRepository, referenceIDGenerator),
+		LedgerUsecase:       usecase.NewLedgerUsecase(repo.TransactionManager, repo.UserBalanceRepository, repo.JournalEntryRepository, repo.BalanceVerificationRepository),
+		JournalEntryUsecase: usecase.NewJournalEntryUsecase(repo.TransactionManager, repo.UserBalanceRepository, repo.JournalEntryRepository),
+		ReportUsecase:       usecase.NewReportUsecase(repo.JournalEntryRepository, repo.AccountingPeriodRepository),
+		ReversalUsecase:     usecase.NewReversalUsecase(repo.TransactionManager, repo.UserBalanceRepository, repo.JournalEntryRepository, repo.DisbursementRepository, repo.ReversalRepository, referenceIDGenerator),
+
+		AccountingPeriodUsecase: usecase.NewAccountingPeriodUsecase(repo.TransactionManager, repo.JournalEntryRepository, repo.AccountingPeriodRepository),
+		ReconciliationUsecase:   usecase.NewReconciliationUsecase(repo.ReconciliationRepository, repo.DisbursementRepository, repo.JournalEntryRepository),
+
+		FXUsecase:         usecase.NewFXUsecase(repo.TransactionManager, repo.UserBalanceRepository, repo.FXRateRepository, repo.FXQuoteRepository, referenceIDGenerator, cfg.FX.QuoteTTL),
+		ConversionUsecase: usecase.NewConversionUsecase(repo.TransactionManager, repo.UserBalanceRepository, repo.JournalEntryRepository, repo.FXQuoteRepository, repo.ConversionRepository, referenceIDGenerator),
 	}
 }
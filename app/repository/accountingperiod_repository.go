@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type AccountingPeriodRepository struct {
+	DB *sqlx.DB
+}
+
+func NewAccountingPeriodRepository(db *sqlx.DB) *AccountingPeriodRepository {
+	return &AccountingPeriodRepository{
+		DB: db,
+	}
+}
+
+func (r *AccountingPeriodRepository) Create(ctx context.Context, period *domain.AccountingPeriod) (*domain.AccountingPeriod, error) {
+	createAccountingPeriodQuery := `INSERT INTO accounting_periods 
+	(period, start_date, end_date, status, created_at) VALUES
+	(:period, :start_date, :end_date, :status, :created_at)
+	ON CONFLICT (period) DO NOTHING`
+
+	if period.CreatedAt.IsZero() {
+		period.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := conn(ctx, r.DB).NamedExecContext(ctx, createAccountingPeriodQuery, period)
+	if err != nil {
+		log.Println("[Create] query err:", err)
+		return &domain.AccountingPeriod{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("[Create] rows affected err:", err)
+		return &domain.AccountingPeriod{}, err
+	}
+
+	if rowsAffected == 0 {
+		return &domain.AccountingPeriod{}, errors.ErrAccountingPeriodAlreadyExists
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		log.Println("[Create] last insert id err:", err)
+		return &domain.AccountingPeriod{}, err
+	}
+	period.ID = id
+
+	return period, nil
+}
+
+func (r *AccountingPeriodRepository) GetByPeriod(ctx context.Context, period string) (*domain.AccountingPeriod, error) {
+	getByPeriodQuery := `SELECT * FROM accounting_periods WHERE period = ?`
+
+	var accountingPeriod = &domain.AccountingPeriod{}
+	if err := conn(ctx, r.DB).GetContext(ctx, accountingPeriod, getByPeriodQuery, period); err != nil {
+		log.Println("[GetByPeriod] query err:", err)
+		return accountingPeriod, err
+	}
+
+	return accountingPeriod, nil
+}
+
+func (r *AccountingPeriodRepository) GetAll(ctx context.Context) ([]*domain.AccountingPeriod, error) {
+	getAllQuery := `SELECT * FROM accounting_periods ORDER BY start_date`
+
+	var accountingPeriods []*domain.AccountingPeriod
+	if err := conn(ctx, r.DB).SelectContext(ctx, &accountingPeriods, getAllQuery); err != nil {
+		log.Println("[GetAll] query err:", err)
+		return nil, err
+	}
+
+	return accountingPeriods, nil
+}
+
+// GetLatestClosed returns the closed period that ends last, but not after endingBy. It fails
+// with sql.ErrNoRows when no such period was closed.
+func (r *AccountingPeriodRepository) GetLatestClosed(ctx context.Context, endingBy time.Time) (*domain.AccountingPeriod, error) {
+	getLatestClosedQuery := `SELECT * FROM accounting_periods WHERE status = ? AND strftime('%Y-%m-%d %H:%M:%f', end_date) <= strftime('%Y-%m-%d %H:%M:%f', ?) ORDER BY end_date DESC LIMIT 1`
+
+	var accountingPeriod = &domain.AccountingPeriod{}
+	if err := conn(ctx, r.DB).GetContext(ctx, accountingPeriod, getLatestClosedQuery, domain.AccountingPeriodStatusClosed, endingBy.UTC()); err != nil {
+		log.Println("[GetLatestClosed] query err:", err)
+		return accountingPeriod, err
+	}
+
+	return accountingPeriod, nil
+}
+
+// IsClosed reports whether date is before the end of a closed period. Periods are closed in
+// order, so this locks the closed periods and everything before them.
+func (r *AccountingPeriodRepository) IsClosed(ctx context.Context, date time.Time) (bool, error) {
+	isClosedQuery := `SELECT EXISTS (SELECT 1 FROM accounting_periods WHERE status = ? AND strftime('%Y-%m-%d %H:%M:%f', end_date) > strftime('%Y-%m-%d %H:%M:%f', ?))`
+
+	var closed bool
+	if err := conn(ctx, r.DB).GetContext(ctx, &closed, isClosedQuery, domain.AccountingPeriodStatusClosed, date.UTC()); err != nil {
+		log.Println("[IsClosed] query err:", err)
+		return false, err
+	}
+
+	return closed, nil
+}
+
+// HasOpenBefore reports whether a period that starts before startDate is still open.
+func (r *AccountingPeriodRepository) HasOpenBefore(ctx context.Context, startDate time.Time) (bool, error) {
+	hasOpenBeforeQuery := `SELECT EXISTS (SELECT 1 FROM accounting_periods WHERE status = ? AND strftime('%Y-%m-%d %H:%M:%f', start_date) < strftime('%Y-%m-%d %H:%M:%f', ?))`
+
+	var hasOpen bool
+	if err := conn(ctx, r.DB).GetContext(ctx, &hasOpen, hasOpenBeforeQuery, domain.AccountingPeriodStatusOpen, startDate.UTC()); err != nil {
+		log.Println("[HasOpenBefore] query err:", err)
+		return false, err
+	}
+
+	return hasOpen, nil
+}
+
+// GetClosingBalances returns the balances stored when the period was closed.
+func (r *AccountingPeriodRepository) GetClosingBalances(ctx context.Context, periodID int64) ([]*domain.LedgerAccountBalance, error) {
+	getClosingBalancesQuery := `SELECT la.id AS account_id, la.name, la.type, la.normal_balance, cb.currency, cb.total_debit, cb.total_credit
+	FROM accounting_period_closing_balances cb JOIN ledger_accounts la ON la.id = cb.account_id
+	WHERE cb.period_id = ?
+	ORDER BY la.id`
+
+	var accountBalances []*domain.LedgerAccountBalance
+	if err := conn(ctx, r.DB).SelectContext(ctx, &accountBalances, getClosingBalancesQuery, periodID); err != nil {
+		log.Println("[GetClosingBalances] query err:", err)
+		return nil, err
+	}
+
+	for _, accountBalance := range accountBalances {
+		accountBalance.CalculateBalance()
+	}
+
+	return accountBalances, nil
+}
+
+// Close stores the closed state of period together with its closing balances. Only an open
+// period is closed, so two concurrent closes cannot both store balances.
+func (r *AccountingPeriodRepository) Close(ctx context.Context, period *domain.AccountingPeriod) error {
+	closeQuery := `UPDATE accounting_periods SET status = ?, closed_at = ? WHERE id = ? AND status = ?`
+	createClosingBalanceQuery := `INSERT INTO accounting_period_closing_balances 
+	(period_id, account_id, currency, total_debit, total_credit) VALUES
+	(?, ?, ?, ?, ?)`
+
+	return NewTransactionManager(r.DB).WithinTransaction(ctx, func(txCtx context.Context) error {
+		result, err := conn(txCtx, r.DB).ExecContext(txCtx, closeQuery, period.Status, period.ClosedAt, period.ID, domain.AccountingPeriodStatusOpen)
+		if err != nil {
+			log.Println("[Close] query err:", err)
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			log.Println("[Close] rows affected err:", err)
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return errors.ErrAccountingPeriodClosed
+		}
+
+		for _, accountBalance := range period.ClosingBalances {
+			if _, err := conn(txCtx, r.DB).ExecContext(txCtx, createClosingBalanceQuery, period.ID, accountBalance.AccountID, accountBalance.Currency, accountBalance.TotalDebit, accountBalance.TotalCredit); err != nil {
+				log.Println("[Close] create closing balance err:", err)
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,191 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountingPeriodRepository_Create(t *testing.T) {
+	// Create a mock DB and expect the named exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.AccountingPeriodRepository{DB: sqlxDB}
+
+	accountingPeriod, err := domain.NewAccountingPeriod("2026-03")
+	assert.NoError(t, err)
+
+	mock.ExpectExec("INSERT INTO accounting_periods \\(period, start_date, end_date, status, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?\\) ON CONFLICT \\(period\\) DO NOTHING").
+		WithArgs("2026-03", accountingPeriod.StartDate, accountingPeriod.EndDate, domain.AccountingPeriodStatusOpen, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	// Execute the function
+	result, err := repo.Create(context.Background(), accountingPeriod)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccountingPeriodRepository_Create_AlreadyExists(t *testing.T) {
+	// Create a mock DB and expect the named exec to hit the conflict clause
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.AccountingPeriodRepository{DB: sqlxDB}
+
+	mock.ExpectExec("INSERT INTO accounting_periods").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute the function
+	_, err = repo.Create(context.Background(), &domain.AccountingPeriod{Period: "2026-03"})
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrAccountingPeriodAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccountingPeriodRepository_GetLatestClosed_NotFound(t *testing.T) {
+	// Create a mock DB and expect the query to find nothing
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.AccountingPeriodRepository{DB: sqlxDB}
+
+	endingBy := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT \\* FROM accounting_periods WHERE status = \\? AND strftime\\('%Y-%m-%d %H:%M:%f', end_date\\) <= strftime\\('%Y-%m-%d %H:%M:%f', \\?\\) ORDER BY end_date DESC LIMIT 1").
+		WithArgs(domain.AccountingPeriodStatusClosed, endingBy).
+		WillReturnError(sql.ErrNoRows)
+
+	// Execute the function
+	_, err = repo.GetLatestClosed(context.Background(), endingBy)
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccountingPeriodRepository_IsClosed(t *testing.T) {
+	// Create a mock DB and expect the query for a closed period ending after the date
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.AccountingPeriodRepository{DB: sqlxDB}
+
+	date := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM accounting_periods WHERE status = \\? AND strftime\\('%Y-%m-%d %H:%M:%f', end_date\\) > strftime\\('%Y-%m-%d %H:%M:%f', \\?\\)\\)").
+		WithArgs(domain.AccountingPeriodStatusClosed, date).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	// Execute the function
+	closed, err := repo.IsClosed(context.Background(), date)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.True(t, closed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccountingPeriodRepository_GetClosingBalances(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.AccountingPeriodRepository{DB: sqlxDB}
+
+	rows := sqlmock.NewRows([]string{"account_id", "name", "type", "normal_balance", "currency", "total_debit", "total_credit"}).
+		AddRow("fee-income", "Fee income", domain.LedgerAccountTypeRevenue, domain.NormalBalanceCredit, "IDR", 0, 100)
+
+	mock.ExpectQuery("SELECT (.+) FROM accounting_period_closing_balances cb JOIN ledger_accounts la ON la.id = cb.account_id WHERE cb.period_id = \\?").
+		WithArgs(int64(2)).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetClosingBalances(context.Background(), 2)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, int64(100), result[0].Balance)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccountingPeriodRepository_Close(t *testing.T) {
+	// Create a mock DB and expect the update and the closing balances in one transaction
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.AccountingPeriodRepository{DB: sqlxDB}
+
+	closedAt := time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC)
+	accountingPeriod := &domain.AccountingPeriod{
+		ID:       2,
+		Period:   "2026-03",
+		Status:   domain.AccountingPeriodStatusClosed,
+		ClosedAt: &closedAt,
+		ClosingBalances: []*domain.LedgerAccountBalance{
+			{AccountID: "fee-income", Currency: "IDR", TotalCredit: 100},
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE accounting_periods SET status = \\?, closed_at = \\? WHERE id = \\? AND status = \\?").
+		WithArgs(domain.AccountingPeriodStatusClosed, &closedAt, int64(2), domain.AccountingPeriodStatusOpen).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO accounting_period_closing_balances \\(period_id, account_id, currency, total_debit, total_credit\\) VALUES \\(\\?, \\?, \\?, \\?, \\?\\)").
+		WithArgs(int64(2), "fee-income", "IDR", int64(0), int64(100)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Execute the function
+	err = repo.Close(context.Background(), accountingPeriod)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAccountingPeriodRepository_Close_AlreadyClosed(t *testing.T) {
+	// Create a mock DB and expect the update to find no open period
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.AccountingPeriodRepository{DB: sqlxDB}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE accounting_periods").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	// Execute the function
+	err = repo.Close(context.Background(), &domain.AccountingPeriod{ID: 2, Status: domain.AccountingPeriodStatusClosed})
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrAccountingPeriodClosed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+)
+
+type BalanceVerificationRepository struct {
+	DB *sqlx.DB
+}
+
+func NewBalanceVerificationRepository(db *sqlx.DB) *BalanceVerificationRepository {
+	return &BalanceVerificationRepository{
+		DB: db,
+	}
+}
+
+// Create stores report together with its mismatches. It must run within the transaction
+// carried by ctx, so a report is never stored without its mismatches.
+func (r *BalanceVerificationRepository) Create(ctx context.Context, report *domain.BalanceVerificationReport) (*domain.BalanceVerificationReport, error) {
+	createVerificationQuery := `INSERT INTO balance_verifications
+	(checked_at, wallets_checked) VALUES
+	(:checked_at, :wallets_checked)`
+	createMismatchQuery := `INSERT INTO balance_mismatches
+	(verification_id, user_id, account_id, wallet_balance, ledger_balance, difference) VALUES
+	(:verification_id, :user_id, :account_id, :wallet_balance, :ledger_balance, :difference)`
+
+	result, err := conn(ctx, r.DB).NamedExecContext(ctx, createVerificationQuery, report)
+	if err != nil {
+		log.Println("[Create] query err:", err)
+		return &domain.BalanceVerificationReport{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		log.Println("[Create] last insert id err:", err)
+		return &domain.BalanceVerificationReport{}, err
+	}
+	report.ID = id
+
+	for _, mismatch := range report.Mismatches {
+		mismatch.VerificationID = id
+
+		result, err := conn(ctx, r.DB).NamedExecContext(ctx, createMismatchQuery, mismatch)
+		if err != nil {
+			log.Println("[Create] create mismatch err:", err)
+			return &domain.BalanceVerificationReport{}, err
+		}
+
+		mismatchID, err := result.LastInsertId()
+		if err != nil {
+			log.Println("[Create] mismatch last insert id err:", err)
+			return &domain.BalanceVerificationReport{}, err
+		}
+		mismatch.ID = mismatchID
+	}
+
+	return report, nil
+}
+
+func (r *BalanceVerificationRepository) GetAll(ctx context.Context) ([]*domain.BalanceVerificationReport, error) {
+	getAllQuery := `SELECT * FROM balance_verifications ORDER BY id DESC`
+
+	var reports = []*domain.BalanceVerificationReport{}
+	if err := conn(ctx, r.DB).SelectContext(ctx, &reports, getAllQuery); err != nil {
+		log.Println("[GetAll] query err:", err)
+		return reports, err
+	}
+
+	return reports, nil
+}
+
+func (r *BalanceVerificationRepository) GetMismatches(ctx context.Context, verificationID int64) ([]*domain.BalanceMismatch, error) {
+	getMismatchesQuery := `SELECT * FROM balance_mismatches WHERE verification_id = ? ORDER BY id`
+
+	var mismatches = []*domain.BalanceMismatch{}
+	if err := conn(ctx, r.DB).SelectContext(ctx, &mismatches, getMismatchesQuery, verificationID); err != nil {
+		log.Println("[GetMismatches] query err:", err)
+		return mismatches, err
+	}
+
+	return mismatches, nil
+}
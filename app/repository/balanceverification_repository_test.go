@@ -0,0 +1,127 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalanceVerificationRepository_Create(t *testing.T) {
+	// Create a mock DB and expect the verification and its mismatches
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.BalanceVerificationRepository{DB: sqlxDB}
+
+	checkedAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	report := &domain.BalanceVerificationReport{
+		CheckedAt:      checkedAt,
+		WalletsChecked: 3,
+		Mismatches: []*domain.BalanceMismatch{
+			{UserID: 2, AccountID: "wallet:2", WalletBalance: 1200, LedgerBalance: 1500, Difference: -300},
+		},
+	}
+
+	mock.ExpectExec("INSERT INTO balance_verifications").
+		WithArgs(checkedAt, 3).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO balance_mismatches").
+		WithArgs(int64(7), int64(2), "wallet:2", int64(1200), int64(1500), int64(-300)).
+		WillReturnResult(sqlmock.NewResult(12, 1))
+
+	// Execute the function
+	result, err := repo.Create(context.Background(), report)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), result.ID)
+	assert.Equal(t, int64(7), result.Mismatches[0].VerificationID)
+	assert.Equal(t, int64(12), result.Mismatches[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBalanceVerificationRepository_Create_Error(t *testing.T) {
+	// Create a mock DB and expect the mismatch insert to fail
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.BalanceVerificationRepository{DB: sqlxDB}
+
+	report := &domain.BalanceVerificationReport{
+		CheckedAt:  time.Now().UTC(),
+		Mismatches: []*domain.BalanceMismatch{{UserID: 2, AccountID: "wallet:2"}},
+	}
+
+	mock.ExpectExec("INSERT INTO balance_verifications").
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec("INSERT INTO balance_mismatches").
+		WillReturnError(errors.New("insert error"))
+
+	// Execute the function
+	_, err = repo.Create(context.Background(), report)
+
+	// Assert the expectations
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBalanceVerificationRepository_GetAll(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.BalanceVerificationRepository{DB: sqlxDB}
+
+	rows := sqlmock.NewRows([]string{"id", "wallets_checked"}).
+		AddRow(8, 3).
+		AddRow(7, 3)
+
+	mock.ExpectQuery("SELECT \\* FROM balance_verifications ORDER BY id DESC").
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetAll(context.Background())
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.BalanceVerificationReport{{ID: 8, WalletsChecked: 3}, {ID: 7, WalletsChecked: 3}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBalanceVerificationRepository_GetMismatches(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.BalanceVerificationRepository{DB: sqlxDB}
+
+	rows := sqlmock.NewRows([]string{"id", "verification_id", "user_id", "account_id", "difference"}).
+		AddRow(12, 7, 2, "wallet:2", -300)
+
+	mock.ExpectQuery("SELECT \\* FROM balance_mismatches WHERE verification_id = \\? ORDER BY id").
+		WithArgs(int64(7)).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetMismatches(context.Background(), 7)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.BalanceMismatch{{ID: 12, VerificationID: 7, UserID: 2, AccountID: "wallet:2", Difference: -300}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
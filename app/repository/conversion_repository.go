@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type ConversionRepository struct {
+	DB *sqlx.DB
+}
+
+func NewConversionRepository(db *sqlx.DB) *ConversionRepository {
+	return &ConversionRepository{
+		DB: db,
+	}
+}
+
+// Create records conversion, failing with ErrFXQuoteAlreadyUsed when its quote was already
+// used for another conversion.
+func (r *ConversionRepository) Create(ctx context.Context, conversion *domain.Conversion) (*domain.Conversion, error) {
+	createConversionQuery := `INSERT INTO conversions 
+	(reference_id, quote_id, user_id, from_currency, from_amount, to_currency, to_amount, rate, created_at) VALUES
+	(:reference_id, :quote_id, :user_id, :from_currency, :from_amount, :to_currency, :to_amount, :rate, :created_at)
+	ON CONFLICT (quote_id) DO NOTHING`
+
+	if conversion.CreatedAt.IsZero() {
+		conversion.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := conn(ctx, r.DB).NamedExecContext(ctx, createConversionQuery, conversion)
+	if err != nil {
+		log.Println("[Create] query err:", err)
+		return &domain.Conversion{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("[Create] rows affected err:", err)
+		return &domain.Conversion{}, err
+	}
+
+	if rowsAffected == 0 {
+		return &domain.Conversion{}, errors.ErrFXQuoteAlreadyUsed
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		log.Println("[Create] last insert id err:", err)
+		return &domain.Conversion{}, err
+	}
+	conversion.ID = id
+
+	return conversion, nil
+}
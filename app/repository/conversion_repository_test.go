@@ -0,0 +1,66 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversionRepository_Create(t *testing.T) {
+	// Create a mock DB and expect the named exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.ConversionRepository{DB: sqlxDB}
+
+	conversion := &domain.Conversion{
+		ReferenceID:  "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W8",
+		QuoteID:      "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		UserID:       1,
+		FromCurrency: "USD",
+		FromAmount:   1000,
+		ToCurrency:   "IDR",
+		ToAmount:     16250500,
+		Rate:         "16250.50",
+	}
+
+	mock.ExpectExec("INSERT INTO conversions \\(reference_id, quote_id, user_id, from_currency, from_amount, to_currency, to_amount, rate, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?\\) ON CONFLICT \\(quote_id\\) DO NOTHING").
+		WithArgs(conversion.ReferenceID, conversion.QuoteID, conversion.UserID, "USD", int64(1000), "IDR", int64(16250500), "16250.50", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+
+	// Execute the function
+	result, err := repo.Create(context.Background(), conversion)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), result.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestConversionRepository_Create_QuoteAlreadyUsed(t *testing.T) {
+	// Create a mock DB and expect the named exec to insert nothing
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.ConversionRepository{DB: sqlxDB}
+
+	mock.ExpectExec("INSERT INTO conversions").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute the function
+	_, err = repo.Create(context.Background(), &domain.Conversion{QuoteID: "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7"})
+
+	// Assert the expectations
+	assert.Equal(t, domErr.ErrFXQuoteAlreadyUsed, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
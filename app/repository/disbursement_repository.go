@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type DisbursementRepository struct {
+	DB *sqlx.DB
+}
+
+func NewDisbursementRepository(db *sqlx.DB) *DisbursementRepository {
+	return &DisbursementRepository{
+		DB: db,
+	}
+}
+
+func (r *DisbursementRepository) Create(ctx context.Context, disbursement *domain.Disbursement) (*domain.Disbursement, error) {
+	createDisbursementQuery := `INSERT INTO disbursements 
+	(reference_id, user_id, amount, currency, bank_code, account_no, account_name, status, provider, partner_disbursement_id, created_at, updated_at) VALUES
+	(:reference_id, :user_id, :amount, :currency, :bank_code, :account_no, :account_name, :status, :provider, :partner_disbursement_id, :created_at, :updated_at)`
+
+	if disbursement.CreatedAt.IsZero() {
+		disbursement.CreatedAt = time.Now().UTC()
+	}
+	if disbursement.UpdatedAt.IsZero() {
+		disbursement.UpdatedAt = disbursement.CreatedAt
+	}
+
+	result, err := conn(ctx, r.DB).NamedExecContext(ctx, createDisbursementQuery, disbursement)
+	if err != nil {
+		log.Println("[Create] query err:", err)
+		return &domain.Disbursement{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		log.Println("[Create] last insert id err:", err)
+		return &domain.Disbursement{}, err
+	}
+	disbursement.ID = id
+
+	return disbursement, nil
+}
+
+func (r *DisbursementRepository) GetByID(ctx context.Context, id int64) (*domain.Disbursement, error) {
+	getByIDQuery := `SELECT * FROM disbursements WHERE id = ?`
+
+	var disbursement = &domain.Disbursement{}
+	if err := conn(ctx, r.DB).GetContext(ctx, disbursement, getByIDQuery, id); err != nil {
+		log.Println("[GetByID] query err:", err)
+		return disbursement, err
+	}
+
+	return disbursement, nil
+}
+
+func (r *DisbursementRepository) GetByReferenceID(ctx context.Context, referenceID string) (*domain.Disbursement, error) {
+	getByReferenceIDQuery := `SELECT * FROM disbursements WHERE reference_id = ?`
+
+	var disbursement = &domain.Disbursement{}
+	if err := conn(ctx, r.DB).GetContext(ctx, disbursement, getByReferenceIDQuery, referenceID); err != nil {
+		log.Println("[GetByReferenceID] query err:", err)
+		return disbursement, err
+	}
+
+	return disbursement, nil
+}
+
+func (r *DisbursementRepository) GetByUserID(ctx context.Context, userID int64) ([]*domain.Disbursement, error) {
+	getByUserIDQuery := `SELECT * FROM disbursements WHERE user_id = ? ORDER BY id DESC`
+
+	var disbursements = []*domain.Disbursement{}
+	if err := conn(ctx, r.DB).SelectContext(ctx, &disbursements, getByUserIDQuery, userID); err != nil {
+		log.Println("[GetByUserID] query err:", err)
+		return disbursements, err
+	}
+
+	return disbursements, nil
+}
+
+func (r *DisbursementRepository) GetByPartnerDisbursementID(ctx context.Context, partnerDisbursementID string) (*domain.Disbursement, error) {
+	getByPartnerDisbursementIDQuery := `SELECT * FROM disbursements WHERE partner_disbursement_id = ? ORDER BY id LIMIT 1`
+
+	var disbursement = &domain.Disbursement{}
+	if err := conn(ctx, r.DB).GetContext(ctx, disbursement, getByPartnerDisbursementIDQuery, partnerDisbursementID); err != nil {
+		log.Println("[GetByPartnerDisbursementID] query err:", err)
+		return disbursement, err
+	}
+
+	return disbursement, nil
+}
+
+// GetCompletedBetween returns the disbursements completed from from, inclusive, to to,
+// exclusive, whatever their status is now.
+func (r *DisbursementRepository) GetCompletedBetween(ctx context.Context, from, to time.Time) ([]*domain.Disbursement, error) {
+	getCompletedBetweenQuery := `SELECT * FROM disbursements WHERE strftime('%Y-%m-%d %H:%M:%f', completed_at) >= strftime('%Y-%m-%d %H:%M:%f', ?) AND strftime('%Y-%m-%d %H:%M:%f', completed_at) < strftime('%Y-%m-%d %H:%M:%f', ?) ORDER BY id`
+
+	var disbursements = []*domain.Disbursement{}
+	if err := conn(ctx, r.DB).SelectContext(ctx, &disbursements, getCompletedBetweenQuery, from.UTC(), to.UTC()); err != nil {
+		log.Println("[GetCompletedBetween] query err:", err)
+		return disbursements, err
+	}
+
+	return disbursements, nil
+}
+
+// UpdateStatus persists the status fields of disbursement, provided its stored status
+// is still previousStatus.
+func (r *DisbursementRepository) UpdateStatus(ctx context.Context, disbursement *domain.Disbursement, previousStatus domain.DisbursementStatus) error {
+	updateStatusQuery := `UPDATE disbursements SET 
+	status = ?, partner_disbursement_id = ?, failure_reason = ?, updated_at = ?,
+	submitted_at = ?, completed_at = ?, failed_at = ?, reversed_at = ?
+	WHERE id = ? AND status = ?`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, updateStatusQuery,
+		disbursement.Status, disbursement.PartnerDisbursementID, disbursement.FailureReason, disbursement.UpdatedAt,
+		disbursement.SubmittedAt, disbursement.CompletedAt, disbursement.FailedAt, disbursement.ReversedAt,
+		disbursement.ID, previousStatus)
+	if err != nil {
+		log.Println("[UpdateStatus] query err:", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("[UpdateStatus] rows affected err:", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.ErrDisbursementStatusConflict
+	}
+
+	return nil
+}
+
+// GetDueForPolling returns up to limit submitted disbursements, and pending ones created
+// before pendingBefore, oldest first, that have not been polled yet or whose next poll is
+// due at now.
+func (r *DisbursementRepository) GetDueForPolling(ctx context.Context, now, pendingBefore time.Time, limit int) ([]*domain.Disbursement, error) {
+	getDueForPollingQuery := `SELECT * FROM disbursements 
+	WHERE (status = ? OR (status = ? AND strftime('%Y-%m-%d %H:%M:%f', created_at) < strftime('%Y-%m-%d %H:%M:%f', ?))) 
+	AND (next_poll_at IS NULL OR strftime('%Y-%m-%d %H:%M:%f', next_poll_at) <= strftime('%Y-%m-%d %H:%M:%f', ?)) 
+	ORDER BY id LIMIT ?`
+
+	var disbursements = []*domain.Disbursement{}
+	if err := conn(ctx, r.DB).SelectContext(ctx, &disbursements, getDueForPollingQuery,
+		domain.DisbursementStatusSubmitted, domain.DisbursementStatusPending, pendingBefore.UTC(), now.UTC(), limit); err != nil {
+		log.Println("[GetDueForPolling] query err:", err)
+		return disbursements, err
+	}
+
+	return disbursements, nil
+}
+
+// UpdatePollSchedule persists the poll attempts and next poll of disbursement, provided it
+// is still pending or submitted.
+func (r *DisbursementRepository) UpdatePollSchedule(ctx context.Context, disbursement *domain.Disbursement) error {
+	updatePollScheduleQuery := `UPDATE disbursements SET poll_attempts = ?, next_poll_at = ? WHERE id = ? AND status IN (?, ?)`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, updatePollScheduleQuery,
+		disbursement.PollAttempts, disbursement.NextPollAt, disbursement.ID, domain.DisbursementStatusPending, domain.DisbursementStatusSubmitted)
+	if err != nil {
+		log.Println("[UpdatePollSchedule] query err:", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("[UpdatePollSchedule] rows affected err:", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.ErrDisbursementStatusConflict
+	}
+
+	return nil
+}
@@ -0,0 +1,396 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisbursementRepository_Create(t *testing.T) {
+	// Create a mock DB and expect the named exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	disbursement := &domain.Disbursement{
+		ReferenceID: "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		UserID:      1,
+		Amount:      500,
+		Currency:    "IDR",
+		BankCode:    "bca",
+		AccountNo:   "0810123456878",
+		AccountName: "Brandy Joe",
+		Status:      domain.DisbursementStatusPending,
+		Provider:    "bank1",
+	}
+
+	mock.ExpectExec("INSERT INTO disbursements \\(reference_id, user_id, amount, currency, bank_code, account_no, account_name, status, provider, partner_disbursement_id, created_at, updated_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?\\)").
+		WithArgs(
+			disbursement.ReferenceID,
+			disbursement.UserID,
+			disbursement.Amount,
+			disbursement.Currency,
+			disbursement.BankCode,
+			disbursement.AccountNo,
+			disbursement.AccountName,
+			disbursement.Status,
+			disbursement.Provider,
+			disbursement.PartnerDisbursementID,
+			sqlmock.AnyArg(),
+			sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	// Execute the function
+	result, err := repo.Create(context.Background(), disbursement)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), result.ID)
+	assert.False(t, result.CreatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisbursementRepository_Create_Error(t *testing.T) {
+	// Create a mock DB and expect the named exec to fail
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	disbursement := &domain.Disbursement{
+		ReferenceID: "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		UserID:      1,
+		Amount:      500,
+	}
+
+	mock.ExpectExec("INSERT INTO disbursements").
+		WillReturnError(errors.New("insert failed"))
+
+	// Execute the function
+	result, err := repo.Create(context.Background(), disbursement)
+
+	// Assert the expectations
+	assert.Error(t, err)
+	assert.Equal(t, &domain.Disbursement{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisbursementRepository_GetByReferenceID(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	expectedDisbursement := &domain.Disbursement{
+		ID:          7,
+		ReferenceID: "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		UserID:      1,
+		Amount:      500,
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "reference_id", "user_id", "amount"}).
+		AddRow(expectedDisbursement.ID, expectedDisbursement.ReferenceID, expectedDisbursement.UserID, expectedDisbursement.Amount)
+
+	mock.ExpectQuery("SELECT \\* FROM disbursements WHERE reference_id = \\?").
+		WithArgs(expectedDisbursement.ReferenceID).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetByReferenceID(context.Background(), expectedDisbursement.ReferenceID)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, expectedDisbursement, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisbursementRepository_GetByReferenceID_Error(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	mock.ExpectQuery("SELECT \\* FROM disbursements WHERE reference_id = \\?").
+		WithArgs("unknown").
+		WillReturnError(sql.ErrNoRows)
+
+	// Execute the function
+	result, err := repo.GetByReferenceID(context.Background(), "unknown")
+
+	// Assert the expectations
+	assert.Error(t, err)
+	assert.Equal(t, &domain.Disbursement{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisbursementRepository_GetByID(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	expectedDisbursement := &domain.Disbursement{
+		ID:     7,
+		UserID: 1,
+		Amount: 500,
+		Status: domain.DisbursementStatusSubmitted,
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "amount", "status"}).
+		AddRow(expectedDisbursement.ID, expectedDisbursement.UserID, expectedDisbursement.Amount, expectedDisbursement.Status)
+
+	mock.ExpectQuery("SELECT \\* FROM disbursements WHERE id = \\?").
+		WithArgs(expectedDisbursement.ID).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetByID(context.Background(), expectedDisbursement.ID)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, expectedDisbursement, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisbursementRepository_GetByUserID(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	userID := int64(1)
+	expectedDisbursements := []*domain.Disbursement{
+		{ID: 8, UserID: userID, Amount: 300, Status: domain.DisbursementStatusPending},
+		{ID: 7, UserID: userID, Amount: 500, Status: domain.DisbursementStatusCompleted},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "amount", "status"})
+	for _, disbursement := range expectedDisbursements {
+		rows.AddRow(disbursement.ID, disbursement.UserID, disbursement.Amount, disbursement.Status)
+	}
+
+	mock.ExpectQuery("SELECT \\* FROM disbursements WHERE user_id = \\? ORDER BY id DESC").
+		WithArgs(userID).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetByUserID(context.Background(), userID)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, expectedDisbursements, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisbursementRepository_UpdateStatus(t *testing.T) {
+	// Create a mock DB and expect the exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	disbursement := &domain.Disbursement{
+		ID:                    7,
+		Status:                domain.DisbursementStatusSubmitted,
+		PartnerDisbursementID: "bank-disbursement-1",
+	}
+
+	mock.ExpectExec("UPDATE disbursements SET (.+) WHERE id = \\? AND status = \\?").
+		WithArgs(
+			disbursement.Status, disbursement.PartnerDisbursementID, disbursement.FailureReason, sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			disbursement.ID, domain.DisbursementStatusPending,
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Execute the function
+	err = repo.UpdateStatus(context.Background(), disbursement, domain.DisbursementStatusPending)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisbursementRepository_UpdateStatus_Conflict(t *testing.T) {
+	// Create a mock DB and expect the exec to match no rows
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	disbursement := &domain.Disbursement{
+		ID:     7,
+		Status: domain.DisbursementStatusCompleted,
+	}
+
+	mock.ExpectExec("UPDATE disbursements SET (.+) WHERE id = \\? AND status = \\?").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute the function
+	err = repo.UpdateStatus(context.Background(), disbursement, domain.DisbursementStatusSubmitted)
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrDisbursementStatusConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisbursementRepository_GetDueForPolling(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	pendingBefore := now.Add(-5 * time.Minute)
+	expectedDisbursements := []*domain.Disbursement{
+		{ID: 6, UserID: 1, Amount: 200, Status: domain.DisbursementStatusPending},
+		{ID: 7, UserID: 1, Amount: 500, Status: domain.DisbursementStatusSubmitted, PollAttempts: 2},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "amount", "status", "poll_attempts"})
+	for _, disbursement := range expectedDisbursements {
+		rows.AddRow(disbursement.ID, disbursement.UserID, disbursement.Amount, disbursement.Status, disbursement.PollAttempts)
+	}
+
+	mock.ExpectQuery("SELECT \\* FROM disbursements WHERE \\(status = \\? OR \\(status = \\? AND strftime\\('%Y-%m-%d %H:%M:%f', created_at\\) < strftime\\('%Y-%m-%d %H:%M:%f', \\?\\)\\)\\) AND \\(next_poll_at IS NULL OR strftime\\('%Y-%m-%d %H:%M:%f', next_poll_at\\) <= strftime\\('%Y-%m-%d %H:%M:%f', \\?\\)\\) ORDER BY id LIMIT \\?").
+		WithArgs(domain.DisbursementStatusSubmitted, domain.DisbursementStatusPending, pendingBefore, now, 100).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetDueForPolling(context.Background(), now, pendingBefore, 100)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, expectedDisbursements, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisbursementRepository_UpdatePollSchedule(t *testing.T) {
+	// Create a mock DB and expect the exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	nextPollAt := time.Date(2024, 6, 1, 10, 1, 0, 0, time.UTC)
+	disbursement := &domain.Disbursement{ID: 7, PollAttempts: 2, NextPollAt: &nextPollAt}
+
+	mock.ExpectExec("UPDATE disbursements SET poll_attempts = \\?, next_poll_at = \\? WHERE id = \\? AND status IN \\(\\?, \\?\\)").
+		WithArgs(2, nextPollAt, disbursement.ID, domain.DisbursementStatusPending, domain.DisbursementStatusSubmitted).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Execute the function
+	err = repo.UpdatePollSchedule(context.Background(), disbursement)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisbursementRepository_UpdatePollSchedule_Conflict(t *testing.T) {
+	// Create a mock DB and expect the exec to match no rows, the disbursement was settled meanwhile
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	mock.ExpectExec("UPDATE disbursements SET poll_attempts = \\?, next_poll_at = \\? WHERE id = \\? AND status IN \\(\\?, \\?\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute the function
+	err = repo.UpdatePollSchedule(context.Background(), &domain.Disbursement{ID: 7})
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrDisbursementStatusConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisbursementRepository_GetByPartnerDisbursementID(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	rows := sqlmock.NewRows([]string{"id", "reference_id", "partner_disbursement_id"}).
+		AddRow(7, "WLT-7", "bank-7")
+
+	mock.ExpectQuery("SELECT \\* FROM disbursements WHERE partner_disbursement_id = \\?").
+		WithArgs("bank-7").
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetByPartnerDisbursementID(context.Background(), "bank-7")
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.Disbursement{ID: 7, ReferenceID: "WLT-7", PartnerDisbursementID: "bank-7"}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDisbursementRepository_GetCompletedBetween(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.DisbursementRepository{DB: sqlxDB}
+
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"id", "reference_id", "status"}).
+		AddRow(7, "WLT-7", domain.DisbursementStatusCompleted)
+
+	mock.ExpectQuery("SELECT \\* FROM disbursements WHERE strftime\\('%Y-%m-%d %H:%M:%f', completed_at\\) >= strftime\\('%Y-%m-%d %H:%M:%f', \\?\\) AND strftime\\('%Y-%m-%d %H:%M:%f', completed_at\\) < strftime\\('%Y-%m-%d %H:%M:%f', \\?\\) ORDER BY id").
+		WithArgs(from, from.AddDate(0, 0, 1)).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetCompletedBetween(context.Background(), from, from.AddDate(0, 0, 1))
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.Disbursement{{ID: 7, ReferenceID: "WLT-7", Status: domain.DisbursementStatusCompleted}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
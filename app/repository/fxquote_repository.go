@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+)
+
+type FXQuoteRepository struct {
+	DB *sqlx.DB
+}
+
+func NewFXQuoteRepository(db *sqlx.DB) *FXQuoteRepository {
+	return &FXQuoteRepository{
+		DB: db,
+	}
+}
+
+func (r *FXQuoteRepository) Create(ctx context.Context, quote *domain.FXQuote) (*domain.FXQuote, error) {
+	createQuoteQuery := `INSERT INTO fx_quotes 
+	(reference_id, user_id, from_currency, from_amount, to_currency, to_amount, rate, expires_at, created_at) VALUES
+	(:reference_id, :user_id, :from_currency, :from_amount, :to_currency, :to_amount, :rate, :expires_at, :created_at)`
+
+	if quote.CreatedAt.IsZero() {
+		quote.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := conn(ctx, r.DB).NamedExecContext(ctx, createQuoteQuery, quote)
+	if err != nil {
+		log.Println("[Create] query err:", err)
+		return &domain.FXQuote{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		log.Println("[Create] last insert id err:", err)
+		return &domain.FXQuote{}, err
+	}
+	quote.ID = id
+
+	return quote, nil
+}
+
+func (r *FXQuoteRepository) GetByReferenceID(ctx context.Context, referenceID string) (*domain.FXQuote, error) {
+	getByReferenceIDQuery := `SELECT * FROM fx_quotes WHERE reference_id = ?`
+
+	var quote = &domain.FXQuote{}
+	if err := conn(ctx, r.DB).GetContext(ctx, quote, getByReferenceIDQuery, referenceID); err != nil {
+		log.Println("[GetByReferenceID] query err:", err)
+		return quote, err
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFXQuoteRepository_Create(t *testing.T) {
+	// Create a mock DB and expect the named exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.FXQuoteRepository{DB: sqlxDB}
+
+	quote := &domain.FXQuote{
+		ReferenceID:  "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		UserID:       1,
+		FromCurrency: "USD",
+		FromAmount:   1000,
+		ToCurrency:   "IDR",
+		ToAmount:     16250500,
+		Rate:         "16250.50",
+		ExpiresAt:    time.Now().Add(30 * time.Second),
+	}
+
+	mock.ExpectExec("INSERT INTO fx_quotes \\(reference_id, user_id, from_currency, from_amount, to_currency, to_amount, rate, expires_at, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?\\)").
+		WithArgs(quote.ReferenceID, quote.UserID, "USD", int64(1000), "IDR", int64(16250500), "16250.50", quote.ExpiresAt, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	// Execute the function
+	result, err := repo.Create(context.Background(), quote)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFXQuoteRepository_GetByReferenceID(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.FXQuoteRepository{DB: sqlxDB}
+
+	mock.ExpectQuery("SELECT \\* FROM fx_quotes WHERE reference_id = \\?").
+		WithArgs("WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "reference_id", "user_id"}).AddRow(2, "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7", 1))
+
+	// Execute the function
+	result, err := repo.GetByReferenceID(context.Background(), "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7")
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.UserID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+)
+
+type FXRateRepository struct {
+	DB *sqlx.DB
+}
+
+func NewFXRateRepository(db *sqlx.DB) *FXRateRepository {
+	return &FXRateRepository{
+		DB: db,
+	}
+}
+
+// Upsert stores rate as the current rate of its currency pair.
+func (r *FXRateRepository) Upsert(ctx context.Context, rate *domain.FXRate) error {
+	upsertQuery := `INSERT INTO fx_rates 
+	(base_currency, quote_currency, rate, updated_at) VALUES
+	(:base_currency, :quote_currency, :rate, :updated_at)
+	ON CONFLICT (base_currency, quote_currency) DO UPDATE SET rate = excluded.rate, updated_at = excluded.updated_at`
+
+	if rate.UpdatedAt.IsZero() {
+		rate.UpdatedAt = time.Now().UTC()
+	}
+
+	if _, err := conn(ctx, r.DB).NamedExecContext(ctx, upsertQuery, rate); err != nil {
+		log.Println("[Upsert] query err:", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *FXRateRepository) GetAll(ctx context.Context) ([]*domain.FXRate, error) {
+	getAllQuery := `SELECT * FROM fx_rates ORDER BY base_currency, quote_currency`
+
+	var rates []*domain.FXRate
+	if err := conn(ctx, r.DB).SelectContext(ctx, &rates, getAllQuery); err != nil {
+		log.Println("[GetAll] query err:", err)
+		return nil, err
+	}
+
+	return rates, nil
+}
+
+func (r *FXRateRepository) GetByPair(ctx context.Context, baseCurrency, quoteCurrency string) (*domain.FXRate, error) {
+	getByPairQuery := `SELECT * FROM fx_rates WHERE base_currency = ? AND quote_currency = ?`
+
+	var rate = &domain.FXRate{}
+	if err := conn(ctx, r.DB).GetContext(ctx, rate, getByPairQuery, baseCurrency, quoteCurrency); err != nil {
+		log.Println("[GetByPair] query err:", err)
+		return rate, err
+	}
+
+	return rate, nil
+}
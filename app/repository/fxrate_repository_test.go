@@ -0,0 +1,65 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFXRateRepository_Upsert(t *testing.T) {
+	// Create a mock DB and expect the named exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.FXRateRepository{DB: sqlxDB}
+
+	rate := &domain.FXRate{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "16250.50"}
+
+	mock.ExpectExec("INSERT INTO fx_rates \\(base_currency, quote_currency, rate, updated_at\\) VALUES \\(\\?, \\?, \\?, \\?\\) ON CONFLICT \\(base_currency, quote_currency\\) DO UPDATE SET rate = excluded.rate, updated_at = excluded.updated_at").
+		WithArgs("USD", "IDR", "16250.50", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Execute the function
+	err = repo.Upsert(context.Background(), rate)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.False(t, rate.UpdatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFXRateRepository_GetByPair(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.FXRateRepository{DB: sqlxDB}
+
+	mock.ExpectQuery("SELECT \\* FROM fx_rates WHERE base_currency = \\? AND quote_currency = \\?").
+		WithArgs("USD", "IDR").
+		WillReturnRows(sqlmock.NewRows([]string{"base_currency", "quote_currency", "rate"}).AddRow("USD", "IDR", "16250.50"))
+	mock.ExpectQuery("SELECT \\* FROM fx_rates WHERE base_currency = \\? AND quote_currency = \\?").
+		WithArgs("IDR", "USD").
+		WillReturnError(sql.ErrNoRows)
+
+	// Execute the function
+	result, err := repo.GetByPair(context.Background(), "USD", "IDR")
+	assert.NoError(t, err)
+	assert.Equal(t, "16250.50", result.Rate)
+
+	_, err = repo.GetByPair(context.Background(), "IDR", "USD")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	// Assert the expectations
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type IdempotencyKeyRepository struct {
+	DB *sqlx.DB
+}
+
+func NewIdempotencyKeyRepository(db *sqlx.DB) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{
+		DB: db,
+	}
+}
+
+func (r *IdempotencyKeyRepository) Create(ctx context.Context, idempotencyKey *domain.IdempotencyKey) error {
+	createIdempotencyKeyQuery := `INSERT INTO idempotency_keys 
+	(scope, idempotency_key, request_fingerprint) VALUES
+	(:scope, :idempotency_key, :request_fingerprint)
+	ON CONFLICT (scope, idempotency_key) DO NOTHING`
+
+	result, err := conn(ctx, r.DB).NamedExecContext(ctx, createIdempotencyKeyQuery, idempotencyKey)
+	if err != nil {
+		log.Println("[Create] query err:", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("[Create] rows affected err:", err)
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return errors.ErrDuplicateIdempotencyKey
+	}
+
+	return nil
+}
+
+func (r *IdempotencyKeyRepository) GetByKey(ctx context.Context, scope, key string) (*domain.IdempotencyKey, error) {
+	getByKeyQuery := `SELECT * FROM idempotency_keys WHERE scope = ? AND idempotency_key = ?`
+
+	var idempotencyKey = &domain.IdempotencyKey{}
+	if err := conn(ctx, r.DB).GetContext(ctx, idempotencyKey, getByKeyQuery, scope, key); err != nil {
+		log.Println("[GetByKey] query err:", err)
+		return idempotencyKey, err
+	}
+
+	return idempotencyKey, nil
+}
+
+func (r *IdempotencyKeyRepository) LockStaleByKey(ctx context.Context, scope, key string, lockedBefore time.Time) error {
+	lockStaleByKeyQuery := `UPDATE idempotency_keys SET locked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP 
+	WHERE scope = ? AND idempotency_key = ? AND response_status = 0 
+	AND strftime('%Y-%m-%d %H:%M:%f', locked_at) < strftime('%Y-%m-%d %H:%M:%f', ?)`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, lockStaleByKeyQuery, scope, key, lockedBefore.UTC())
+	if err != nil {
+		log.Println("[LockStaleByKey] query err:", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("[LockStaleByKey] rows affected err:", err)
+		return err
+	}
+
+	// another request took the key over or completed it in the meantime
+	if rowsAffected == 0 {
+		return errors.ErrIdempotencyKeyInProgress
+	}
+
+	return nil
+}
+
+func (r *IdempotencyKeyRepository) UpdateResponseByKey(ctx context.Context, scope, key string, responseStatus int, responseBody string) error {
+	updateResponseByKeyQuery := `UPDATE idempotency_keys SET response_status = ?, response_body = ?, updated_at = CURRENT_TIMESTAMP WHERE scope = ? AND idempotency_key = ?`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, updateResponseByKeyQuery, responseStatus, responseBody, scope, key)
+	if err != nil {
+		log.Println("[UpdateResponseByKey] query err:", err)
+		return err
+	}
+
+	return nil
+}
+
+func (r *IdempotencyKeyRepository) DeleteByKey(ctx context.Context, scope, key string) error {
+	deleteByKeyQuery := `DELETE FROM idempotency_keys WHERE scope = ? AND idempotency_key = ?`
+
+	_, err := conn(ctx, r.DB).ExecContext(ctx, deleteByKeyQuery, scope, key)
+	if err != nil {
+		log.Println("[DeleteByKey] query err:", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,240 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyKeyRepository_Create(t *testing.T) {
+	// Create a mock DB and expect the named exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.IdempotencyKeyRepository{DB: sqlxDB}
+
+	idempotencyKey := &domain.IdempotencyKey{
+		Scope:              "1",
+		Key:                "key-1",
+		RequestFingerprint: "fingerprint",
+	}
+
+	mock.ExpectExec("INSERT INTO idempotency_keys \\(scope, idempotency_key, request_fingerprint\\) VALUES \\(\\?, \\?, \\?\\) ON CONFLICT \\(scope, idempotency_key\\) DO NOTHING").
+		WithArgs(idempotencyKey.Scope, idempotencyKey.Key, idempotencyKey.RequestFingerprint).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Execute the function
+	err = repo.Create(context.Background(), idempotencyKey)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyKeyRepository_Create_Duplicate(t *testing.T) {
+	// Create a mock DB and expect the named exec to hit the conflict clause
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.IdempotencyKeyRepository{DB: sqlxDB}
+
+	idempotencyKey := &domain.IdempotencyKey{
+		Scope:              "1",
+		Key:                "key-1",
+		RequestFingerprint: "fingerprint",
+	}
+
+	mock.ExpectExec("INSERT INTO idempotency_keys").
+		WithArgs(idempotencyKey.Scope, idempotencyKey.Key, idempotencyKey.RequestFingerprint).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute the function
+	err = repo.Create(context.Background(), idempotencyKey)
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrDuplicateIdempotencyKey)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyKeyRepository_Create_Error(t *testing.T) {
+	// Create a mock DB and expect the named exec to fail
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.IdempotencyKeyRepository{DB: sqlxDB}
+
+	idempotencyKey := &domain.IdempotencyKey{
+		Scope:              "1",
+		Key:                "key-1",
+		RequestFingerprint: "fingerprint",
+	}
+
+	mock.ExpectExec("INSERT INTO idempotency_keys").
+		WithArgs(idempotencyKey.Scope, idempotencyKey.Key, idempotencyKey.RequestFingerprint).
+		WillReturnError(errors.New("insert failed"))
+
+	// Execute the function
+	err = repo.Create(context.Background(), idempotencyKey)
+
+	// Assert the expectations
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyKeyRepository_GetByKey(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.IdempotencyKeyRepository{DB: sqlxDB}
+
+	expectedIdempotencyKey := &domain.IdempotencyKey{
+		Scope:              "1",
+		Key:                "key-1",
+		RequestFingerprint: "fingerprint",
+		ResponseStatus:     200,
+		ResponseBody:       `{"status":"ok"}`,
+	}
+
+	rows := sqlmock.NewRows([]string{"scope", "idempotency_key", "request_fingerprint", "response_status", "response_body"}).
+		AddRow(expectedIdempotencyKey.Scope, expectedIdempotencyKey.Key, expectedIdempotencyKey.RequestFingerprint, expectedIdempotencyKey.ResponseStatus, expectedIdempotencyKey.ResponseBody)
+
+	mock.ExpectQuery("SELECT \\* FROM idempotency_keys WHERE scope = \\? AND idempotency_key = \\?").
+		WithArgs(expectedIdempotencyKey.Scope, expectedIdempotencyKey.Key).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetByKey(context.Background(), expectedIdempotencyKey.Scope, expectedIdempotencyKey.Key)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, expectedIdempotencyKey, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyKeyRepository_GetByKey_Error(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.IdempotencyKeyRepository{DB: sqlxDB}
+
+	mock.ExpectQuery("SELECT \\* FROM idempotency_keys WHERE scope = \\? AND idempotency_key = \\?").
+		WithArgs("1", "key-1").
+		WillReturnError(sql.ErrNoRows)
+
+	// Execute the function
+	result, err := repo.GetByKey(context.Background(), "1", "key-1")
+
+	// Assert the expectations
+	assert.Error(t, err)
+	assert.Equal(t, &domain.IdempotencyKey{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyKeyRepository_UpdateResponseByKey(t *testing.T) {
+	// Create a mock DB and expect the exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.IdempotencyKeyRepository{DB: sqlxDB}
+
+	mock.ExpectExec("UPDATE idempotency_keys SET response_status = \\?, response_body = \\?, updated_at = CURRENT_TIMESTAMP WHERE scope = \\? AND idempotency_key = \\?").
+		WithArgs(200, `{"status":"ok"}`, "1", "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Execute the function
+	err = repo.UpdateResponseByKey(context.Background(), "1", "key-1", 200, `{"status":"ok"}`)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyKeyRepository_DeleteByKey(t *testing.T) {
+	// Create a mock DB and expect the exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.IdempotencyKeyRepository{DB: sqlxDB}
+
+	mock.ExpectExec("DELETE FROM idempotency_keys WHERE scope = \\? AND idempotency_key = \\?").
+		WithArgs("1", "key-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Execute the function
+	err = repo.DeleteByKey(context.Background(), "1", "key-1")
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyKeyRepository_LockStaleByKey(t *testing.T) {
+	// Create a mock DB and expect the exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.IdempotencyKeyRepository{DB: sqlxDB}
+
+	lockedBefore := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE idempotency_keys SET locked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE scope = \\? AND idempotency_key = \\? AND response_status = 0").
+		WithArgs("1", "key-1", lockedBefore).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Execute the function
+	err = repo.LockStaleByKey(context.Background(), "1", "key-1", lockedBefore)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyKeyRepository_LockStaleByKey_InProgress(t *testing.T) {
+	// Create a mock DB and expect the exec to match no stale key
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.IdempotencyKeyRepository{DB: sqlxDB}
+
+	lockedBefore := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec("UPDATE idempotency_keys SET locked_at = CURRENT_TIMESTAMP").
+		WithArgs("1", "key-1", lockedBefore).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute the function
+	err = repo.LockStaleByKey(context.Background(), "1", "key-1", lockedBefore)
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrIdempotencyKeyInProgress)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,28 +2,52 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
 )
 
 type JournalEntryRepository struct {
-	DB *sqlx.DB
+	DB                         *sqlx.DB
+	AccountingPeriodRepository domain.AccountingPeriodRepository
 }
 
-func NewJournalEntryRepository(db *sqlx.DB) *JournalEntryRepository {
+func NewJournalEntryRepository(db *sqlx.DB, accountingPeriodRepository domain.AccountingPeriodRepository) *JournalEntryRepository {
 	return &JournalEntryRepository{
-		DB: db,
+		DB:                         db,
+		AccountingPeriodRepository: accountingPeriodRepository,
 	}
 }
 
+// Create links journalEntry to the last entry of the journal and inserts it. It has to run
+// within the transaction carried by ctx, so no two entries can link to the same one. An entry
+// valued before the end of a closed accounting period is rejected.
 func (r *JournalEntryRepository) Create(ctx context.Context, journalEntry *domain.JournalEntry) (*domain.JournalEntry, error) {
 	createJournalEntryQuery := `INSERT INTO journal_entries 
-	(account_id, transaction_name, debit_amount, credit_amount, folio) VALUES
-	(:account_id, :transaction_name, :debit_amount, :credit_amount, :folio)`
+	(account_id, transaction_name, debit_amount, credit_amount, currency, folio, transaction_group_id, posted_at, value_date, metadata, previous_hash, hash) VALUES
+	(:account_id, :transaction_name, :debit_amount, :credit_amount, :currency, :folio, :transaction_group_id, :posted_at, :value_date, :metadata, :previous_hash, :hash)`
 
-	result, err := r.DB.NamedExecContext(ctx, createJournalEntryQuery, journalEntry)
+	closed, err := r.AccountingPeriodRepository.IsClosed(ctx, journalEntry.ValueDate)
+	if err != nil {
+		return &domain.JournalEntry{}, err
+	}
+	if closed {
+		return &domain.JournalEntry{}, errors.ErrPostingPeriodClosed
+	}
+
+	previousHash, err := r.getLastHash(ctx)
+	if err != nil {
+		return &domain.JournalEntry{}, err
+	}
+	journalEntry.PreviousHash = previousHash
+	journalEntry.Hash = journalEntry.ComputeHash()
+
+	result, err := conn(ctx, r.DB).NamedExecContext(ctx, createJournalEntryQuery, journalEntry)
 	if err != nil {
 		return &domain.JournalEntry{}, err
 	}
@@ -31,3 +55,205 @@ func (r *JournalEntryRepository) Create(ctx context.Context, journalEntry *domai
 
 	return journalEntry, nil
 }
+
+// getLastHash returns the hash of the last journal entry, or GenesisHash for the first one.
+func (r *JournalEntryRepository) getLastHash(ctx context.Context) (string, error) {
+	getLastHashQuery := `SELECT hash FROM journal_entries ORDER BY id DESC LIMIT 1`
+
+	var hash string
+	if err := conn(ctx, r.DB).GetContext(ctx, &hash, getLastHashQuery); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.GenesisHash, nil
+		}
+
+		log.Println("[Create] get last hash err:", err)
+		return "", err
+	}
+
+	return hash, nil
+}
+
+// CreateTransaction posts all entries of journal. It has to run within the transaction
+// carried by ctx, which the caller rolls back when an entry fails. A journal that does not
+// balance is rejected before anything is written.
+func (r *JournalEntryRepository) CreateTransaction(ctx context.Context, journal *domain.JournalTransaction) error {
+	journal.Stamp(time.Now())
+	if err := journal.Validate(); err != nil {
+		log.Println("[CreateTransaction] validate err:", err)
+		return err
+	}
+
+	if err := r.checkAccounts(ctx, journal); err != nil {
+		return err
+	}
+
+	for _, entry := range journal.Entries {
+		if _, err := r.Create(ctx, entry); err != nil {
+			log.Println("[CreateTransaction] create entry err:", err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *JournalEntryRepository) GetAccountBalance(ctx context.Context, accountID string) (*domain.LedgerAccountBalance, error) {
+	getAccountBalanceQuery := `SELECT la.id AS account_id, la.name, la.type, la.normal_balance, la.currency,
+	COALESCE(SUM(je.debit_amount), 0) AS total_debit, COALESCE(SUM(je.credit_amount), 0) AS total_credit
+	FROM ledger_accounts la LEFT JOIN journal_entries je ON je.account_id = la.id
+	WHERE la.id = ?
+	GROUP BY la.id, la.name, la.type, la.normal_balance, la.currency`
+
+	var accountBalance = &domain.LedgerAccountBalance{}
+	if err := conn(ctx, r.DB).GetContext(ctx, accountBalance, getAccountBalanceQuery, accountID); err != nil {
+		log.Println("[GetAccountBalance] query err:", err)
+		return accountBalance, err
+	}
+	accountBalance.CalculateBalance()
+
+	return accountBalance, nil
+}
+
+func (r *JournalEntryRepository) GetAccountBalances(ctx context.Context) ([]*domain.LedgerAccountBalance, error) {
+	getAccountBalancesQuery := `SELECT la.id AS account_id, la.name, la.type, la.normal_balance, la.currency,
+	COALESCE(SUM(je.debit_amount), 0) AS total_debit, COALESCE(SUM(je.credit_amount), 0) AS total_credit
+	FROM ledger_accounts la LEFT JOIN journal_entries je ON je.account_id = la.id
+	GROUP BY la.id, la.name, la.type, la.normal_balance, la.currency
+	ORDER BY la.id`
+
+	var accountBalances []*domain.LedgerAccountBalance
+	if err := conn(ctx, r.DB).SelectContext(ctx, &accountBalances, getAccountBalancesQuery); err != nil {
+		log.Println("[GetAccountBalances] query err:", err)
+		return nil, err
+	}
+
+	for _, accountBalance := range accountBalances {
+		accountBalance.CalculateBalance()
+	}
+
+	return accountBalances, nil
+}
+
+// GetAccountBalancesForPeriod returns the balance of every account from the entries with a
+// value date in [from, to). A zero from starts at the first entry.
+func (r *JournalEntryRepository) GetAccountBalancesForPeriod(ctx context.Context, from, to time.Time) ([]*domain.LedgerAccountBalance, error) {
+	getAccountBalancesForPeriodQuery := `SELECT la.id AS account_id, la.name, la.type, la.normal_balance, la.currency,
+	COALESCE(SUM(je.debit_amount), 0) AS total_debit, COALESCE(SUM(je.credit_amount), 0) AS total_credit
+	FROM ledger_accounts la LEFT JOIN journal_entries je ON je.account_id = la.id AND strftime('%Y-%m-%d %H:%M:%f', je.value_date) >= strftime('%Y-%m-%d %H:%M:%f', ?) AND strftime('%Y-%m-%d %H:%M:%f', je.value_date) < strftime('%Y-%m-%d %H:%M:%f', ?)
+	GROUP BY la.id, la.name, la.type, la.normal_balance, la.currency
+	ORDER BY la.id`
+
+	var accountBalances []*domain.LedgerAccountBalance
+	if err := conn(ctx, r.DB).SelectContext(ctx, &accountBalances, getAccountBalancesForPeriodQuery, from.UTC(), to.UTC()); err != nil {
+		log.Println("[GetAccountBalancesForPeriod] query err:", err)
+		return nil, err
+	}
+
+	for _, accountBalance := range accountBalances {
+		accountBalance.CalculateBalance()
+	}
+
+	return accountBalances, nil
+}
+
+// GetEntries returns the entries matching filter in posting order, starting after the entry
+// filter.Cursor and returning at most filter.Limit entries when it is set.
+func (r *JournalEntryRepository) GetEntries(ctx context.Context, filter *domain.JournalEntryFilter) ([]*domain.JournalEntry, error) {
+	conditions := []string{"id > ?"}
+	args := []interface{}{filter.Cursor}
+	if filter.AccountID != "" {
+		conditions = append(conditions, "account_id = ?")
+		args = append(args, filter.AccountID)
+	}
+	if filter.Currency != "" {
+		conditions = append(conditions, "currency = ?")
+		args = append(args, filter.Currency)
+	}
+	if filter.Folio != "" {
+		conditions = append(conditions, "folio = ?")
+		args = append(args, filter.Folio)
+	}
+	if filter.TransactionGroupID != "" {
+		conditions = append(conditions, "transaction_group_id = ?")
+		args = append(args, filter.TransactionGroupID)
+	}
+	if filter.TransactionName != "" {
+		conditions = append(conditions, "transaction_name = ?")
+		args = append(args, filter.TransactionName)
+	}
+	// timestamps are compared through strftime, so values written by the driver, by
+	// CURRENT_TIMESTAMP and the bound ones compare as UTC times to the millisecond, not as text
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "strftime('%Y-%m-%d %H:%M:%f', posted_at) >= strftime('%Y-%m-%d %H:%M:%f', ?)")
+		args = append(args, filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "strftime('%Y-%m-%d %H:%M:%f', posted_at) < strftime('%Y-%m-%d %H:%M:%f', ?)")
+		args = append(args, filter.To.UTC())
+	}
+
+	getEntriesQuery := `SELECT * FROM journal_entries WHERE ` + strings.Join(conditions, " AND ") + ` ORDER BY id`
+	if filter.Limit > 0 {
+		getEntriesQuery += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	var journalEntries = []*domain.JournalEntry{}
+	if err := conn(ctx, r.DB).SelectContext(ctx, &journalEntries, getEntriesQuery, args...); err != nil {
+		log.Println("[GetEntries] query err:", err)
+		return journalEntries, err
+	}
+
+	return journalEntries, nil
+}
+
+// GetAccountBalanceBefore returns the balance of accountID from the entries posted before the
+// entry entryID.
+func (r *JournalEntryRepository) GetAccountBalanceBefore(ctx context.Context, accountID string, entryID int64) (*domain.LedgerAccountBalance, error) {
+	getAccountBalanceBeforeQuery := `SELECT la.id AS account_id, la.name, la.type, la.normal_balance, la.currency,
+	COALESCE(SUM(je.debit_amount), 0) AS total_debit, COALESCE(SUM(je.credit_amount), 0) AS total_credit
+	FROM ledger_accounts la LEFT JOIN journal_entries je ON je.account_id = la.id AND je.id < ?
+	WHERE la.id = ?
+	GROUP BY la.id, la.name, la.type, la.normal_balance, la.currency`
+
+	var accountBalance = &domain.LedgerAccountBalance{}
+	if err := conn(ctx, r.DB).GetContext(ctx, accountBalance, getAccountBalanceBeforeQuery, entryID, accountID); err != nil {
+		log.Println("[GetAccountBalanceBefore] query err:", err)
+		return accountBalance, err
+	}
+	accountBalance.CalculateBalance()
+
+	return accountBalance, nil
+}
+
+// checkAccounts returns ErrLedgerAccountNotFound unless every account of journal is
+// registered, and ErrCurrencyMismatch when an entry is not in the currency of its account.
+func (r *JournalEntryRepository) checkAccounts(ctx context.Context, journal *domain.JournalTransaction) error {
+	getAccountsQuery, args, err := sqlx.In(`SELECT id, currency FROM ledger_accounts WHERE id IN (?)`, journal.AccountIDs())
+	if err != nil {
+		return err
+	}
+
+	var accounts []*domain.LedgerAccount
+	if err := conn(ctx, r.DB).SelectContext(ctx, &accounts, r.DB.Rebind(getAccountsQuery), args...); err != nil {
+		log.Println("[CreateTransaction] get accounts err:", err)
+		return err
+	}
+
+	currencies := make(map[string]string, len(accounts))
+	for _, account := range accounts {
+		currencies[account.ID] = account.Currency
+	}
+
+	for _, entry := range journal.Entries {
+		currency, ok := currencies[entry.AccountID]
+		if !ok {
+			return errors.ErrLedgerAccountNotFound
+		}
+		if currency != entry.Currency {
+			return errors.ErrCurrencyMismatch
+		}
+	}
+
+	return nil
+}
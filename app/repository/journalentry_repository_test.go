@@ -4,38 +4,58 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/domain/mocks"
 	"github.com/krisdioles/ppr-wallet/app/repository"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestJournalEntryRepository_Create(t *testing.T) {
-	// Create a mock DB and expect the named exec
+	// Create a mock DB and expect the first entry to link to the genesis hash
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
-	repo := repository.JournalEntryRepository{DB: sqlxDB}
+	mockAccountingPeriodRepo := new(mocks.AccountingPeriodRepository)
+	repo := repository.JournalEntryRepository{DB: sqlxDB, AccountingPeriodRepository: mockAccountingPeriodRepo}
 
+	postedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
 	journalEntry := &domain.JournalEntry{
-		AccountID:       "1",
-		TransactionName: "Test Transaction",
-		DebitAmount:     100.0,
-		CreditAmount:    100.0,
-		Folio:           "Test Folio",
+		AccountID:          "1",
+		TransactionName:    "Test Transaction",
+		DebitAmount:        100.0,
+		CreditAmount:       100.0,
+		Currency:           "IDR",
+		Folio:              "Test Folio",
+		TransactionGroupID: "Test Group",
+		PostedAt:           postedAt,
+		ValueDate:          postedAt.Truncate(24 * time.Hour),
+		Metadata:           domain.JournalMetadata{"user_id": "1"},
 	}
 
-	mock.ExpectExec("INSERT INTO journal_entries \\(account_id, transaction_name, debit_amount, credit_amount, folio\\) VALUES \\(\\?, \\?, \\?, \\?, \\?\\)").
+	mockAccountingPeriodRepo.On("IsClosed", context.Background(), journalEntry.ValueDate).Return(false, nil)
+	mock.ExpectQuery("SELECT hash FROM journal_entries ORDER BY id DESC LIMIT 1").
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	mock.ExpectExec("INSERT INTO journal_entries \\(account_id, transaction_name, debit_amount, credit_amount, currency, folio, transaction_group_id, posted_at, value_date, metadata, previous_hash, hash\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?\\)").
 		WithArgs(
 			journalEntry.AccountID,
 			journalEntry.TransactionName,
 			journalEntry.DebitAmount,
 			journalEntry.CreditAmount,
+			journalEntry.Currency,
 			journalEntry.Folio,
+			journalEntry.TransactionGroupID,
+			journalEntry.PostedAt,
+			journalEntry.ValueDate,
+			`{"user_id":"1"}`,
+			domain.GenesisHash,
+			sqlmock.AnyArg(),
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -45,7 +65,10 @@ func TestJournalEntryRepository_Create(t *testing.T) {
 	// Assert the expectations
 	assert.NoError(t, err)
 	assert.Equal(t, journalEntry, result)
+	assert.Equal(t, domain.GenesisHash, result.PreviousHash)
+	assert.Equal(t, result.ComputeHash(), result.Hash)
 	assert.NoError(t, mock.ExpectationsWereMet())
+	mockAccountingPeriodRepo.AssertExpectations(t)
 }
 
 func TestJournalEntryRepository_Create_Error(t *testing.T) {
@@ -55,23 +78,40 @@ func TestJournalEntryRepository_Create_Error(t *testing.T) {
 	defer db.Close()
 
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
-	repo := repository.JournalEntryRepository{DB: sqlxDB}
+	mockAccountingPeriodRepo := new(mocks.AccountingPeriodRepository)
+	repo := repository.JournalEntryRepository{DB: sqlxDB, AccountingPeriodRepository: mockAccountingPeriodRepo}
 
+	postedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
 	journalEntry := &domain.JournalEntry{
-		AccountID:       "1",
-		TransactionName: "Test Transaction",
-		DebitAmount:     100.0,
-		CreditAmount:    100.0,
-		Folio:           "Test Folio",
+		AccountID:          "1",
+		TransactionName:    "Test Transaction",
+		DebitAmount:        100.0,
+		CreditAmount:       100.0,
+		Currency:           "IDR",
+		Folio:              "Test Folio",
+		TransactionGroupID: "Test Group",
+		PostedAt:           postedAt,
+		ValueDate:          postedAt.Truncate(24 * time.Hour),
+		Metadata:           domain.JournalMetadata{"user_id": "1"},
 	}
 
-	mock.ExpectExec("INSERT INTO journal_entries \\(account_id, transaction_name, debit_amount, credit_amount, folio\\) VALUES \\(\\?, \\?, \\?, \\?, \\?\\)").
+	mockAccountingPeriodRepo.On("IsClosed", context.Background(), journalEntry.ValueDate).Return(false, nil)
+	mock.ExpectQuery("SELECT hash FROM journal_entries ORDER BY id DESC LIMIT 1").
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("previous-hash"))
+	mock.ExpectExec("INSERT INTO journal_entries \\(account_id, transaction_name, debit_amount, credit_amount, currency, folio, transaction_group_id, posted_at, value_date, metadata, previous_hash, hash\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?\\)").
 		WithArgs(
 			journalEntry.AccountID,
 			journalEntry.TransactionName,
 			journalEntry.DebitAmount,
 			journalEntry.CreditAmount,
+			journalEntry.Currency,
 			journalEntry.Folio,
+			journalEntry.TransactionGroupID,
+			journalEntry.PostedAt,
+			journalEntry.ValueDate,
+			`{"user_id":"1"}`,
+			"previous-hash",
+			sqlmock.AnyArg(),
 		).
 		WillReturnError(errors.New("insert failed"))
 
@@ -83,4 +123,378 @@ func TestJournalEntryRepository_Create_Error(t *testing.T) {
 	assert.NotNil(t, result)
 	assert.Equal(t, &domain.JournalEntry{}, result)
 	assert.NoError(t, mock.ExpectationsWereMet())
+	mockAccountingPeriodRepo.AssertExpectations(t)
+}
+
+func TestJournalEntryRepository_Create_PeriodClosed(t *testing.T) {
+	// Create a mock DB and expect the entry to be rejected before it is linked
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	mockAccountingPeriodRepo := new(mocks.AccountingPeriodRepository)
+	repo := repository.JournalEntryRepository{DB: sqlxDB, AccountingPeriodRepository: mockAccountingPeriodRepo}
+
+	journalEntry := &domain.JournalEntry{
+		AccountID:          "1",
+		DebitAmount:        100,
+		Currency:           "IDR",
+		Folio:              "Test Folio",
+		PostedAt:           time.Date(2026, 4, 2, 3, 4, 5, 0, time.UTC),
+		ValueDate:          time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		TransactionGroupID: "Test Group",
+	}
+
+	mockAccountingPeriodRepo.On("IsClosed", context.Background(), journalEntry.ValueDate).Return(true, nil)
+
+	// Execute the function
+	_, err = repo.Create(context.Background(), journalEntry)
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrPostingPeriodClosed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	mockAccountingPeriodRepo.AssertExpectations(t)
+}
+
+func TestJournalEntryRepository_CreateTransaction(t *testing.T) {
+	// Create a mock DB and expect every entry to be linked and inserted
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	mockAccountingPeriodRepo := new(mocks.AccountingPeriodRepository)
+	repo := repository.JournalEntryRepository{DB: sqlxDB, AccountingPeriodRepository: mockAccountingPeriodRepo}
+
+	journal := domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+		Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 500).
+		Credit(domain.BankClearingAccountID, 500)
+	journal.ValueDate = time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT id, currency FROM ledger_accounts WHERE id IN \\(\\?, \\?\\)").
+		WithArgs("wallet:1", "bank-clearing").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "currency"}).AddRow("wallet:1", "IDR").AddRow("bank-clearing", "IDR"))
+	mockAccountingPeriodRepo.On("IsClosed", context.Background(), journal.ValueDate).Return(false, nil).Times(2)
+	mock.ExpectQuery("SELECT hash FROM journal_entries").
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("hash-0"))
+	mock.ExpectExec("INSERT INTO journal_entries").
+		WithArgs("wallet:1", "Balance disbursement", int64(500), int64(0), "IDR", "WLT-1", "WLT-1", sqlmock.AnyArg(), sqlmock.AnyArg(), "{}", "hash-0", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT hash FROM journal_entries").
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("hash-1"))
+	mock.ExpectExec("INSERT INTO journal_entries").
+		WithArgs("bank-clearing", "Balance disbursement", int64(0), int64(500), "IDR", "WLT-1", "WLT-1", sqlmock.AnyArg(), sqlmock.AnyArg(), "{}", "hash-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	// Execute the function
+	err = repo.CreateTransaction(context.Background(), journal)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	mockAccountingPeriodRepo.AssertExpectations(t)
+}
+
+func TestJournalEntryRepository_CreateTransaction_Unbalanced(t *testing.T) {
+	// Create a mock DB and expect nothing to be written
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.JournalEntryRepository{DB: sqlxDB}
+
+	journal := domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+		Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 500).
+		Credit(domain.BankClearingAccountID, 499)
+
+	// Execute the function
+	err = repo.CreateTransaction(context.Background(), journal)
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrUnbalancedJournal)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJournalEntryRepository_CreateTransaction_Error(t *testing.T) {
+	// Create a mock DB and expect the insert to fail
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	mockAccountingPeriodRepo := new(mocks.AccountingPeriodRepository)
+	repo := repository.JournalEntryRepository{DB: sqlxDB, AccountingPeriodRepository: mockAccountingPeriodRepo}
+
+	journal := domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+		Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 500).
+		Credit(domain.BankClearingAccountID, 500)
+	journal.ValueDate = time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("SELECT id, currency FROM ledger_accounts").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "currency"}).AddRow("wallet:1", "IDR").AddRow("bank-clearing", "IDR"))
+	mockAccountingPeriodRepo.On("IsClosed", context.Background(), journal.ValueDate).Return(false, nil).Times(2)
+	mock.ExpectQuery("SELECT hash FROM journal_entries").
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	mock.ExpectExec("INSERT INTO journal_entries").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT hash FROM journal_entries").
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}).AddRow("hash-1"))
+	mock.ExpectExec("INSERT INTO journal_entries").
+		WillReturnError(errors.New("insert failed"))
+
+	// Execute the function
+	err = repo.CreateTransaction(context.Background(), journal)
+
+	// Assert the expectations
+	assert.EqualError(t, err, "insert failed")
+	assert.NoError(t, mock.ExpectationsWereMet())
+	mockAccountingPeriodRepo.AssertExpectations(t)
+}
+
+func TestJournalEntryRepository_CreateTransaction_UnknownAccount(t *testing.T) {
+	// Create a mock DB and expect no entry to be inserted
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.JournalEntryRepository{DB: sqlxDB}
+
+	journal := domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+		Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 500).
+		Credit("1234567890", 500)
+
+	mock.ExpectQuery("SELECT id, currency FROM ledger_accounts WHERE id IN \\(\\?, \\?\\)").
+		WithArgs("wallet:1", "1234567890").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "currency"}).AddRow("wallet:1", "IDR"))
+
+	// Execute the function
+	err = repo.CreateTransaction(context.Background(), journal)
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrLedgerAccountNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJournalEntryRepository_CreateTransaction_CurrencyMismatch(t *testing.T) {
+	// Create a mock DB and expect no entry to be inserted
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.JournalEntryRepository{DB: sqlxDB}
+
+	journal := domain.NewJournalTransaction("WLT-1", "Balance disbursement").
+		InCurrency("USD").
+		Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 500).
+		Credit(domain.CurrencyAccountID(domain.BankClearingAccountID, "USD"), 500)
+
+	mock.ExpectQuery("SELECT id, currency FROM ledger_accounts WHERE id IN \\(\\?, \\?\\)").
+		WithArgs("wallet:1", "bank-clearing:USD").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "currency"}).AddRow("wallet:1", "IDR").AddRow("bank-clearing:USD", "USD"))
+
+	// Execute the function
+	err = repo.CreateTransaction(context.Background(), journal)
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrCurrencyMismatch)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJournalEntryRepository_GetAccountBalance(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.JournalEntryRepository{DB: sqlxDB}
+
+	rows := sqlmock.NewRows([]string{"account_id", "normal_balance", "total_debit", "total_credit"}).
+		AddRow("wallet:1", "CREDIT", 300, 1000)
+
+	mock.ExpectQuery("SELECT la.id AS account_id, (.+) FROM ledger_accounts la LEFT JOIN journal_entries je (.+) WHERE la.id = \\?").
+		WithArgs("wallet:1").
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetAccountBalance(context.Background(), "wallet:1")
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.LedgerAccountBalance{
+		AccountID:     "wallet:1",
+		NormalBalance: domain.NormalBalanceCredit,
+		TotalDebit:    300,
+		TotalCredit:   1000,
+		Balance:       700,
+	}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJournalEntryRepository_GetAccountBalances(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.JournalEntryRepository{DB: sqlxDB}
+
+	rows := sqlmock.NewRows([]string{"account_id", "normal_balance", "total_debit", "total_credit"}).
+		AddRow(domain.BankClearingAccountID, "DEBIT", 0, 300).
+		AddRow("wallet:1", "CREDIT", 300, 1000)
+
+	mock.ExpectQuery("SELECT la.id AS account_id, la.name, la.type, la.normal_balance, la.currency, (.+) GROUP BY la.id, la.name, la.type, la.normal_balance, la.currency ORDER BY la.id").
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetAccountBalances(context.Background())
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, int64(-300), result[0].Balance)
+	assert.Equal(t, int64(700), result[1].Balance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJournalEntryRepository_GetAccountBalances_Error(t *testing.T) {
+	// Create a mock DB and expect the query to fail
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.JournalEntryRepository{DB: sqlxDB}
+
+	mock.ExpectQuery("SELECT la.id AS account_id").
+		WillReturnError(errors.New("query error"))
+
+	// Execute the function
+	result, err := repo.GetAccountBalances(context.Background())
+
+	// Assert the expectations
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJournalEntryRepository_GetEntries(t *testing.T) {
+	// Create a mock DB and expect the query with every filter applied
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.JournalEntryRepository{DB: sqlxDB}
+
+	filter := &domain.JournalEntryFilter{
+		AccountID:          "wallet:1",
+		Folio:              "WLT-1",
+		TransactionGroupID: "WLT-1",
+		TransactionName:    "Balance disbursement",
+		From:               time.Date(2026, 1, 1, 7, 0, 0, 0, time.FixedZone("WIB", 7*60*60)),
+		To:                 time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Cursor:             10,
+		Limit:              21,
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "account_id", "debit_amount", "folio"}).
+		AddRow(11, "wallet:1", 500, "WLT-1")
+
+	mock.ExpectQuery("SELECT \\* FROM journal_entries WHERE id > \\? AND account_id = \\? AND folio = \\? AND transaction_group_id = \\? AND transaction_name = \\? AND strftime\\('%Y-%m-%d %H:%M:%f', posted_at\\) >= strftime\\('%Y-%m-%d %H:%M:%f', \\?\\) AND strftime\\('%Y-%m-%d %H:%M:%f', posted_at\\) < strftime\\('%Y-%m-%d %H:%M:%f', \\?\\) ORDER BY id LIMIT \\?").
+		WithArgs(int64(10), "wallet:1", "WLT-1", "WLT-1", "Balance disbursement", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), 21).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetEntries(context.Background(), filter)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.JournalEntry{{ID: 11, AccountID: "wallet:1", DebitAmount: 500, Folio: "WLT-1"}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJournalEntryRepository_GetEntries_NoFilter(t *testing.T) {
+	// Create a mock DB and expect the query without conditions besides the cursor
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.JournalEntryRepository{DB: sqlxDB}
+
+	mock.ExpectQuery("SELECT \\* FROM journal_entries WHERE id > \\? ORDER BY id$").
+		WithArgs(int64(0)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Execute the function
+	result, err := repo.GetEntries(context.Background(), &domain.JournalEntryFilter{})
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJournalEntryRepository_GetAccountBalanceBefore(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.JournalEntryRepository{DB: sqlxDB}
+
+	rows := sqlmock.NewRows([]string{"account_id", "normal_balance", "total_debit", "total_credit"}).
+		AddRow("wallet:1", "CREDIT", 200, 1000)
+
+	mock.ExpectQuery("LEFT JOIN journal_entries je ON je.account_id = la.id AND je.id < \\? WHERE la.id = \\?").
+		WithArgs(int64(5), "wallet:1").
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetAccountBalanceBefore(context.Background(), "wallet:1", 5)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, int64(800), result.Balance)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestJournalEntryRepository_GetAccountBalancesForPeriod(t *testing.T) {
+	// Create a mock DB and expect the query bounded by value date
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.JournalEntryRepository{DB: sqlxDB}
+
+	rows := sqlmock.NewRows([]string{"account_id", "name", "type", "normal_balance", "currency", "total_debit", "total_credit"}).
+		AddRow(domain.FeeIncomeAccountID, "Fee income", "REVENUE", "CREDIT", "IDR", 0, 100)
+
+	mock.ExpectQuery("ON je.account_id = la.id AND strftime\\('%Y-%m-%d %H:%M:%f', je.value_date\\) >= strftime\\('%Y-%m-%d %H:%M:%f', \\?\\) AND strftime\\('%Y-%m-%d %H:%M:%f', je.value_date\\) < strftime\\('%Y-%m-%d %H:%M:%f', \\?\\)").
+		WithArgs(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetAccountBalancesForPeriod(context.Background(), time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.LedgerAccountBalance{{
+		AccountID:     domain.FeeIncomeAccountID,
+		Name:          "Fee income",
+		Type:          domain.LedgerAccountTypeRevenue,
+		NormalBalance: domain.NormalBalanceCredit,
+		Currency:      "IDR",
+		TotalCredit:   100,
+		Balance:       100,
+	}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
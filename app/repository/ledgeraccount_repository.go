@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+)
+
+type LedgerAccountRepository struct {
+	DB *sqlx.DB
+}
+
+func NewLedgerAccountRepository(db *sqlx.DB) *LedgerAccountRepository {
+	return &LedgerAccountRepository{
+		DB: db,
+	}
+}
+
+// Register adds account to the chart of accounts, keeping the existing one if it is already
+// registered.
+func (r *LedgerAccountRepository) Register(ctx context.Context, account *domain.LedgerAccount) error {
+	registerQuery := `INSERT INTO ledger_accounts 
+	(id, name, type, normal_balance, currency, owner_user_id) VALUES
+	(:id, :name, :type, :normal_balance, :currency, :owner_user_id)
+	ON CONFLICT (id) DO NOTHING`
+
+	if _, err := conn(ctx, r.DB).NamedExecContext(ctx, registerQuery, account); err != nil {
+		log.Println("[Register] query err:", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+)
+
+type ReconciliationRepository struct {
+	DB *sqlx.DB
+}
+
+func NewReconciliationRepository(db *sqlx.DB) *ReconciliationRepository {
+	return &ReconciliationRepository{
+		DB: db,
+	}
+}
+
+// Create stores reconciliation together with its lines.
+func (r *ReconciliationRepository) Create(ctx context.Context, reconciliation *domain.Reconciliation) (*domain.Reconciliation, error) {
+	createReconciliationQuery := `INSERT INTO reconciliations
+	(file_name, provider, settlement_date, matched, missing_on_our_side, missing_at_bank, amount_mismatch, created_at) VALUES
+	(:file_name, :provider, :settlement_date, :matched, :missing_on_our_side, :missing_at_bank, :amount_mismatch, :created_at)`
+	createReconciliationLineQuery := `INSERT INTO reconciliation_lines
+	(reconciliation_id, line_no, reference, currency, bank_amount, our_amount, status, folio, note) VALUES
+	(:reconciliation_id, :line_no, :reference, :currency, :bank_amount, :our_amount, :status, :folio, :note)`
+
+	if reconciliation.CreatedAt.IsZero() {
+		reconciliation.CreatedAt = time.Now().UTC()
+	}
+
+	err := NewTransactionManager(r.DB).WithinTransaction(ctx, func(txCtx context.Context) error {
+		result, err := conn(txCtx, r.DB).NamedExecContext(txCtx, createReconciliationQuery, reconciliation)
+		if err != nil {
+			log.Println("[Create] query err:", err)
+			return err
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			log.Println("[Create] last insert id err:", err)
+			return err
+		}
+		reconciliation.ID = id
+
+		for _, line := range reconciliation.Lines {
+			line.ReconciliationID = id
+
+			result, err := conn(txCtx, r.DB).NamedExecContext(txCtx, createReconciliationLineQuery, line)
+			if err != nil {
+				log.Println("[Create] create line err:", err)
+				return err
+			}
+
+			lineID, err := result.LastInsertId()
+			if err != nil {
+				log.Println("[Create] line last insert id err:", err)
+				return err
+			}
+			line.ID = lineID
+		}
+
+		return nil
+	})
+	if err != nil {
+		return &domain.Reconciliation{}, err
+	}
+
+	return reconciliation, nil
+}
+
+func (r *ReconciliationRepository) GetByID(ctx context.Context, id int64) (*domain.Reconciliation, error) {
+	getByIDQuery := `SELECT * FROM reconciliations WHERE id = ?`
+
+	var reconciliation = &domain.Reconciliation{}
+	if err := conn(ctx, r.DB).GetContext(ctx, reconciliation, getByIDQuery, id); err != nil {
+		log.Println("[GetByID] query err:", err)
+		return reconciliation, err
+	}
+
+	return reconciliation, nil
+}
+
+func (r *ReconciliationRepository) GetAll(ctx context.Context) ([]*domain.Reconciliation, error) {
+	getAllQuery := `SELECT * FROM reconciliations ORDER BY id DESC`
+
+	var reconciliations = []*domain.Reconciliation{}
+	if err := conn(ctx, r.DB).SelectContext(ctx, &reconciliations, getAllQuery); err != nil {
+		log.Println("[GetAll] query err:", err)
+		return reconciliations, err
+	}
+
+	return reconciliations, nil
+}
+
+// GetLines returns the lines of a reconciliation in the order they were stored: the lines of
+// the file first, then the ones missing at the bank.
+func (r *ReconciliationRepository) GetLines(ctx context.Context, reconciliationID int64) ([]*domain.ReconciliationLine, error) {
+	getLinesQuery := `SELECT * FROM reconciliation_lines WHERE reconciliation_id = ? ORDER BY id`
+
+	var lines = []*domain.ReconciliationLine{}
+	if err := conn(ctx, r.DB).SelectContext(ctx, &lines, getLinesQuery, reconciliationID); err != nil {
+		log.Println("[GetLines] query err:", err)
+		return lines, err
+	}
+
+	return lines, nil
+}
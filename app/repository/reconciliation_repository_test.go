@@ -0,0 +1,141 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconciliationRepository_Create(t *testing.T) {
+	// Create a mock DB and expect the reconciliation and its lines in one transaction
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.ReconciliationRepository{DB: sqlxDB}
+
+	settlementDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	reconciliation := &domain.Reconciliation{
+		FileName:       "settlement-2024-06-01.csv",
+		SettlementDate: settlementDate,
+		Matched:        1,
+		MissingAtBank:  1,
+		Lines: []*domain.ReconciliationLine{
+			{LineNo: 2, Reference: "WLT-1", Currency: "IDR", BankAmount: 300, OurAmount: 300, Status: domain.ReconciliationLineStatusMatched, Folio: "WLT-1"},
+			{Reference: "WLT-2", Currency: "IDR", OurAmount: 100, Status: domain.ReconciliationLineStatusMissingAtBank, Folio: "WLT-2"},
+		},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO reconciliations").
+		WithArgs("settlement-2024-06-01.csv", "", settlementDate, 1, 0, 1, 0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("INSERT INTO reconciliation_lines").
+		WithArgs(int64(4), 2, "WLT-1", "IDR", int64(300), int64(300), domain.ReconciliationLineStatusMatched, "WLT-1", "").
+		WillReturnResult(sqlmock.NewResult(10, 1))
+	mock.ExpectExec("INSERT INTO reconciliation_lines").
+		WithArgs(int64(4), 0, "WLT-2", "IDR", int64(0), int64(100), domain.ReconciliationLineStatusMissingAtBank, "WLT-2", "").
+		WillReturnResult(sqlmock.NewResult(11, 1))
+	mock.ExpectCommit()
+
+	// Execute the function
+	result, err := repo.Create(context.Background(), reconciliation)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), result.ID)
+	assert.Equal(t, int64(4), result.Lines[1].ReconciliationID)
+	assert.Equal(t, int64(11), result.Lines[1].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReconciliationRepository_Create_Error(t *testing.T) {
+	// Create a mock DB and expect a failing line to roll the reconciliation back
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.ReconciliationRepository{DB: sqlxDB}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO reconciliations").
+		WillReturnResult(sqlmock.NewResult(4, 1))
+	mock.ExpectExec("INSERT INTO reconciliation_lines").
+		WillReturnError(errors.New("insert failed"))
+	mock.ExpectRollback()
+
+	// Execute the function
+	_, err = repo.Create(context.Background(), &domain.Reconciliation{
+		Lines: []*domain.ReconciliationLine{{Reference: "WLT-1"}},
+	})
+
+	// Assert the expectations
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReconciliationRepository_GetByID(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.ReconciliationRepository{DB: sqlxDB}
+
+	rows := sqlmock.NewRows([]string{"id", "file_name", "matched"}).
+		AddRow(4, "settlement-2024-06-01.csv", 3)
+
+	mock.ExpectQuery("SELECT \\* FROM reconciliations WHERE id = \\?").
+		WithArgs(int64(4)).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetByID(context.Background(), 4)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.Reconciliation{ID: 4, FileName: "settlement-2024-06-01.csv", Matched: 3}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReconciliationRepository_GetLines(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.ReconciliationRepository{DB: sqlxDB}
+
+	expectedLines := []*domain.ReconciliationLine{
+		{ID: 10, ReconciliationID: 4, LineNo: 2, Reference: "WLT-1", Status: domain.ReconciliationLineStatusMatched},
+		{ID: 11, ReconciliationID: 4, Reference: "WLT-2", Status: domain.ReconciliationLineStatusMissingAtBank},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "reconciliation_id", "line_no", "reference", "status"})
+	for _, line := range expectedLines {
+		rows.AddRow(line.ID, line.ReconciliationID, line.LineNo, line.Reference, line.Status)
+	}
+
+	mock.ExpectQuery("SELECT \\* FROM reconciliation_lines WHERE reconciliation_id = \\? ORDER BY id").
+		WithArgs(int64(4)).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetLines(context.Background(), 4)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, expectedLines, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type ReversalRepository struct {
+	DB *sqlx.DB
+}
+
+func NewReversalRepository(db *sqlx.DB) *ReversalRepository {
+	return &ReversalRepository{
+		DB: db,
+	}
+}
+
+func (r *ReversalRepository) Create(ctx context.Context, reversal *domain.Reversal) (*domain.Reversal, error) {
+	createReversalQuery := `INSERT INTO reversals 
+	(reference_id, original_folio, transaction_group_id, reason, created_at) VALUES
+	(:reference_id, :original_folio, :transaction_group_id, :reason, :created_at)
+	ON CONFLICT (original_folio) DO NOTHING`
+
+	if reversal.CreatedAt.IsZero() {
+		reversal.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := conn(ctx, r.DB).NamedExecContext(ctx, createReversalQuery, reversal)
+	if err != nil {
+		log.Println("[Create] query err:", err)
+		return &domain.Reversal{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("[Create] rows affected err:", err)
+		return &domain.Reversal{}, err
+	}
+
+	if rowsAffected == 0 {
+		return &domain.Reversal{}, errors.ErrJournalAlreadyReversed
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		log.Println("[Create] last insert id err:", err)
+		return &domain.Reversal{}, err
+	}
+	reversal.ID = id
+
+	return reversal, nil
+}
+
+func (r *ReversalRepository) GetByOriginalFolio(ctx context.Context, originalFolio string) (*domain.Reversal, error) {
+	getByOriginalFolioQuery := `SELECT * FROM reversals WHERE original_folio = ?`
+
+	var reversal = &domain.Reversal{}
+	if err := conn(ctx, r.DB).GetContext(ctx, reversal, getByOriginalFolioQuery, originalFolio); err != nil {
+		log.Println("[GetByOriginalFolio] query err:", err)
+		return reversal, err
+	}
+
+	return reversal, nil
+}
@@ -0,0 +1,121 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReversalRepository_Create(t *testing.T) {
+	// Create a mock DB and expect the named exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.ReversalRepository{DB: sqlxDB}
+
+	reversal := &domain.Reversal{
+		ReferenceID:        "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W8",
+		OriginalFolio:      "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		TransactionGroupID: "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		Reason:             "sent to the wrong wallet",
+	}
+
+	mock.ExpectExec("INSERT INTO reversals \\(reference_id, original_folio, transaction_group_id, reason, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?\\) ON CONFLICT \\(original_folio\\) DO NOTHING").
+		WithArgs(reversal.ReferenceID, reversal.OriginalFolio, reversal.TransactionGroupID, reversal.Reason, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(4, 1))
+
+	// Execute the function
+	result, err := repo.Create(context.Background(), reversal)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), result.ID)
+	assert.False(t, result.CreatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReversalRepository_Create_AlreadyReversed(t *testing.T) {
+	// Create a mock DB and expect the named exec to hit the conflict clause
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.ReversalRepository{DB: sqlxDB}
+
+	mock.ExpectExec("INSERT INTO reversals").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute the function
+	_, err = repo.Create(context.Background(), &domain.Reversal{OriginalFolio: "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7"})
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrJournalAlreadyReversed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReversalRepository_GetByOriginalFolio(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.ReversalRepository{DB: sqlxDB}
+
+	createdAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	expectedReversal := &domain.Reversal{
+		ID:                 4,
+		ReferenceID:        "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W8",
+		OriginalFolio:      "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		TransactionGroupID: "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		Reason:             "sent to the wrong wallet",
+		CreatedAt:          createdAt,
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "reference_id", "original_folio", "transaction_group_id", "reason", "created_at"}).
+		AddRow(expectedReversal.ID, expectedReversal.ReferenceID, expectedReversal.OriginalFolio, expectedReversal.TransactionGroupID, expectedReversal.Reason, createdAt)
+
+	mock.ExpectQuery("SELECT \\* FROM reversals WHERE original_folio = \\?").
+		WithArgs(expectedReversal.OriginalFolio).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetByOriginalFolio(context.Background(), expectedReversal.OriginalFolio)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, expectedReversal, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReversalRepository_GetByOriginalFolio_NotFound(t *testing.T) {
+	// Create a mock DB and expect the query to find nothing
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.ReversalRepository{DB: sqlxDB}
+
+	mock.ExpectQuery("SELECT \\* FROM reversals WHERE original_folio = \\?").
+		WithArgs("WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7").
+		WillReturnError(sql.ErrNoRows)
+
+	// Execute the function
+	_, err = repo.GetByOriginalFolio(context.Background(), "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7")
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type TopUpRepository struct {
+	DB *sqlx.DB
+}
+
+func NewTopUpRepository(db *sqlx.DB) *TopUpRepository {
+	return &TopUpRepository{
+		DB: db,
+	}
+}
+
+func (r *TopUpRepository) Create(ctx context.Context, topUp *domain.TopUp) (*domain.TopUp, error) {
+	createTopUpQuery := `INSERT INTO top_ups 
+	(reference_id, user_id, amount, currency, source_reference, created_at) VALUES
+	(:reference_id, :user_id, :amount, :currency, :source_reference, :created_at)
+	ON CONFLICT (source_reference) DO NOTHING`
+
+	if topUp.CreatedAt.IsZero() {
+		topUp.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := conn(ctx, r.DB).NamedExecContext(ctx, createTopUpQuery, topUp)
+	if err != nil {
+		log.Println("[Create] query err:", err)
+		return &domain.TopUp{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("[Create] rows affected err:", err)
+		return &domain.TopUp{}, err
+	}
+
+	if rowsAffected == 0 {
+		return &domain.TopUp{}, errors.ErrDuplicateTopUpSourceReference
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		log.Println("[Create] last insert id err:", err)
+		return &domain.TopUp{}, err
+	}
+	topUp.ID = id
+
+	return topUp, nil
+}
+
+func (r *TopUpRepository) GetBySourceReference(ctx context.Context, sourceReference string) (*domain.TopUp, error) {
+	getBySourceReferenceQuery := `SELECT * FROM top_ups WHERE source_reference = ?`
+
+	var topUp = &domain.TopUp{}
+	if err := conn(ctx, r.DB).GetContext(ctx, topUp, getBySourceReferenceQuery, sourceReference); err != nil {
+		log.Println("[GetBySourceReference] query err:", err)
+		return topUp, err
+	}
+
+	return topUp, nil
+}
@@ -0,0 +1,129 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopUpRepository_Create(t *testing.T) {
+	// Create a mock DB and expect the named exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.TopUpRepository{DB: sqlxDB}
+
+	topUp := &domain.TopUp{
+		ReferenceID:     "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		UserID:          1,
+		Amount:          5000,
+		Currency:        "IDR",
+		SourceReference: "VA-20240601-0001",
+	}
+
+	mock.ExpectExec("INSERT INTO top_ups \\(reference_id, user_id, amount, currency, source_reference, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?\\) ON CONFLICT \\(source_reference\\) DO NOTHING").
+		WithArgs(topUp.ReferenceID, topUp.UserID, topUp.Amount, topUp.Currency, topUp.SourceReference, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	// Execute the function
+	result, err := repo.Create(context.Background(), topUp)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), result.ID)
+	assert.False(t, result.CreatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTopUpRepository_Create_DuplicateSourceReference(t *testing.T) {
+	// Create a mock DB and expect the named exec to hit the conflict clause
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.TopUpRepository{DB: sqlxDB}
+
+	topUp := &domain.TopUp{
+		ReferenceID:     "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		UserID:          1,
+		Amount:          5000,
+		SourceReference: "VA-20240601-0001",
+	}
+
+	mock.ExpectExec("INSERT INTO top_ups").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute the function
+	_, err = repo.Create(context.Background(), topUp)
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrDuplicateTopUpSourceReference)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTopUpRepository_GetBySourceReference(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.TopUpRepository{DB: sqlxDB}
+
+	createdAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	expectedTopUp := &domain.TopUp{
+		ID:              3,
+		ReferenceID:     "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		UserID:          1,
+		Amount:          5000,
+		SourceReference: "VA-20240601-0001",
+		CreatedAt:       createdAt,
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "reference_id", "user_id", "amount", "source_reference", "created_at"}).
+		AddRow(expectedTopUp.ID, expectedTopUp.ReferenceID, expectedTopUp.UserID, expectedTopUp.Amount, expectedTopUp.SourceReference, createdAt)
+
+	mock.ExpectQuery("SELECT \\* FROM top_ups WHERE source_reference = \\?").
+		WithArgs(expectedTopUp.SourceReference).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetBySourceReference(context.Background(), expectedTopUp.SourceReference)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, expectedTopUp, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTopUpRepository_GetBySourceReference_NotFound(t *testing.T) {
+	// Create a mock DB and expect the query to find nothing
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.TopUpRepository{DB: sqlxDB}
+
+	mock.ExpectQuery("SELECT \\* FROM top_ups WHERE source_reference = \\?").
+		WithArgs("VA-20240601-0001").
+		WillReturnError(sql.ErrNoRows)
+
+	// Execute the function
+	_, err = repo.GetBySourceReference(context.Background(), "VA-20240601-0001")
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
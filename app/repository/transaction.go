@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type txKey struct{}
+
+// executor is the part of sqlx shared by *sqlx.DB and *sqlx.Tx that the repositories use.
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error)
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// conn returns the transaction carried by ctx, or db when there is none.
+func conn(ctx context.Context, db *sqlx.DB) executor {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+type TransactionManager struct {
+	DB *sqlx.DB
+}
+
+func NewTransactionManager(db *sqlx.DB) *TransactionManager {
+	return &TransactionManager{
+		DB: db,
+	}
+}
+
+// WithinTransaction joins the transaction already carried by ctx, so nested calls commit
+// or roll back together with the outermost one.
+func (m *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Println("[WithinTransaction] begin err:", err)
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			log.Println("[WithinTransaction] rollback err:", rollbackErr)
+		}
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Println("[WithinTransaction] commit err:", err)
+		return err
+	}
+
+	return nil
+}
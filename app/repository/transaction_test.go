@@ -0,0 +1,141 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionManager_WithinTransaction_Commit(t *testing.T) {
+	// Create a mock DB and expect both writes inside one transaction
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	transactionManager := repository.NewTransactionManager(sqlxDB)
+	userBalanceRepo := repository.NewUserBalanceRepository(sqlxDB)
+	journalEntryRepo := repository.NewJournalEntryRepository(sqlxDB, repository.NewAccountingPeriodRepository(sqlxDB))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user_balances SET balance = \\?, held_balance = \\?, version = version \\+ 1").
+		WithArgs(int64(500), int64(0), int64(1), int64(0)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM accounting_periods").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery("SELECT hash FROM journal_entries").
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	mock.ExpectExec("INSERT INTO journal_entries").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	// Execute the function
+	err = transactionManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := userBalanceRepo.UpdateBalance(ctx, &domain.UserBalance{ID: 1, Balance: 500}); err != nil {
+			return err
+		}
+
+		_, err := journalEntryRepo.Create(ctx, &domain.JournalEntry{AccountID: "1", DebitAmount: 500})
+		return err
+	})
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionManager_WithinTransaction_Rollback(t *testing.T) {
+	// Create a mock DB and expect the transaction to roll back after the failed insert
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	transactionManager := repository.NewTransactionManager(sqlxDB)
+	userBalanceRepo := repository.NewUserBalanceRepository(sqlxDB)
+	journalEntryRepo := repository.NewJournalEntryRepository(sqlxDB, repository.NewAccountingPeriodRepository(sqlxDB))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user_balances SET balance = \\?, held_balance = \\?, version = version \\+ 1").
+		WithArgs(int64(500), int64(0), int64(1), int64(0)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM accounting_periods").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery("SELECT hash FROM journal_entries").
+		WillReturnRows(sqlmock.NewRows([]string{"hash"}))
+	mock.ExpectExec("INSERT INTO journal_entries").
+		WillReturnError(errors.New("insert failed"))
+	mock.ExpectRollback()
+
+	// Execute the function
+	err = transactionManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := userBalanceRepo.UpdateBalance(ctx, &domain.UserBalance{ID: 1, Balance: 500}); err != nil {
+			return err
+		}
+
+		_, err := journalEntryRepo.Create(ctx, &domain.JournalEntry{AccountID: "1", DebitAmount: 500})
+		return err
+	})
+
+	// Assert the expectations
+	assert.EqualError(t, err, "insert failed")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionManager_WithinTransaction_Nested(t *testing.T) {
+	// Create a mock DB and expect a single transaction for the nested calls
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	transactionManager := repository.NewTransactionManager(sqlxDB)
+	userBalanceRepo := repository.NewUserBalanceRepository(sqlxDB)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE user_balances SET balance = \\?, held_balance = \\?, version = version \\+ 1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Execute the function
+	err = transactionManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		return transactionManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			return userBalanceRepo.UpdateBalance(ctx, &domain.UserBalance{ID: 1, Balance: 500})
+		})
+	})
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactionManager_WithinTransaction_BeginError(t *testing.T) {
+	// Create a mock DB and expect begin to fail
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	transactionManager := repository.NewTransactionManager(sqlxDB)
+
+	mock.ExpectBegin().WillReturnError(errors.New("begin failed"))
+
+	called := false
+
+	// Execute the function
+	err = transactionManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	// Assert the expectations
+	assert.EqualError(t, err, "begin failed")
+	assert.False(t, called)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+)
+
+type TransferRepository struct {
+	DB *sqlx.DB
+}
+
+func NewTransferRepository(db *sqlx.DB) *TransferRepository {
+	return &TransferRepository{
+		DB: db,
+	}
+}
+
+func (r *TransferRepository) Create(ctx context.Context, transfer *domain.Transfer) (*domain.Transfer, error) {
+	createTransferQuery := `INSERT INTO transfers 
+	(reference_id, from_user_id, to_user_id, amount, currency, created_at) VALUES
+	(:reference_id, :from_user_id, :to_user_id, :amount, :currency, :created_at)`
+
+	if transfer.CreatedAt.IsZero() {
+		transfer.CreatedAt = time.Now().UTC()
+	}
+
+	result, err := conn(ctx, r.DB).NamedExecContext(ctx, createTransferQuery, transfer)
+	if err != nil {
+		log.Println("[Create] query err:", err)
+		return &domain.Transfer{}, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		log.Println("[Create] last insert id err:", err)
+		return &domain.Transfer{}, err
+	}
+	transfer.ID = id
+
+	return transfer, nil
+}
@@ -0,0 +1,65 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferRepository_Create(t *testing.T) {
+	// Create a mock DB and expect the named exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.TransferRepository{DB: sqlxDB}
+
+	transfer := &domain.Transfer{
+		ReferenceID: "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7",
+		FromUserID:  2,
+		ToUserID:    1,
+		Amount:      600,
+		Currency:    "IDR",
+	}
+
+	mock.ExpectExec("INSERT INTO transfers \\(reference_id, from_user_id, to_user_id, amount, currency, created_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?\\)").
+		WithArgs(transfer.ReferenceID, transfer.FromUserID, transfer.ToUserID, transfer.Amount, transfer.Currency, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+
+	// Execute the function
+	result, err := repo.Create(context.Background(), transfer)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), result.ID)
+	assert.False(t, result.CreatedAt.IsZero())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransferRepository_Create_Error(t *testing.T) {
+	// Create a mock DB and expect the named exec to fail
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.TransferRepository{DB: sqlxDB}
+
+	mock.ExpectExec("INSERT INTO transfers").
+		WillReturnError(errors.New("some error"))
+
+	// Execute the function
+	result, err := repo.Create(context.Background(), &domain.Transfer{ReferenceID: "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7"})
+
+	// Assert the expectations
+	assert.Error(t, err)
+	assert.Equal(t, &domain.Transfer{}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
 )
 
 type UserBalanceRepository struct {
@@ -18,26 +20,103 @@ func NewUserBalanceRepository(db *sqlx.DB) *UserBalanceRepository {
 	}
 }
 
-func (r *UserBalanceRepository) GetByID(ctx context.Context, id int64) (*domain.UserBalance, error) {
-	getByIDQuery := `SELECT * FROM user_balances WHERE id = ?`
+// Create opens the wallet of userBalance, failing with ErrWalletAlreadyExists when the user
+// already has one in its currency.
+func (r *UserBalanceRepository) Create(ctx context.Context, userBalance *domain.UserBalance) (*domain.UserBalance, error) {
+	createQuery := `INSERT INTO user_balances 
+	(user_id, username, currency, balance, held_balance, bank_code, account_no, account_name, created_at, updated_at) VALUES
+	(:user_id, :username, :currency, :balance, :held_balance, :bank_code, :account_no, :account_name, :created_at, :updated_at)
+	ON CONFLICT (user_id, currency) DO NOTHING`
+
+	if userBalance.CreatedAt.IsZero() {
+		userBalance.CreatedAt = time.Now().UTC()
+		userBalance.UpdatedAt = userBalance.CreatedAt
+	}
+
+	result, err := conn(ctx, r.DB).NamedExecContext(ctx, createQuery, userBalance)
+	if err != nil {
+		log.Println("[Create] query err:", err)
+		return &domain.UserBalance{}, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Println("[Create] rows affected err:", err)
+		return &domain.UserBalance{}, err
+	}
+
+	if rowsAffected == 0 {
+		return &domain.UserBalance{}, errors.ErrWalletAlreadyExists
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		log.Println("[Create] last insert id err:", err)
+		return &domain.UserBalance{}, err
+	}
+	userBalance.ID = id
+
+	return userBalance, nil
+}
+
+func (r *UserBalanceRepository) GetByUserIDAndCurrency(ctx context.Context, userID int64, currency string) (*domain.UserBalance, error) {
+	getByUserIDAndCurrencyQuery := `SELECT * FROM user_balances WHERE user_id = ? AND currency = ?`
 
 	var userBalance = &domain.UserBalance{}
-	if err := r.DB.GetContext(ctx, userBalance, getByIDQuery, id); err != nil {
-		log.Println("[GetByID] query err:", err)
+	if err := conn(ctx, r.DB).GetContext(ctx, userBalance, getByUserIDAndCurrencyQuery, userID, currency); err != nil {
+		log.Println("[GetByUserIDAndCurrency] query err:", err)
 		return userBalance, err
 	}
 
 	return userBalance, nil
 }
 
-func (r *UserBalanceRepository) UpdateBalanceByID(ctx context.Context, updatedBalance, id int64) error {
-	updateBalanceByIDQuery := `UPDATE user_balances SET balance = ? WHERE id = ?`
+// GetAllByUserID returns the wallets of a user, the oldest first.
+func (r *UserBalanceRepository) GetAllByUserID(ctx context.Context, userID int64) ([]*domain.UserBalance, error) {
+	getAllByUserIDQuery := `SELECT * FROM user_balances WHERE user_id = ? ORDER BY id`
 
-	_, err := r.DB.ExecContext(ctx, updateBalanceByIDQuery, updatedBalance, id)
+	var userBalances []*domain.UserBalance
+	if err := conn(ctx, r.DB).SelectContext(ctx, &userBalances, getAllByUserIDQuery, userID); err != nil {
+		log.Println("[GetAllByUserID] query err:", err)
+		return nil, err
+	}
+
+	return userBalances, nil
+}
+
+func (r *UserBalanceRepository) GetAll(ctx context.Context) ([]*domain.UserBalance, error) {
+	getAllQuery := `SELECT * FROM user_balances ORDER BY id`
+
+	var userBalances []*domain.UserBalance
+	if err := conn(ctx, r.DB).SelectContext(ctx, &userBalances, getAllQuery); err != nil {
+		log.Println("[GetAll] query err:", err)
+		return nil, err
+	}
+
+	return userBalances, nil
+}
+
+// UpdateBalance saves the balances of userBalance only if the row still has the version
+// it was read with, and bumps the version on success.
+func (r *UserBalanceRepository) UpdateBalance(ctx context.Context, userBalance *domain.UserBalance) error {
+	updateBalanceQuery := `UPDATE user_balances SET balance = ?, held_balance = ?, version = version + 1, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND version = ?`
+
+	result, err := conn(ctx, r.DB).ExecContext(ctx, updateBalanceQuery, userBalance.Balance, userBalance.HeldBalance, userBalance.ID, userBalance.Version)
+	if err != nil {
+		log.Println("[UpdateBalance] query err:", err)
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		log.Println("[UpdateBalanceByID] query err:", err)
+		log.Println("[UpdateBalance] rows affected err:", err)
 		return err
 	}
 
+	if rowsAffected == 0 {
+		return errors.ErrBalanceVersionConflict
+	}
+	userBalance.Version++
+
 	return nil
 }
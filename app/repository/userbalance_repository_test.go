@@ -9,11 +9,12 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
 	"github.com/krisdioles/ppr-wallet/app/repository"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestUserBalanceRepository_GetByID(t *testing.T) {
+func TestUserBalanceRepository_GetByUserIDAndCurrency(t *testing.T) {
 	// Create a mock DB and expect the query
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -24,19 +25,21 @@ func TestUserBalanceRepository_GetByID(t *testing.T) {
 
 	userID := int64(1)
 	expectedUserBalance := &domain.UserBalance{
-		ID:      userID,
-		Balance: 1000,
+		ID:       3,
+		UserID:   userID,
+		Currency: "USD",
+		Balance:  1000,
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "balance"}).
-		AddRow(expectedUserBalance.ID, expectedUserBalance.Balance)
+	rows := sqlmock.NewRows([]string{"id", "user_id", "currency", "balance"}).
+		AddRow(expectedUserBalance.ID, expectedUserBalance.UserID, expectedUserBalance.Currency, expectedUserBalance.Balance)
 
-	mock.ExpectQuery("SELECT \\* FROM user_balances WHERE id = ?").
-		WithArgs(userID).
+	mock.ExpectQuery("SELECT \\* FROM user_balances WHERE user_id = \\? AND currency = \\?").
+		WithArgs(userID, "USD").
 		WillReturnRows(rows)
 
 	// Execute the function
-	result, err := repo.GetByID(context.Background(), userID)
+	result, err := repo.GetByUserIDAndCurrency(context.Background(), userID, "USD")
 
 	// Assert the expectations
 	assert.NoError(t, err)
@@ -44,7 +47,7 @@ func TestUserBalanceRepository_GetByID(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUserBalanceRepository_GetByID_Error(t *testing.T) {
+func TestUserBalanceRepository_GetByUserIDAndCurrency_Error(t *testing.T) {
 	// Create a mock DB and expect the query
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -55,12 +58,12 @@ func TestUserBalanceRepository_GetByID_Error(t *testing.T) {
 
 	userID := int64(1)
 
-	mock.ExpectQuery("SELECT \\* FROM user_balances WHERE id = ?").
-		WithArgs(userID).
+	mock.ExpectQuery("SELECT \\* FROM user_balances WHERE user_id = \\? AND currency = \\?").
+		WithArgs(userID, "IDR").
 		WillReturnError(sql.ErrNoRows)
 
 	// Execute the function
-	result, err := repo.GetByID(context.Background(), userID)
+	result, err := repo.GetByUserIDAndCurrency(context.Background(), userID, "IDR")
 
 	// Assert the expectations
 	assert.Error(t, err)
@@ -68,7 +71,7 @@ func TestUserBalanceRepository_GetByID_Error(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUserBalanceRepository_UpdateBalanceByID(t *testing.T) {
+func TestUserBalanceRepository_Create(t *testing.T) {
 	// Create a mock DB and expect the exec
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -77,22 +80,26 @@ func TestUserBalanceRepository_UpdateBalanceByID(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := repository.UserBalanceRepository{DB: sqlxDB}
 
-	userID := int64(1)
-	newBalance := int64(0)
+	userBalance := &domain.UserBalance{
+		UserID:   1,
+		Username: "andy123",
+		Currency: "USD",
+	}
 
-	mock.ExpectExec("UPDATE user_balances SET balance = \\? WHERE id = \\?").
-		WithArgs(newBalance, userID).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO user_balances \\(user_id, username, currency, balance, held_balance, bank_code, account_no, account_name, created_at, updated_at\\) VALUES \\(\\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?, \\?\\) ON CONFLICT \\(user_id, currency\\) DO NOTHING").
+		WithArgs(int64(1), "andy123", "USD", int64(0), int64(0), "", "", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(4, 1))
 
 	// Execute the function
-	err = repo.UpdateBalanceByID(context.Background(), newBalance, userID)
+	result, err := repo.Create(context.Background(), userBalance)
 
 	// Assert the expectations
 	assert.NoError(t, err)
+	assert.Equal(t, int64(4), result.ID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUserBalanceRepository_UpdateBalanceByID_Error(t *testing.T) {
+func TestUserBalanceRepository_Create_AlreadyExists(t *testing.T) {
 	// Create a mock DB and expect the exec
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -101,17 +108,148 @@ func TestUserBalanceRepository_UpdateBalanceByID_Error(t *testing.T) {
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	repo := repository.UserBalanceRepository{DB: sqlxDB}
 
-	userID := int64(1)
-	newBalance := int64(2000)
+	mock.ExpectExec("INSERT INTO user_balances").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute the function
+	_, err = repo.Create(context.Background(), &domain.UserBalance{UserID: 1, Currency: "USD"})
+
+	// Assert the expectations
+	assert.Equal(t, domErr.ErrWalletAlreadyExists, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserBalanceRepository_GetAllByUserID(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.UserBalanceRepository{DB: sqlxDB}
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "currency"}).
+		AddRow(1, 1, "IDR").
+		AddRow(4, 1, "USD")
+
+	mock.ExpectQuery("SELECT \\* FROM user_balances WHERE user_id = \\? ORDER BY id").
+		WithArgs(int64(1)).
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetAllByUserID(context.Background(), 1)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "USD", result[1].Currency)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserBalanceRepository_UpdateBalance(t *testing.T) {
+	// Create a mock DB and expect the exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.UserBalanceRepository{DB: sqlxDB}
+
+	userBalance := &domain.UserBalance{
+		ID:          1,
+		Balance:     1000,
+		HeldBalance: 500,
+		Version:     3,
+	}
+
+	mock.ExpectExec("UPDATE user_balances SET balance = \\?, held_balance = \\?, version = version \\+ 1, updated_at = CURRENT_TIMESTAMP WHERE id = \\? AND version = \\?").
+		WithArgs(userBalance.Balance, userBalance.HeldBalance, userBalance.ID, int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Execute the function
+	err = repo.UpdateBalance(context.Background(), userBalance)
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), userBalance.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	mock.ExpectExec("UPDATE user_balances SET balance = \\? WHERE id = \\?").
-		WithArgs(newBalance, userID).
+func TestUserBalanceRepository_UpdateBalance_VersionConflict(t *testing.T) {
+	// Create a mock DB and expect the exec to match no rows
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.UserBalanceRepository{DB: sqlxDB}
+
+	userBalance := &domain.UserBalance{
+		ID:      1,
+		Balance: 1000,
+		Version: 3,
+	}
+
+	mock.ExpectExec("UPDATE user_balances SET balance = \\?, held_balance = \\?, version = version \\+ 1").
+		WithArgs(userBalance.Balance, userBalance.HeldBalance, userBalance.ID, int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute the function
+	err = repo.UpdateBalance(context.Background(), userBalance)
+
+	// Assert the expectations
+	assert.ErrorIs(t, err, domErr.ErrBalanceVersionConflict)
+	assert.Equal(t, int64(3), userBalance.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserBalanceRepository_UpdateBalance_Error(t *testing.T) {
+	// Create a mock DB and expect the exec
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.UserBalanceRepository{DB: sqlxDB}
+
+	userBalance := &domain.UserBalance{
+		ID:      1,
+		Balance: 2000,
+	}
+
+	mock.ExpectExec("UPDATE user_balances SET balance = \\?, held_balance = \\?, version = version \\+ 1").
+		WithArgs(userBalance.Balance, userBalance.HeldBalance, userBalance.ID, userBalance.Version).
 		WillReturnError(errors.New("some error"))
 
 	// Execute the function
-	err = repo.UpdateBalanceByID(context.Background(), newBalance, userID)
+	err = repo.UpdateBalance(context.Background(), userBalance)
 
 	// Assert the expectations
 	assert.Error(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUserBalanceRepository_GetAll(t *testing.T) {
+	// Create a mock DB and expect the query
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	repo := repository.UserBalanceRepository{DB: sqlxDB}
+
+	rows := sqlmock.NewRows([]string{"id", "balance"}).
+		AddRow(1, 1000).
+		AddRow(2, 2000)
+
+	mock.ExpectQuery("SELECT \\* FROM user_balances ORDER BY id").
+		WillReturnRows(rows)
+
+	// Execute the function
+	result, err := repo.GetAll(context.Background())
+
+	// Assert the expectations
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.UserBalance{{ID: 1, Balance: 1000}, {ID: 2, Balance: 2000}}, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
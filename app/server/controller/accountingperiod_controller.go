@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type AccountingPeriodController struct {
+	AccountingPeriodUsecase domain.AccountingPeriodUsecase
+}
+
+func NewAccountingPeriodController(accountingPeriodUsecase domain.AccountingPeriodUsecase) *AccountingPeriodController {
+	return &AccountingPeriodController{
+		AccountingPeriodUsecase: accountingPeriodUsecase,
+	}
+}
+
+func (c *AccountingPeriodController) OpenPeriod(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	var request domain.OpenAccountingPeriodRequest
+	if err := gc.ShouldBindJSON(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	accountingPeriod, err := c.AccountingPeriodUsecase.OpenPeriod(ctx, &request)
+	if err != nil {
+		respondAccountingPeriodError(gc, err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    accountingPeriod,
+	})
+}
+
+func (c *AccountingPeriodController) GetPeriods(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	accountingPeriods, err := c.AccountingPeriodUsecase.GetPeriods(ctx)
+	if err != nil {
+		respondAccountingPeriodError(gc, err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    accountingPeriods,
+	})
+}
+
+func (c *AccountingPeriodController) GetPeriod(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	accountingPeriod, err := c.AccountingPeriodUsecase.GetPeriod(ctx, gc.Param("period"))
+	if err != nil {
+		respondAccountingPeriodError(gc, err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    accountingPeriod,
+	})
+}
+
+func (c *AccountingPeriodController) ClosePeriod(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	accountingPeriod, err := c.AccountingPeriodUsecase.ClosePeriod(ctx, gc.Param("period"))
+	if err != nil {
+		respondAccountingPeriodError(gc, err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    accountingPeriod,
+	})
+}
+
+func respondAccountingPeriodError(gc *gin.Context, err error) {
+	switch err {
+	case errors.ErrInvalidParameter:
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	case errors.ErrAccountingPeriodNotFound:
+		gc.JSON(http.StatusNotFound, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	case errors.ErrAccountingPeriodAlreadyExists, errors.ErrAccountingPeriodClosed:
+		gc.JSON(http.StatusConflict, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	case errors.ErrAccountingPeriodNotEnded, errors.ErrEarlierAccountingPeriodOpen:
+		gc.JSON(http.StatusUnprocessableEntity, gin.H{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	default:
+		gc.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": errors.ErrInternalServerError.Error(),
+		})
+	}
+}
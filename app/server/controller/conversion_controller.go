@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type ConversionController struct {
+	ConversionUsecase domain.ConversionUsecase
+}
+
+func NewConversionController(conversionUsecase domain.ConversionUsecase) *ConversionController {
+	return &ConversionController{
+		ConversionUsecase: conversionUsecase,
+	}
+}
+
+func (c *ConversionController) ConvertCurrency(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	idParam, err := strconv.Atoi(gc.Param("id"))
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	var request domain.ConvertCurrencyRequest
+	if err = gc.ShouldBindJSON(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	conversion, err := c.ConversionUsecase.ConvertCurrency(ctx, int64(idParam), &request)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidParameter:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrUserNotFound, errors.ErrFXQuoteNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrFXQuoteAlreadyUsed, errors.ErrBalanceVersionConflict:
+			gc.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrFXQuoteExpired, errors.ErrInsufficientBalance:
+			gc.JSON(http.StatusUnprocessableEntity, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    conversion,
+	})
+}
@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type DisbursementController struct {
+	DisbursementUsecase domain.DisbursementUsecase
+	UserBalanceUsecase  domain.UserBalanceUsecase
+}
+
+func NewDisbursementController(disbursementUsecase domain.DisbursementUsecase, userBalanceUsecase domain.UserBalanceUsecase) *DisbursementController {
+	return &DisbursementController{
+		DisbursementUsecase: disbursementUsecase,
+		UserBalanceUsecase:  userBalanceUsecase,
+	}
+}
+
+func (c *DisbursementController) GetDisbursementByReferenceID(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	disbursement, err := c.DisbursementUsecase.GetDisbursementByReferenceID(ctx, gc.Param("reference_id"))
+	if err != nil {
+		switch err {
+		case errors.ErrDisbursementNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    disbursement,
+	})
+}
+
+func (c *DisbursementController) GetDisbursementsByUserID(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	idParam, err := strconv.Atoi(gc.Param("id"))
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	disbursements, err := c.DisbursementUsecase.GetDisbursementsByUserID(ctx, int64(idParam))
+	if err != nil {
+		switch err {
+		case errors.ErrUserNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    disbursements,
+	})
+}
+
+func (c *DisbursementController) GetUserDisbursementByID(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	idParam, err := strconv.Atoi(gc.Param("id"))
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	disbursementIDParam, err := strconv.Atoi(gc.Param("disbursement_id"))
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	disbursement, err := c.DisbursementUsecase.GetUserDisbursementByID(ctx, int64(idParam), int64(disbursementIDParam))
+	if err != nil {
+		switch err {
+		case errors.ErrDisbursementNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    disbursement,
+	})
+}
+
+// SettleDisbursement records the outcome of a disbursement found out by hand, typically one
+// escalated to manual review because its provider never reported it.
+func (c *DisbursementController) SettleDisbursement(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	var request domain.SettleDisbursementRequest
+	if err := gc.ShouldBindJSON(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	disbursement, err := c.UserBalanceUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+		ReferenceID:   gc.Param("reference_id"),
+		Status:        request.Status,
+		FailureReason: request.FailureReason,
+		ByOperator:    true,
+	})
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidParameter:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrDisbursementNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrInvalidDisbursementStatusTransition, errors.ErrDisbursementStatusConflict, errors.ErrBalanceVersionConflict:
+			gc.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    disbursement,
+	})
+}
@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type FXController struct {
+	FXUsecase domain.FXUsecase
+}
+
+func NewFXController(fxUsecase domain.FXUsecase) *FXController {
+	return &FXController{
+		FXUsecase: fxUsecase,
+	}
+}
+
+func (c *FXController) GetRates(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	rates, err := c.FXUsecase.GetRates(ctx)
+	if err != nil {
+		gc.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": errors.ErrInternalServerError.Error(),
+		})
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    rates,
+	})
+}
+
+func (c *FXController) SetRates(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	var request domain.SetFXRatesRequest
+	if err := gc.ShouldBindJSON(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	rates, err := c.FXUsecase.SetRates(ctx, &request)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidParameter, errors.ErrSameCurrencyConversion:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    rates,
+	})
+}
+
+func (c *FXController) CreateQuote(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	idParam, err := strconv.Atoi(gc.Param("id"))
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	var request domain.CreateFXQuoteRequest
+	if err = gc.ShouldBindJSON(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	quote, err := c.FXUsecase.CreateQuote(ctx, int64(idParam), &request)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidParameter, errors.ErrSameCurrencyConversion, errors.ErrConvertedAmountTooLarge:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrUserNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrFXRateNotFound:
+			gc.JSON(http.StatusUnprocessableEntity, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    quote,
+	})
+}
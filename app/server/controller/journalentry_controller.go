@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type JournalEntryController struct {
+	JournalEntryUsecase domain.JournalEntryUsecase
+}
+
+func NewJournalEntryController(journalEntryUsecase domain.JournalEntryUsecase) *JournalEntryController {
+	return &JournalEntryController{
+		JournalEntryUsecase: journalEntryUsecase,
+	}
+}
+
+func (c *JournalEntryController) GetJournalEntries(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	var filter domain.JournalEntryFilter
+	if err := gc.ShouldBindQuery(&filter); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	page, err := c.JournalEntryUsecase.GetJournalEntries(ctx, &filter)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidParameter:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    page,
+	})
+}
+
+func (c *JournalEntryController) GetUserStatement(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	idParam, err := strconv.Atoi(gc.Param("id"))
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	var filter domain.JournalEntryFilter
+	if err = gc.ShouldBindQuery(&filter); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	statement, err := c.JournalEntryUsecase.GetUserStatement(ctx, int64(idParam), &filter)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidParameter:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrUserNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    statement,
+	})
+}
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type LedgerController struct {
+	LedgerUsecase domain.LedgerUsecase
+}
+
+func NewLedgerController(ledgerUsecase domain.LedgerUsecase) *LedgerController {
+	return &LedgerController{
+		LedgerUsecase: ledgerUsecase,
+	}
+}
+
+func (c *LedgerController) GetAccountBalance(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	accountBalance, err := c.LedgerUsecase.GetAccountBalance(ctx, gc.Param("account_id"))
+	if err != nil {
+		switch err {
+		case errors.ErrLedgerAccountNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    accountBalance,
+	})
+}
+
+func (c *LedgerController) VerifyWalletBalances(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	report, err := c.LedgerUsecase.VerifyWalletBalances(ctx)
+	if err != nil {
+		gc.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": errors.ErrInternalServerError.Error(),
+		})
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    report,
+	})
+}
+
+func (c *LedgerController) GetBalanceVerifications(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	reports, err := c.LedgerUsecase.GetBalanceVerifications(ctx)
+	if err != nil {
+		gc.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": errors.ErrInternalServerError.Error(),
+		})
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    reports,
+	})
+}
+
+func (c *LedgerController) VerifyHashChain(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	report, err := c.LedgerUsecase.VerifyHashChain(ctx, gc.Query("checkpoint"))
+	if err != nil {
+		gc.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": errors.ErrInternalServerError.Error(),
+		})
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    report,
+	})
+}
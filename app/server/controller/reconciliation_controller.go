@@ -0,0 +1,144 @@
+package controller
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type ReconciliationController struct {
+	ReconciliationUsecase domain.ReconciliationUsecase
+}
+
+func NewReconciliationController(reconciliationUsecase domain.ReconciliationUsecase) *ReconciliationController {
+	return &ReconciliationController{
+		ReconciliationUsecase: reconciliationUsecase,
+	}
+}
+
+// ReconcileSettlement takes the settlement file as the multipart field file.
+func (c *ReconciliationController) ReconcileSettlement(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	var request domain.ReconcileSettlementRequest
+	if err := gc.ShouldBind(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	fileHeader, err := gc.FormFile("file")
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	reconciliation, err := c.ReconciliationUsecase.ReconcileSettlement(ctx, &request, fileHeader.Filename, file)
+	if err != nil {
+		switch {
+		// the message tells which line of the file is wrong
+		case stderrors.Is(err, errors.ErrInvalidSettlementFile):
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case err == errors.ErrInvalidParameter:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    reconciliation,
+	})
+}
+
+func (c *ReconciliationController) GetReconciliations(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	reconciliations, err := c.ReconciliationUsecase.GetReconciliations(ctx)
+	if err != nil {
+		gc.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": errors.ErrInternalServerError.Error(),
+		})
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    reconciliations,
+	})
+}
+
+func (c *ReconciliationController) GetReconciliation(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	id, err := strconv.ParseInt(gc.Param("id"), 10, 64)
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	var request domain.ReconciliationReportRequest
+	if err := gc.ShouldBindQuery(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	reconciliation, err := c.ReconciliationUsecase.GetReconciliation(ctx, id)
+	if err != nil {
+		switch err {
+		case errors.ErrReconciliationNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	respondReport(gc, request.Format, fmt.Sprintf("reconciliation-%d.csv", reconciliation.ID), reconciliation)
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type ReportController struct {
+	ReportUsecase domain.ReportUsecase
+}
+
+func NewReportController(reportUsecase domain.ReportUsecase) *ReportController {
+	return &ReportController{
+		ReportUsecase: reportUsecase,
+	}
+}
+
+// report is a report that can also be written as CSV.
+type report interface {
+	Records() [][]string
+}
+
+func (c *ReportController) GetTrialBalance(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	var request domain.ReportRequest
+	if err := gc.ShouldBindQuery(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	trialBalance, err := c.ReportUsecase.GetTrialBalance(ctx, request.AsOf, request.Currency)
+	if err != nil {
+		gc.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": errors.ErrInternalServerError.Error(),
+		})
+		return
+	}
+
+	respondReport(gc, request.Format, fmt.Sprintf("trial-balance-%s-%s.csv", trialBalance.Currency, trialBalance.AsOf.Format(domain.ReportDateFormat)), trialBalance)
+}
+
+func (c *ReportController) GetBalanceSheet(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	var request domain.ReportRequest
+	if err := gc.ShouldBindQuery(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	balanceSheet, err := c.ReportUsecase.GetBalanceSheet(ctx, request.AsOf, request.Currency)
+	if err != nil {
+		gc.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": errors.ErrInternalServerError.Error(),
+		})
+		return
+	}
+
+	respondReport(gc, request.Format, fmt.Sprintf("balance-sheet-%s-%s.csv", balanceSheet.Currency, balanceSheet.AsOf.Format(domain.ReportDateFormat)), balanceSheet)
+}
+
+func (c *ReportController) GetIncomeStatement(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	var request domain.ReportRequest
+	if err := gc.ShouldBindQuery(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	incomeStatement, err := c.ReportUsecase.GetIncomeStatement(ctx, request.From, request.To, request.Currency)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidParameter:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	respondReport(gc, request.Format, fmt.Sprintf("income-statement-%s-%s.csv", incomeStatement.Currency, incomeStatement.To.Format(domain.ReportDateFormat)), incomeStatement)
+}
+
+// respondReport writes data as a CSV attachment when format asks for it, and as JSON otherwise.
+func respondReport(gc *gin.Context, format domain.ReportFormat, filename string, data report) {
+	if format != domain.ReportFormatCSV {
+		gc.JSON(http.StatusOK, gin.H{
+			"status":  "ok",
+			"message": "success",
+			"data":    data,
+		})
+		return
+	}
+
+	gc.Header("Content-Type", "text/csv")
+	gc.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	gc.Status(http.StatusOK)
+
+	writer := csv.NewWriter(gc.Writer)
+	writer.WriteAll(data.Records())
+}
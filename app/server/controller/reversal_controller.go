@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type ReversalController struct {
+	ReversalUsecase domain.ReversalUsecase
+}
+
+func NewReversalController(reversalUsecase domain.ReversalUsecase) *ReversalController {
+	return &ReversalController{
+		ReversalUsecase: reversalUsecase,
+	}
+}
+
+func (c *ReversalController) ReverseJournal(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	var request domain.ReverseJournalRequest
+	if err := gc.ShouldBindJSON(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	reversal, err := c.ReversalUsecase.ReverseJournal(ctx, gc.Param("folio"), &request)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidParameter:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrJournalNotFound, errors.ErrUserNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrJournalAlreadyReversed, errors.ErrBalanceVersionConflict, errors.ErrInvalidDisbursementStatusTransition, errors.ErrDisbursementStatusConflict, errors.ErrDisbursementUnderReview:
+			gc.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrJournalNotReversible, errors.ErrInsufficientBalance:
+			gc.JSON(http.StatusUnprocessableEntity, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    reversal,
+	})
+}
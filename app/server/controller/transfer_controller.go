@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type TransferController struct {
+	TransferUsecase domain.TransferUsecase
+}
+
+func NewTransferController(transferUsecase domain.TransferUsecase) *TransferController {
+	return &TransferController{
+		TransferUsecase: transferUsecase,
+	}
+}
+
+func (c *TransferController) TransferBalance(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	idParam, err := strconv.Atoi(gc.Param("id"))
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	var request domain.TransferBalanceRequest
+	if err = gc.ShouldBindJSON(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	transfer, err := c.TransferUsecase.TransferBalance(ctx, int64(idParam), &request)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidParameter, errors.ErrSelfTransfer:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrInsufficientBalance:
+			gc.JSON(http.StatusUnprocessableEntity, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrUserNotFound, errors.ErrRecipientNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrBalanceVersionConflict:
+			gc.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    transfer,
+	})
+}
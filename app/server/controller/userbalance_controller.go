@@ -9,6 +9,8 @@ import (
 	"github.com/krisdioles/ppr-wallet/app/domain/errors"
 )
 
+const disbursementOutcomeUnknownMessage = "the bank did not answer, the outcome of the disbursement is unknown and its amount stays held until it is settled"
+
 type UserBalanceController struct {
 	UserBalanceUsecase domain.UserBalanceUsecase
 }
@@ -31,7 +33,7 @@ func (c *UserBalanceController) GetUserBalanceByID(gc *gin.Context) {
 		return
 	}
 
-	userBalance, err := c.UserBalanceUsecase.GetUserBalanceByID(ctx, int64(idParam))
+	userBalance, err := c.UserBalanceUsecase.GetUserBalanceByID(ctx, int64(idParam), gc.Query("currency"))
 	if err != nil {
 		switch err {
 		case errors.ErrUserNotFound:
@@ -56,6 +58,98 @@ func (c *UserBalanceController) GetUserBalanceByID(gc *gin.Context) {
 	})
 }
 
+func (c *UserBalanceController) GetUserWallets(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	idParam, err := strconv.Atoi(gc.Param("id"))
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	userBalances, err := c.UserBalanceUsecase.GetUserWallets(ctx, int64(idParam))
+	if err != nil {
+		switch err {
+		case errors.ErrUserNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    userBalances,
+	})
+}
+
+func (c *UserBalanceController) OpenWallet(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	idParam, err := strconv.Atoi(gc.Param("id"))
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	var request domain.OpenWalletRequest
+	if err = gc.ShouldBindJSON(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	userBalance, err := c.UserBalanceUsecase.OpenWallet(ctx, int64(idParam), &request)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidParameter:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrUserNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrWalletAlreadyExists:
+			gc.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    userBalance,
+	})
+}
+
 func (c *UserBalanceController) DisburseBalance(gc *gin.Context) {
 	ctx := gc.Request.Context()
 
@@ -68,9 +162,23 @@ func (c *UserBalanceController) DisburseBalance(gc *gin.Context) {
 		return
 	}
 
-	err = c.UserBalanceUsecase.DisburseBalance(ctx, int64(idParam))
+	var request domain.DisburseBalanceRequest
+	if err = gc.ShouldBindJSON(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	disbursement, err := c.UserBalanceUsecase.DisburseBalance(ctx, int64(idParam), &request)
 	if err != nil {
 		switch err {
+		case errors.ErrInvalidParameter:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
 		case errors.ErrInsufficientBalance:
 			gc.JSON(http.StatusUnprocessableEntity, gin.H{
 				"status":  "error",
@@ -81,6 +189,81 @@ func (c *UserBalanceController) DisburseBalance(gc *gin.Context) {
 				"status":  "error",
 				"message": err.Error(),
 			})
+		case errors.ErrBalanceVersionConflict:
+			gc.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrNoPayoutProvider:
+			gc.JSON(http.StatusServiceUnavailable, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	// the bank never answered, the client must not take this for a payout that went through
+	if disbursement.OutcomeUnknown() {
+		gc.JSON(http.StatusAccepted, gin.H{
+			"status":  "ok",
+			"message": disbursementOutcomeUnknownMessage,
+			"data":    disbursement,
+		})
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    disbursement,
+	})
+}
+
+func (c *UserBalanceController) TopUpBalance(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	idParam, err := strconv.Atoi(gc.Param("id"))
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	var request domain.TopUpBalanceRequest
+	if err = gc.ShouldBindJSON(&request); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	topUp, err := c.UserBalanceUsecase.TopUpBalance(ctx, int64(idParam), &request)
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidParameter:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrUserNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrTopUpSourceReferenceConflict, errors.ErrBalanceVersionConflict:
+			gc.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
 		default:
 			gc.JSON(http.StatusInternalServerError, gin.H{
 				"status":  "error",
@@ -93,5 +276,6 @@ func (c *UserBalanceController) DisburseBalance(gc *gin.Context) {
 	gc.JSON(http.StatusOK, gin.H{
 		"status":  "ok",
 		"message": "success",
+		"data":    topUp,
 	})
 }
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/external"
+)
+
+var bank1DisbursementStatuses = map[string]domain.DisbursementStatus{
+	external.Bank1DisbursementStatusCompleted: domain.DisbursementStatusCompleted,
+	external.Bank1DisbursementStatusFailed:    domain.DisbursementStatusFailed,
+}
+
+type WebhookController struct {
+	UserBalanceUsecase domain.UserBalanceUsecase
+	Bank1WebhookSecret string
+	// Bank1Providers are the names of the payout providers backed by Bank1, the only
+	// disbursements a Bank1 callback can settle.
+	Bank1Providers []string
+}
+
+func NewWebhookController(userBalanceUsecase domain.UserBalanceUsecase, bank1WebhookSecret string, bank1Providers []string) *WebhookController {
+	return &WebhookController{
+		UserBalanceUsecase: userBalanceUsecase,
+		Bank1WebhookSecret: bank1WebhookSecret,
+		Bank1Providers:     bank1Providers,
+	}
+}
+
+// Bank1DisbursementCallback settles a disbursement with the outcome Bank1 reports. Bank1
+// retries a callback until it is answered with 200, so a callback that may succeed later,
+// such as one arriving before the disbursement was submitted, is answered with 409. One
+// contradicting a settled disbursement sends it to manual review and is answered with 200.
+func (c *WebhookController) Bank1DisbursementCallback(gc *gin.Context) {
+	ctx := gc.Request.Context()
+
+	// the signature covers the body exactly as it was sent
+	body, err := io.ReadAll(gc.Request.Body)
+	if err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	if !external.VerifyBank1Signature(c.Bank1WebhookSecret, body, gc.GetHeader(external.Bank1SignatureHeader)) {
+		gc.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidWebhookSignature.Error(),
+		})
+		return
+	}
+
+	var callback external.Bank1DisbursementCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		gc.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": errors.ErrInvalidParameter.Error(),
+		})
+		return
+	}
+
+	disbursement, err := c.UserBalanceUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+		ReferenceID:           callback.ReferenceID,
+		PartnerDisbursementID: callback.ID,
+		Status:                bank1DisbursementStatuses[callback.Status],
+		FailureReason:         callback.Message,
+		Providers:             c.Bank1Providers,
+	})
+	if err != nil {
+		switch err {
+		case errors.ErrInvalidParameter:
+			gc.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrDisbursementNotFound:
+			gc.JSON(http.StatusNotFound, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrInvalidDisbursementStatusTransition, errors.ErrDisbursementStatusConflict, errors.ErrBalanceVersionConflict:
+			gc.JSON(http.StatusConflict, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		case errors.ErrDisbursementPartnerMismatch, errors.ErrDisbursementProviderMismatch:
+			gc.JSON(http.StatusUnprocessableEntity, gin.H{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		default:
+			gc.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": errors.ErrInternalServerError.Error(),
+			})
+		}
+		return
+	}
+
+	gc.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"message": "success",
+		"data":    disbursement,
+	})
+}
@@ -4,17 +4,82 @@ import (
 	"fmt"
 
 	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/external"
 	"github.com/krisdioles/ppr-wallet/app/provider"
 	"github.com/krisdioles/ppr-wallet/app/server/controller"
+	"github.com/krisdioles/ppr-wallet/app/server/middleware"
 	"github.com/krisdioles/ppr-wallet/config"
 )
 
-func InitHttpServer(cfg *config.ServerConfig, usecase *provider.Usecase) {
+func InitHttpServer(cfg *config.Config, usecase *provider.Usecase) {
+	router := NewRouter(cfg, usecase)
+
+	router.Run(fmt.Sprintf("localhost:%d", cfg.Server.Port))
+}
+
+// NewRouter registers every route of the API with its controller and middleware.
+func NewRouter(cfg *config.Config, usecase *provider.Usecase) *gin.Engine {
 	router := gin.Default()
 
+	idempotency := middleware.Idempotency(usecase.IdempotencyKeyUsecase, "id")
+	adminAuth := middleware.AdminAuth(cfg.Admin.APIKey)
+
 	userBalanceController := controller.NewUserBalanceController(usecase.UserBalanceUsecase)
 	router.GET("/api/user-balance/:id", userBalanceController.GetUserBalanceByID)
-	router.PATCH("/api/user-balance/:id/disburse", userBalanceController.DisburseBalance)
+	router.GET("/api/user-balance/:id/wallets", userBalanceController.GetUserWallets)
+	router.POST("/api/user-balance/:id/wallets", userBalanceController.OpenWallet)
+	router.PATCH("/api/user-balance/:id/disburse", idempotency, userBalanceController.DisburseBalance)
+	router.POST("/api/user-balance/:id/topup", idempotency, userBalanceController.TopUpBalance)
+
+	disbursementController := controller.NewDisbursementController(usecase.DisbursementUsecase, usecase.UserBalanceUsecase)
+	router.GET("/api/disbursements/:reference_id", disbursementController.GetDisbursementByReferenceID)
+	router.GET("/api/user-balance/:id/disbursements", disbursementController.GetDisbursementsByUserID)
+	router.GET("/api/user-balance/:id/disbursements/:disbursement_id", disbursementController.GetUserDisbursementByID)
+	router.POST("/api/admin/disbursements/:reference_id/settlement", adminAuth, disbursementController.SettleDisbursement)
+
+	webhookController := controller.NewWebhookController(usecase.UserBalanceUsecase, cfg.Bank1.WebhookSecret,
+		external.PayoutProviderNames(cfg, external.PayoutProviderTypeBank1))
+	router.POST("/api/webhooks/bank1/disbursements", webhookController.Bank1DisbursementCallback)
+
+	transferController := controller.NewTransferController(usecase.TransferUsecase)
+	router.POST("/api/user-balance/:id/transfer", idempotency, transferController.TransferBalance)
+
+	fxController := controller.NewFXController(usecase.FXUsecase)
+	router.POST("/api/user-balance/:id/fx-quotes", fxController.CreateQuote)
+	router.GET("/api/admin/fx-rates", adminAuth, fxController.GetRates)
+	router.PUT("/api/admin/fx-rates", adminAuth, fxController.SetRates)
+
+	conversionController := controller.NewConversionController(usecase.ConversionUsecase)
+	router.POST("/api/user-balance/:id/conversions", idempotency, conversionController.ConvertCurrency)
+
+	journalEntryController := controller.NewJournalEntryController(usecase.JournalEntryUsecase)
+	router.GET("/api/admin/journal-entries", adminAuth, journalEntryController.GetJournalEntries)
+	router.GET("/api/user-balance/:id/transactions", journalEntryController.GetUserStatement)
+
+	ledgerController := controller.NewLedgerController(usecase.LedgerUsecase)
+	router.GET("/api/admin/ledger/accounts/:account_id/balance", adminAuth, ledgerController.GetAccountBalance)
+	router.GET("/api/admin/ledger/balance-verification", adminAuth, ledgerController.VerifyWalletBalances)
+	router.GET("/api/admin/ledger/balance-verifications", adminAuth, ledgerController.GetBalanceVerifications)
+	router.GET("/api/admin/ledger/hash-chain-verification", adminAuth, ledgerController.VerifyHashChain)
+
+	reversalController := controller.NewReversalController(usecase.ReversalUsecase)
+	router.POST("/api/admin/ledger/journals/:folio/reversal", adminAuth, middleware.Idempotency(usecase.IdempotencyKeyUsecase, "folio"), reversalController.ReverseJournal)
+
+	reportController := controller.NewReportController(usecase.ReportUsecase)
+	router.GET("/api/admin/reports/trial-balance", adminAuth, reportController.GetTrialBalance)
+	router.GET("/api/admin/reports/balance-sheet", adminAuth, reportController.GetBalanceSheet)
+	router.GET("/api/admin/reports/income-statement", adminAuth, reportController.GetIncomeStatement)
+
+	accountingPeriodController := controller.NewAccountingPeriodController(usecase.AccountingPeriodUsecase)
+	router.GET("/api/admin/accounting-periods", adminAuth, accountingPeriodController.GetPeriods)
+	router.POST("/api/admin/accounting-periods", adminAuth, accountingPeriodController.OpenPeriod)
+	router.GET("/api/admin/accounting-periods/:period", adminAuth, accountingPeriodController.GetPeriod)
+	router.POST("/api/admin/accounting-periods/:period/close", adminAuth, accountingPeriodController.ClosePeriod)
+
+	reconciliationController := controller.NewReconciliationController(usecase.ReconciliationUsecase)
+	router.GET("/api/admin/reconciliations", adminAuth, reconciliationController.GetReconciliations)
+	router.POST("/api/admin/reconciliations", adminAuth, reconciliationController.ReconcileSettlement)
+	router.GET("/api/admin/reconciliations/:id", adminAuth, reconciliationController.GetReconciliation)
 
-	router.Run(fmt.Sprintf("localhost:%d", cfg.Port))
+	return router
 }
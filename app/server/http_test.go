@@ -0,0 +1,116 @@
+package server_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/mocks"
+	"github.com/krisdioles/ppr-wallet/app/provider"
+	"github.com/krisdioles/ppr-wallet/app/server"
+	"github.com/krisdioles/ppr-wallet/app/server/middleware"
+	"github.com/krisdioles/ppr-wallet/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewRouter_JournalEntries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	journalEntryUsecase := new(mocks.JournalEntryUsecase)
+	journalEntryUsecase.On("GetJournalEntries", mock.Anything, mock.Anything).Return(&domain.JournalEntryPage{}, nil)
+	router := server.NewRouter(&config.Config{Admin: config.AdminConfig{APIKey: "admin-secret"}}, &provider.Usecase{
+		JournalEntryUsecase: journalEntryUsecase,
+	})
+
+	testCases := []struct {
+		name     string
+		path     string
+		adminKey string
+		expected int
+	}{
+		{"WithoutAdminKey", "/api/admin/journal-entries", "", http.StatusUnauthorized},
+		{"WrongAdminKey", "/api/admin/journal-entries", "guess", http.StatusUnauthorized},
+		{"AdminKey", "/api/admin/journal-entries", "admin-secret", http.StatusOK},
+		{"NotPublic", "/api/journal-entries", "admin-secret", http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.adminKey != "" {
+				req.Header.Set(middleware.AdminKeyHeader, tc.adminKey)
+			}
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expected, rec.Code)
+		})
+	}
+	journalEntryUsecase.AssertNumberOfCalls(t, "GetJournalEntries", 1)
+}
+
+func TestNewRouter_ReversalIdempotencyScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// the same key on two journals reverses both of them
+	idempotencyKeyUsecase := new(mocks.IdempotencyKeyUsecase)
+	reversalUsecase := new(mocks.ReversalUsecase)
+	for _, folio := range []string{"JRN-1", "JRN-2"} {
+		idempotencyKeyUsecase.On("Begin", mock.Anything, folio, "key-1", mock.Anything).Return(&domain.IdempotencyKey{Scope: folio, Key: "key-1"}, nil).Once()
+		idempotencyKeyUsecase.On("Complete", mock.Anything, folio, "key-1", http.StatusOK, mock.Anything).Return(nil).Once()
+		reversalUsecase.On("ReverseJournal", mock.Anything, folio, mock.Anything).Return(&domain.Reversal{}, nil).Once()
+	}
+	router := server.NewRouter(&config.Config{Admin: config.AdminConfig{APIKey: "admin-secret"}}, &provider.Usecase{
+		IdempotencyKeyUsecase: idempotencyKeyUsecase,
+		ReversalUsecase:       reversalUsecase,
+	})
+
+	for _, folio := range []string{"JRN-1", "JRN-2"} {
+		req := httptest.NewRequest(http.MethodPost, "/api/admin/ledger/journals/"+folio+"/reversal", strings.NewReader(`{"reason":"posted twice"}`))
+		req.Header.Set(middleware.AdminKeyHeader, "admin-secret")
+		req.Header.Set(middleware.IdempotencyKeyHeader, "key-1")
+		rec := httptest.NewRecorder()
+
+		router.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	idempotencyKeyUsecase.AssertExpectations(t)
+	reversalUsecase.AssertExpectations(t)
+}
+
+func TestNewRouter_DisburseBalance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name         string
+		disbursement *domain.Disbursement
+		expected     int
+	}{
+		{"Submitted", &domain.Disbursement{Status: domain.DisbursementStatusSubmitted, PartnerDisbursementID: "bank-1"}, http.StatusOK},
+		{"Completed", &domain.Disbursement{Status: domain.DisbursementStatusCompleted, PartnerDisbursementID: "bank-1"}, http.StatusOK},
+		// the bank never answered, so the payout must not look like it went through
+		{"OutcomeUnknown", &domain.Disbursement{Status: domain.DisbursementStatusSubmitted}, http.StatusAccepted},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			userBalanceUsecase := new(mocks.UserBalanceUsecase)
+			userBalanceUsecase.On("DisburseBalance", mock.Anything, int64(1), mock.Anything).Return(tc.disbursement, nil)
+			router := server.NewRouter(&config.Config{}, &provider.Usecase{UserBalanceUsecase: userBalanceUsecase})
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/user-balance/1/disburse", strings.NewReader(`{"amount":100}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expected, rec.Code)
+		})
+	}
+}
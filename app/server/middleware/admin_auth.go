@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+const AdminKeyHeader = "X-Admin-Key"
+
+// AdminAuth lets through only requests whose X-Admin-Key header carries apiKey. Without a
+// configured apiKey every request is rejected, so the admin endpoints are never left open.
+func AdminAuth(apiKey string) gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		key := gc.GetHeader(AdminKeyHeader)
+		if apiKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) != 1 {
+			gc.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"status":  "error",
+				"message": errors.ErrUnauthorized.Error(),
+			})
+			return
+		}
+
+		gc.Next()
+	}
+}
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	replayedResponseMediaType = "application/json; charset=utf-8"
+)
+
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency deduplicates requests carrying an Idempotency-Key header. The first
+// request stores its response; replays with the same payload get the stored response
+// back without reaching the handler, and replays with another payload are rejected.
+// Keys are scoped by the scopeParam path parameter, the user of a wallet route or the journal
+// of a reversal, so users cannot replay or block each other's requests. Only final answers
+// are stored: on a 5xx response or a panic the key is released and a retry runs the request
+// again.
+func Idempotency(idempotencyKeyUsecase domain.IdempotencyKeyUsecase, scopeParam string) gin.HandlerFunc {
+	return func(gc *gin.Context) {
+		key := gc.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			gc.Next()
+			return
+		}
+		scope := gc.Param(scopeParam)
+
+		if len(key) > maxIdempotencyKeyLength {
+			gc.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": errors.ErrInvalidParameter.Error(),
+			})
+			return
+		}
+
+		body, err := io.ReadAll(gc.Request.Body)
+		if err != nil {
+			gc.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": errors.ErrInvalidParameter.Error(),
+			})
+			return
+		}
+		gc.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := gc.Request.Context()
+
+		idempotencyKey, err := idempotencyKeyUsecase.Begin(ctx, scope, key, requestFingerprint(gc.Request, body))
+		if err != nil {
+			switch err {
+			case errors.ErrIdempotencyKeyConflict, errors.ErrIdempotencyKeyInProgress:
+				gc.AbortWithStatusJSON(http.StatusConflict, gin.H{
+					"status":  "error",
+					"message": err.Error(),
+				})
+			default:
+				gc.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"status":  "error",
+					"message": errors.ErrInternalServerError.Error(),
+				})
+			}
+			return
+		}
+
+		if idempotencyKey.IsCompleted() {
+			gc.Header(IdempotentReplayedHeader, "true")
+			gc.Data(idempotencyKey.ResponseStatus, replayedResponseMediaType, []byte(idempotencyKey.ResponseBody))
+			gc.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{
+			ResponseWriter: gc.Writer,
+			body:           &bytes.Buffer{},
+		}
+		gc.Writer = recorder
+
+		// the client may be gone by now, the key must still be settled for its retry
+		ctx = context.WithoutCancel(ctx)
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				if err := idempotencyKeyUsecase.Release(ctx, scope, key); err != nil {
+					log.Println("[Idempotency] Release err:", err)
+				}
+				panic(recovered)
+			}
+		}()
+
+		gc.Next()
+
+		if !isFinalStatus(recorder.Status()) {
+			if err = idempotencyKeyUsecase.Release(ctx, scope, key); err != nil {
+				log.Println("[Idempotency] Release err:", err)
+			}
+			return
+		}
+
+		if err = idempotencyKeyUsecase.Complete(ctx, scope, key, recorder.Status(), recorder.body.String()); err != nil {
+			log.Println("[Idempotency] Complete err:", err)
+		}
+	}
+}
+
+// isFinalStatus reports whether a response with status answers the request for good: a
+// success or a client error. A server error may not happen again and is not replayed.
+func isFinalStatus(status int) bool {
+	return status >= http.StatusOK && status < http.StatusMultipleChoices ||
+		status >= http.StatusBadRequest && status < http.StatusInternalServerError
+}
+
+func requestFingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(req.URL.Path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type AccountingPeriodUsecase struct {
+	transactionManager         domain.TransactionManager
+	journalEntryRepository     domain.JournalEntryRepository
+	accountingPeriodRepository domain.AccountingPeriodRepository
+}
+
+func NewAccountingPeriodUsecase(transactionManager domain.TransactionManager, journalEntryRepository domain.JournalEntryRepository, accountingPeriodRepository domain.AccountingPeriodRepository) domain.AccountingPeriodUsecase {
+	return &AccountingPeriodUsecase{
+		transactionManager:         transactionManager,
+		journalEntryRepository:     journalEntryRepository,
+		accountingPeriodRepository: accountingPeriodRepository,
+	}
+}
+
+// OpenPeriod registers the month named in request as an open period. A month before the end of
+// a closed period is locked already and cannot be opened anymore.
+func (u *AccountingPeriodUsecase) OpenPeriod(ctx context.Context, request *domain.OpenAccountingPeriodRequest) (*domain.AccountingPeriod, error) {
+	if request == nil {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	accountingPeriod, err := domain.NewAccountingPeriod(request.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		closed, err := u.accountingPeriodRepository.IsClosed(txCtx, accountingPeriod.StartDate)
+		if err != nil {
+			log.Println("[OpenPeriod] IsClosed err:", err)
+			return err
+		}
+		if closed {
+			return errors.ErrAccountingPeriodClosed
+		}
+
+		if _, err := u.accountingPeriodRepository.Create(txCtx, accountingPeriod); err != nil {
+			log.Println("[OpenPeriod] Create accounting period err:", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accountingPeriod, nil
+}
+
+func (u *AccountingPeriodUsecase) GetPeriods(ctx context.Context) ([]*domain.AccountingPeriod, error) {
+	accountingPeriods, err := u.accountingPeriodRepository.GetAll(ctx)
+	if err != nil {
+		log.Println("[GetPeriods] GetAll err:", err)
+		return nil, err
+	}
+
+	return accountingPeriods, nil
+}
+
+// GetPeriod returns a period together with its closing balances once it is closed.
+func (u *AccountingPeriodUsecase) GetPeriod(ctx context.Context, period string) (*domain.AccountingPeriod, error) {
+	accountingPeriod, err := u.accountingPeriodRepository.GetByPeriod(ctx, period)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrAccountingPeriodNotFound
+		}
+
+		log.Println("[GetPeriod] GetByPeriod err:", err)
+		return nil, err
+	}
+
+	if accountingPeriod.Status == domain.AccountingPeriodStatusClosed {
+		accountingPeriod.ClosingBalances, err = u.accountingPeriodRepository.GetClosingBalances(ctx, accountingPeriod.ID)
+		if err != nil {
+			log.Println("[GetPeriod] GetClosingBalances err:", err)
+			return nil, err
+		}
+	}
+
+	return accountingPeriod, nil
+}
+
+// ClosePeriod locks a period that has ended and stores the balance of every account at its
+// end. Periods are closed in order, so every earlier period has to be closed first.
+func (u *AccountingPeriodUsecase) ClosePeriod(ctx context.Context, period string) (*domain.AccountingPeriod, error) {
+	var closedPeriod *domain.AccountingPeriod
+	err := u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		accountingPeriod, err := u.accountingPeriodRepository.GetByPeriod(txCtx, period)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return errors.ErrAccountingPeriodNotFound
+			}
+
+			log.Println("[ClosePeriod] GetByPeriod err:", err)
+			return err
+		}
+
+		hasOpenBefore, err := u.accountingPeriodRepository.HasOpenBefore(txCtx, accountingPeriod.StartDate)
+		if err != nil {
+			log.Println("[ClosePeriod] HasOpenBefore err:", err)
+			return err
+		}
+		if hasOpenBefore {
+			return errors.ErrEarlierAccountingPeriodOpen
+		}
+
+		closingBalances, err := accountBalancesBefore(txCtx, u.journalEntryRepository, u.accountingPeriodRepository, accountingPeriod.EndDate)
+		if err != nil {
+			log.Println("[ClosePeriod] get account balances err:", err)
+			return err
+		}
+
+		if err := accountingPeriod.Close(closingBalances, time.Now().UTC()); err != nil {
+			return err
+		}
+
+		if err := u.accountingPeriodRepository.Close(txCtx, accountingPeriod); err != nil {
+			log.Println("[ClosePeriod] Close accounting period err:", err)
+			return err
+		}
+		closedPeriod = accountingPeriod
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return closedPeriod, nil
+}
+
+// accountBalancesBefore returns the balance of every account over the postings valued before
+// end. It starts from the closing balances of the last period closed by then, so only the
+// postings valued after that period are summed.
+func accountBalancesBefore(ctx context.Context, journalEntryRepository domain.JournalEntryRepository, accountingPeriodRepository domain.AccountingPeriodRepository, end time.Time) ([]*domain.LedgerAccountBalance, error) {
+	var from time.Time
+	var closingBalances []*domain.LedgerAccountBalance
+
+	accountingPeriod, err := accountingPeriodRepository.GetLatestClosed(ctx, end)
+	switch {
+	case err == nil:
+		closingBalances, err = accountingPeriodRepository.GetClosingBalances(ctx, accountingPeriod.ID)
+		if err != nil {
+			return nil, err
+		}
+		from = accountingPeriod.EndDate
+	case err != sql.ErrNoRows:
+		return nil, err
+	}
+
+	movements, err := journalEntryRepository.GetAccountBalancesForPeriod(ctx, from, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.AddAccountBalances(closingBalances, movements), nil
+}
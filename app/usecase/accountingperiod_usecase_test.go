@@ -0,0 +1,161 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/domain/mocks"
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database/migration"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAccountingPeriodUsecase_ClosePeriod(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+
+	fee := func(folio string, amount int64, valueDate time.Time) *domain.JournalTransaction {
+		journal := domain.NewJournalTransaction(folio, "Fee").
+			Debit(domain.WalletAccountID(1, domain.DefaultCurrency), amount).
+			Credit(domain.FeeIncomeAccountID, amount)
+		journal.ValueDate = valueDate
+
+		return journal
+	}
+	migration.InsertJournalTransactionRecord(db, fee("FEE-1", 100, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)))
+	migration.InsertJournalTransactionRecord(db, fee("FEE-2", 50, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)))
+
+	journalEntryRepo := repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db))
+	accountingPeriodRepo := repository.NewAccountingPeriodRepository(db)
+	accountingPeriodUsecase := usecase.NewAccountingPeriodUsecase(repository.NewTransactionManager(db), journalEntryRepo, accountingPeriodRepo)
+	reportUsecase := usecase.NewReportUsecase(journalEntryRepo, accountingPeriodRepo)
+
+	trialBalanceBeforeClose, err := reportUsecase.GetTrialBalance(ctx, time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC), domain.DefaultCurrency)
+	assert.NoError(t, err)
+
+	_, err = accountingPeriodUsecase.OpenPeriod(ctx, &domain.OpenAccountingPeriodRequest{Period: "2026-03"})
+	assert.NoError(t, err)
+	_, err = accountingPeriodUsecase.OpenPeriod(ctx, &domain.OpenAccountingPeriodRequest{Period: "2026-03"})
+	assert.ErrorIs(t, err, domErr.ErrAccountingPeriodAlreadyExists)
+
+	closedPeriod, err := accountingPeriodUsecase.ClosePeriod(ctx, "2026-03")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AccountingPeriodStatusClosed, closedPeriod.Status)
+	assert.NotNil(t, closedPeriod.ClosedAt)
+
+	_, err = accountingPeriodUsecase.ClosePeriod(ctx, "2026-03")
+	assert.ErrorIs(t, err, domErr.ErrAccountingPeriodClosed)
+
+	// the closing balances only hold the postings valued up to the end of March
+	accountingPeriod, err := accountingPeriodUsecase.GetPeriod(ctx, "2026-03")
+	assert.NoError(t, err)
+	closingBalances := map[string]int64{}
+	for _, accountBalance := range accountingPeriod.ClosingBalances {
+		closingBalances[accountBalance.AccountID] = accountBalance.Balance
+	}
+	assert.Equal(t, int64(100), closingBalances[domain.FeeIncomeAccountID])
+	assert.Equal(t, int64(-100), closingBalances[domain.WalletAccountID(1, domain.DefaultCurrency)])
+
+	// nothing can be posted into the closed period or before it anymore
+	for _, valueDate := range []time.Time{time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)} {
+		err = journalEntryRepo.CreateTransaction(ctx, fee("FEE-3", 10, valueDate))
+		assert.ErrorIs(t, err, domErr.ErrPostingPeriodClosed)
+	}
+	assert.NoError(t, journalEntryRepo.CreateTransaction(ctx, fee("FEE-4", 10, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))))
+
+	_, err = accountingPeriodUsecase.OpenPeriod(ctx, &domain.OpenAccountingPeriodRequest{Period: "2026-02"})
+	assert.ErrorIs(t, err, domErr.ErrAccountingPeriodClosed)
+
+	// reports start from the stored closing balances instead of summing March again
+	trialBalance, err := reportUsecase.GetTrialBalance(ctx, time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC), domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.True(t, trialBalance.Balanced)
+	assert.Equal(t, trialBalanceBeforeClose.TotalDebitBalance+10, trialBalance.TotalDebitBalance)
+
+	db.MustExec(`UPDATE accounting_period_closing_balances SET total_credit = total_credit + 1 WHERE account_id = ?`, domain.FeeIncomeAccountID)
+	trialBalance, err = reportUsecase.GetTrialBalance(ctx, time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC), domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.False(t, trialBalance.Balanced)
+}
+
+func TestAccountingPeriodUsecase_ClosePeriod_Order(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	accountingPeriodUsecase := usecase.NewAccountingPeriodUsecase(repository.NewTransactionManager(db), repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)), repository.NewAccountingPeriodRepository(db))
+
+	currentPeriod := time.Now().UTC().Format(domain.AccountingPeriodFormat)
+	for _, period := range []string{"2026-04", "2026-05", currentPeriod} {
+		_, err := accountingPeriodUsecase.OpenPeriod(ctx, &domain.OpenAccountingPeriodRequest{Period: period})
+		assert.NoError(t, err)
+	}
+
+	_, err := accountingPeriodUsecase.ClosePeriod(ctx, "2026-05")
+	assert.ErrorIs(t, err, domErr.ErrEarlierAccountingPeriodOpen)
+
+	_, err = accountingPeriodUsecase.ClosePeriod(ctx, "2026-04")
+	assert.NoError(t, err)
+	_, err = accountingPeriodUsecase.ClosePeriod(ctx, "2026-05")
+	assert.NoError(t, err)
+
+	_, err = accountingPeriodUsecase.ClosePeriod(ctx, currentPeriod)
+	assert.ErrorIs(t, err, domErr.ErrAccountingPeriodNotEnded)
+
+	accountingPeriods, err := accountingPeriodUsecase.GetPeriods(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, accountingPeriods, 3) {
+		assert.Equal(t, domain.AccountingPeriodStatusClosed, accountingPeriods[1].Status)
+		assert.Equal(t, domain.AccountingPeriodStatusOpen, accountingPeriods[2].Status)
+	}
+}
+
+func TestAccountingPeriodUsecase_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	newUsecase := func() (domain.AccountingPeriodUsecase, *mocks.AccountingPeriodRepository) {
+		transactionManager := new(mocks.TransactionManager)
+		transactionManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Maybe()
+		accountingPeriodRepo := new(mocks.AccountingPeriodRepository)
+
+		return usecase.NewAccountingPeriodUsecase(transactionManager, new(mocks.JournalEntryRepository), accountingPeriodRepo), accountingPeriodRepo
+	}
+
+	t.Run("InvalidPeriod", func(t *testing.T) {
+		usecase, accountingPeriodRepo := newUsecase()
+
+		_, err := usecase.OpenPeriod(ctx, &domain.OpenAccountingPeriodRequest{Period: "March 2026"})
+		assert.ErrorIs(t, err, domErr.ErrInvalidParameter)
+
+		accountingPeriodRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		usecase, accountingPeriodRepo := newUsecase()
+		accountingPeriodRepo.On("GetByPeriod", ctx, "2026-03").Return(nil, sql.ErrNoRows)
+
+		_, err := usecase.ClosePeriod(ctx, "2026-03")
+		assert.ErrorIs(t, err, domErr.ErrAccountingPeriodNotFound)
+
+		_, err = usecase.GetPeriod(ctx, "2026-03")
+		assert.ErrorIs(t, err, domErr.ErrAccountingPeriodNotFound)
+	})
+
+	t.Run("QueryError", func(t *testing.T) {
+		usecase, accountingPeriodRepo := newUsecase()
+		accountingPeriodRepo.On("GetAll", ctx).Return(nil, errors.New("query error"))
+
+		_, err := usecase.GetPeriods(ctx)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+const (
+	maxBalanceUpdateAttempts = 5
+	balanceUpdateRetryDelay  = 5 * time.Millisecond
+)
+
+// updateBalance applies mutate to the latest state of the wallet of a user in currency and saves it. The save only
+// succeeds when nobody changed the wallet since it was read, otherwise it fails with
+// ErrBalanceVersionConflict instead of overwriting the other change.
+func updateBalance(ctx context.Context, userBalanceRepository domain.UserBalanceRepository, userID int64, currency string, mutate func(*domain.UserBalance) error) (*domain.UserBalance, error) {
+	userBalance, err := userBalanceRepository.GetByUserIDAndCurrency(ctx, userID, currency)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	if err := mutate(userBalance); err != nil {
+		return nil, err
+	}
+
+	if err := userBalanceRepository.UpdateBalance(ctx, userBalance); err != nil {
+		return nil, err
+	}
+
+	return userBalance, nil
+}
+
+// retryOnBalanceConflict runs fn again while it loses the race on a wallet version, waiting a
+// little longer with some jitter before every attempt. fn must be safe to run from the start
+// again, which is the case when it is a whole transaction.
+func retryOnBalanceConflict(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxBalanceUpdateAttempts; attempt++ {
+		err = fn()
+		if err != errors.ErrBalanceVersionConflict || attempt == maxBalanceUpdateAttempts {
+			break
+		}
+
+		delay := time.Duration(attempt)*balanceUpdateRetryDelay + time.Duration(rand.Int63n(int64(balanceUpdateRetryDelay)))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type ConversionUsecase struct {
+	transactionManager     domain.TransactionManager
+	userBalanceRepository  domain.UserBalanceRepository
+	journalEntryRepository domain.JournalEntryRepository
+	fxQuoteRepository      domain.FXQuoteRepository
+	conversionRepository   domain.ConversionRepository
+	referenceIDGenerator   domain.ReferenceIDGenerator
+}
+
+func NewConversionUsecase(transactionManager domain.TransactionManager, userBalanceRepository domain.UserBalanceRepository, journalEntryRepository domain.JournalEntryRepository, fxQuoteRepository domain.FXQuoteRepository, conversionRepository domain.ConversionRepository, referenceIDGenerator domain.ReferenceIDGenerator) domain.ConversionUsecase {
+	return &ConversionUsecase{
+		transactionManager:     transactionManager,
+		userBalanceRepository:  userBalanceRepository,
+		journalEntryRepository: journalEntryRepository,
+		fxQuoteRepository:      fxQuoteRepository,
+		conversionRepository:   conversionRepository,
+		referenceIDGenerator:   referenceIDGenerator,
+	}
+}
+
+// ConvertCurrency converts between two wallets of the user at the rate of a quote that has not
+// expired and was not used before. Each currency balances on its own in the journal, through
+// the FX position account of that currency:
+//
+//	debit  wallet      from amount
+//	credit fx-position from amount
+//	debit  fx-position to amount
+//	credit wallet      to amount
+func (u *ConversionUsecase) ConvertCurrency(ctx context.Context, userID int64, request *domain.ConvertCurrencyRequest) (*domain.Conversion, error) {
+	if request == nil || request.QuoteID == "" {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	quote, err := u.fxQuoteRepository.GetByReferenceID(ctx, request.QuoteID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrFXQuoteNotFound
+		}
+
+		return nil, err
+	}
+
+	// another user's quote is reported as missing rather than forbidden
+	if quote.UserID != userID {
+		return nil, errors.ErrFXQuoteNotFound
+	}
+	if quote.IsExpired(time.Now().UTC()) {
+		return nil, errors.ErrFXQuoteExpired
+	}
+	from, to := quote.FromMoney(), quote.ToMoney()
+
+	// both wallets belong to the same user, they are written in the order of their currency
+	mutations := map[string]func(*domain.UserBalance) error{
+		from.Currency: func(userBalance *domain.UserBalance) error {
+			return userBalance.Debit(from)
+		},
+		to.Currency: func(userBalance *domain.UserBalance) error {
+			return userBalance.Credit(to)
+		},
+	}
+	lockOrder := []string{from.Currency, to.Currency}
+	if lockOrder[1] < lockOrder[0] {
+		lockOrder[0], lockOrder[1] = lockOrder[1], lockOrder[0]
+	}
+
+	var conversion *domain.Conversion
+	err = retryOnBalanceConflict(ctx, func() error {
+		return u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+			createdConversion, err := u.conversionRepository.Create(txCtx, &domain.Conversion{
+				ReferenceID:  u.referenceIDGenerator.Generate(),
+				QuoteID:      quote.ReferenceID,
+				UserID:       userID,
+				FromCurrency: from.Currency,
+				FromAmount:   from.Amount,
+				ToCurrency:   to.Currency,
+				ToAmount:     to.Amount,
+				Rate:         quote.Rate,
+			})
+			if err != nil {
+				log.Println("[ConvertCurrency] Create conversion record err:", err)
+				return err
+			}
+
+			for _, currency := range lockOrder {
+				if _, err := updateBalance(txCtx, u.userBalanceRepository, userID, currency, mutations[currency]); err != nil {
+					log.Println("[ConvertCurrency] update balance err:", err)
+					return err
+				}
+			}
+
+			journal := domain.NewJournalTransaction(createdConversion.ReferenceID, "Currency conversion").
+				InCurrency(from.Currency).
+				DebitMoney(domain.WalletAccountID(userID, from.Currency), from).
+				CreditMoney(domain.CurrencyAccountID(domain.FXPositionAccountID, from.Currency), from).
+				DebitMoney(domain.CurrencyAccountID(domain.FXPositionAccountID, to.Currency), to).
+				CreditMoney(domain.WalletAccountID(userID, to.Currency), to).
+				WithMetadata("user_id", strconv.FormatInt(userID, 10)).
+				WithMetadata("quote_id", quote.ReferenceID).
+				WithMetadata("rate", quote.Rate)
+			if err := u.journalEntryRepository.CreateTransaction(txCtx, journal); err != nil {
+				log.Println("[ConvertCurrency] CreateTransaction journal err:", err)
+				return err
+			}
+			conversion = createdConversion
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conversion, nil
+}
@@ -0,0 +1,143 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database/migration"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/krisdioles/ppr-wallet/pkg/refid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newConversionUsecase(db *sqlx.DB) domain.ConversionUsecase {
+	return usecase.NewConversionUsecase(
+		repository.NewTransactionManager(db),
+		repository.NewUserBalanceRepository(db),
+		repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)),
+		repository.NewFXQuoteRepository(db),
+		repository.NewConversionRepository(db),
+		refid.NewGenerator("WLT"),
+	)
+}
+
+// newFXQuote prices 50.00 USD into IDR for the first user at 16000.
+func newFXQuote(t *testing.T, db *sqlx.DB, quoteTTL time.Duration) *domain.FXQuote {
+	fxUsecase := newFXUsecase(db, quoteTTL)
+	_, err := fxUsecase.SetRates(context.Background(), &domain.SetFXRatesRequest{Rates: []*domain.FXRateInput{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "16000"},
+	}})
+	assert.NoError(t, err)
+
+	quote, err := fxUsecase.CreateQuote(context.Background(), 1, &domain.CreateFXQuoteRequest{FromCurrency: "USD", ToCurrency: "IDR", Amount: 50})
+	assert.NoError(t, err)
+
+	return quote
+}
+
+func TestConversionUsecase_ConvertCurrency(t *testing.T) {
+	ctx := context.Background()
+
+	db := newFXTestDB(t)
+	quote := newFXQuote(t, db, time.Minute)
+
+	conversion, err := newConversionUsecase(db).ConvertCurrency(ctx, 1, &domain.ConvertCurrencyRequest{QuoteID: quote.ReferenceID})
+	assert.NoError(t, err)
+	assert.Equal(t, quote.ReferenceID, conversion.QuoteID)
+	assert.Equal(t, int64(800000), conversion.ToAmount)
+
+	userBalanceRepo := repository.NewUserBalanceRepository(db)
+	usd, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, "USD")
+	assert.NoError(t, err)
+	idr, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(950), usd.Balance)
+	assert.Equal(t, int64(1800000), idr.Balance)
+
+	// each currency balances on its own through the FX position accounts
+	journalEntryRepo := repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db))
+	entries, err := journalEntryRepo.GetEntries(ctx, &domain.JournalEntryFilter{TransactionGroupID: conversion.ReferenceID})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	usdPosition, err := journalEntryRepo.GetAccountBalance(ctx, "fx-position:USD")
+	assert.NoError(t, err)
+	idrPosition, err := journalEntryRepo.GetAccountBalance(ctx, domain.FXPositionAccountID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50), usdPosition.TotalCredit)
+	assert.Equal(t, int64(800000), idrPosition.TotalDebit)
+
+	assertLedgerMatchesWallets(t, db)
+
+	t.Run("QuoteAlreadyUsed", func(t *testing.T) {
+		_, err := newConversionUsecase(db).ConvertCurrency(ctx, 1, &domain.ConvertCurrencyRequest{QuoteID: quote.ReferenceID})
+		assert.ErrorIs(t, err, domErr.ErrFXQuoteAlreadyUsed)
+
+		usd, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, "USD")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(950), usd.Balance)
+	})
+
+	t.Run("Reversal", func(t *testing.T) {
+		_, err := newReversalUsecase(db).ReverseJournal(ctx, conversion.ReferenceID, &domain.ReverseJournalRequest{Reason: "converted by mistake"})
+		assert.NoError(t, err)
+
+		usd, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, "USD")
+		assert.NoError(t, err)
+		idr, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, domain.DefaultCurrency)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1000), usd.Balance)
+		assert.Equal(t, int64(1000000), idr.Balance)
+
+		assertLedgerMatchesWallets(t, db)
+	})
+}
+
+func TestConversionUsecase_ConvertCurrency_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	db := newFXTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{UserID: 2, Username: "brandy345"})
+	conversionUsecase := newConversionUsecase(db)
+
+	t.Run("QuoteNotFound", func(t *testing.T) {
+		_, err := conversionUsecase.ConvertCurrency(ctx, 1, &domain.ConvertCurrencyRequest{QuoteID: "WLT-UNKNOWN"})
+		assert.ErrorIs(t, err, domErr.ErrFXQuoteNotFound)
+	})
+
+	t.Run("QuoteOfAnotherUser", func(t *testing.T) {
+		quote := newFXQuote(t, db, time.Minute)
+
+		_, err := conversionUsecase.ConvertCurrency(ctx, 2, &domain.ConvertCurrencyRequest{QuoteID: quote.ReferenceID})
+		assert.ErrorIs(t, err, domErr.ErrFXQuoteNotFound)
+	})
+
+	t.Run("QuoteExpired", func(t *testing.T) {
+		quote := newFXQuote(t, db, time.Nanosecond)
+		time.Sleep(time.Millisecond)
+
+		_, err := conversionUsecase.ConvertCurrency(ctx, 1, &domain.ConvertCurrencyRequest{QuoteID: quote.ReferenceID})
+		assert.ErrorIs(t, err, domErr.ErrFXQuoteExpired)
+	})
+
+	t.Run("InsufficientBalance", func(t *testing.T) {
+		fxUsecase := newFXUsecase(db, time.Minute)
+		quote, err := fxUsecase.CreateQuote(ctx, 1, &domain.CreateFXQuoteRequest{FromCurrency: "USD", ToCurrency: "IDR", Amount: 5000})
+		assert.NoError(t, err)
+
+		_, err = conversionUsecase.ConvertCurrency(ctx, 1, &domain.ConvertCurrencyRequest{QuoteID: quote.ReferenceID})
+		assert.ErrorIs(t, err, domErr.ErrInsufficientBalance)
+
+		// the failed attempt does not use up the quote
+		conversions := 0
+		assert.NoError(t, db.Get(&conversions, `SELECT COUNT(*) FROM conversions`))
+		assert.Equal(t, 0, conversions)
+	})
+
+	assertLedgerMatchesWallets(t, db)
+}
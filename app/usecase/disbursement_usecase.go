@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type DisbursementUsecase struct {
+	disbursementRepository domain.DisbursementRepository
+	userBalanceRepository  domain.UserBalanceRepository
+}
+
+func NewDisbursementUsecase(disbursementRepository domain.DisbursementRepository, userBalanceRepository domain.UserBalanceRepository) domain.DisbursementUsecase {
+	return &DisbursementUsecase{
+		disbursementRepository: disbursementRepository,
+		userBalanceRepository:  userBalanceRepository,
+	}
+}
+
+func (u *DisbursementUsecase) GetDisbursementByReferenceID(ctx context.Context, referenceID string) (*domain.Disbursement, error) {
+	disbursement, err := u.disbursementRepository.GetByReferenceID(ctx, referenceID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return disbursement, errors.ErrDisbursementNotFound
+		}
+
+		return disbursement, err
+	}
+
+	return disbursement, nil
+}
+
+func (u *DisbursementUsecase) GetDisbursementsByUserID(ctx context.Context, userID int64) ([]*domain.Disbursement, error) {
+	userBalances, err := u.userBalanceRepository.GetAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userBalances) == 0 {
+		return nil, errors.ErrUserNotFound
+	}
+
+	return u.disbursementRepository.GetByUserID(ctx, userID)
+}
+
+func (u *DisbursementUsecase) GetUserDisbursementByID(ctx context.Context, userID, id int64) (*domain.Disbursement, error) {
+	disbursement, err := u.disbursementRepository.GetByID(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrDisbursementNotFound
+		}
+
+		return nil, err
+	}
+
+	// another user's disbursement is reported as missing rather than forbidden
+	if disbursement.UserID != userID {
+		return nil, errors.ErrDisbursementNotFound
+	}
+
+	return disbursement, nil
+}
@@ -0,0 +1,151 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/domain/mocks"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisbursementUsecase_GetDisbursementByReferenceID(t *testing.T) {
+	ctx := context.Background()
+	referenceID := "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7"
+	expectedDisbursement := &domain.Disbursement{
+		ID:          7,
+		ReferenceID: referenceID,
+		UserID:      1,
+		Amount:      500,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockDisbursementRepo := new(mocks.DisbursementRepository)
+		usecase := usecase.NewDisbursementUsecase(mockDisbursementRepo, nil)
+
+		mockDisbursementRepo.On("GetByReferenceID", ctx, referenceID).Return(expectedDisbursement, nil)
+
+		result, err := usecase.GetDisbursementByReferenceID(ctx, referenceID)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedDisbursement, result)
+
+		mockDisbursementRepo.AssertExpectations(t)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockDisbursementRepo := new(mocks.DisbursementRepository)
+		usecase := usecase.NewDisbursementUsecase(mockDisbursementRepo, nil)
+
+		mockDisbursementRepo.On("GetByReferenceID", ctx, referenceID).Return(nil, sql.ErrNoRows)
+
+		_, err := usecase.GetDisbursementByReferenceID(ctx, referenceID)
+		assert.ErrorIs(t, err, domErr.ErrDisbursementNotFound)
+
+		mockDisbursementRepo.AssertExpectations(t)
+	})
+
+	t.Run("OtherError", func(t *testing.T) {
+		mockDisbursementRepo := new(mocks.DisbursementRepository)
+		usecase := usecase.NewDisbursementUsecase(mockDisbursementRepo, nil)
+
+		mockDisbursementRepo.On("GetByReferenceID", ctx, referenceID).Return(nil, errors.New("some error"))
+
+		_, err := usecase.GetDisbursementByReferenceID(ctx, referenceID)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, domErr.ErrDisbursementNotFound)
+
+		mockDisbursementRepo.AssertExpectations(t)
+	})
+}
+
+func TestDisbursementUsecase_GetDisbursementsByUserID(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(1)
+	expectedDisbursements := []*domain.Disbursement{
+		{ID: 8, UserID: userID, Amount: 300, Status: domain.DisbursementStatusPending},
+		{ID: 7, UserID: userID, Amount: 500, Status: domain.DisbursementStatusCompleted},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockDisbursementRepo := new(mocks.DisbursementRepository)
+		mockUserBalanceRepo := new(mocks.UserBalanceRepository)
+		usecase := usecase.NewDisbursementUsecase(mockDisbursementRepo, mockUserBalanceRepo)
+
+		mockUserBalanceRepo.On("GetAllByUserID", ctx, userID).Return([]*domain.UserBalance{{ID: 1, UserID: userID}}, nil)
+		mockDisbursementRepo.On("GetByUserID", ctx, userID).Return(expectedDisbursements, nil)
+
+		result, err := usecase.GetDisbursementsByUserID(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedDisbursements, result)
+
+		mockUserBalanceRepo.AssertExpectations(t)
+		mockDisbursementRepo.AssertExpectations(t)
+	})
+
+	t.Run("UserNotFound", func(t *testing.T) {
+		mockDisbursementRepo := new(mocks.DisbursementRepository)
+		mockUserBalanceRepo := new(mocks.UserBalanceRepository)
+		usecase := usecase.NewDisbursementUsecase(mockDisbursementRepo, mockUserBalanceRepo)
+
+		mockUserBalanceRepo.On("GetAllByUserID", ctx, userID).Return([]*domain.UserBalance{}, nil)
+
+		_, err := usecase.GetDisbursementsByUserID(ctx, userID)
+		assert.ErrorIs(t, err, domErr.ErrUserNotFound)
+
+		mockUserBalanceRepo.AssertExpectations(t)
+		mockDisbursementRepo.AssertNotCalled(t, "GetByUserID", ctx, userID)
+	})
+}
+
+func TestDisbursementUsecase_GetUserDisbursementByID(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(1)
+	expectedDisbursement := &domain.Disbursement{
+		ID:     7,
+		UserID: userID,
+		Amount: 500,
+		Status: domain.DisbursementStatusCompleted,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockDisbursementRepo := new(mocks.DisbursementRepository)
+		usecase := usecase.NewDisbursementUsecase(mockDisbursementRepo, nil)
+
+		mockDisbursementRepo.On("GetByID", ctx, expectedDisbursement.ID).Return(expectedDisbursement, nil)
+
+		result, err := usecase.GetUserDisbursementByID(ctx, userID, expectedDisbursement.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedDisbursement, result)
+
+		mockDisbursementRepo.AssertExpectations(t)
+	})
+
+	t.Run("OtherUsersDisbursement", func(t *testing.T) {
+		mockDisbursementRepo := new(mocks.DisbursementRepository)
+		usecase := usecase.NewDisbursementUsecase(mockDisbursementRepo, nil)
+
+		mockDisbursementRepo.On("GetByID", ctx, expectedDisbursement.ID).Return(expectedDisbursement, nil)
+
+		result, err := usecase.GetUserDisbursementByID(ctx, userID+1, expectedDisbursement.ID)
+		assert.ErrorIs(t, err, domErr.ErrDisbursementNotFound)
+		assert.Nil(t, result)
+
+		mockDisbursementRepo.AssertExpectations(t)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockDisbursementRepo := new(mocks.DisbursementRepository)
+		usecase := usecase.NewDisbursementUsecase(mockDisbursementRepo, nil)
+
+		mockDisbursementRepo.On("GetByID", ctx, expectedDisbursement.ID).Return(nil, sql.ErrNoRows)
+
+		_, err := usecase.GetUserDisbursementByID(ctx, userID, expectedDisbursement.ID)
+		assert.ErrorIs(t, err, domErr.ErrDisbursementNotFound)
+
+		mockDisbursementRepo.AssertExpectations(t)
+	})
+}
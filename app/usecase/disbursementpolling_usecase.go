@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/external"
+)
+
+const (
+	defaultDisbursementPollBackoff    = 30 * time.Second
+	defaultDisbursementPollMaxBackoff = 10 * time.Minute
+	defaultDisbursementReviewDeadline = 24 * time.Hour
+	defaultDisbursementPendingTimeout = 5 * time.Minute
+	defaultDisbursementPollBatchSize  = 100
+)
+
+var payoutSettlementStatuses = map[external.PayoutStatus]domain.DisbursementStatus{
+	external.PayoutStatusCompleted: domain.DisbursementStatusCompleted,
+	external.PayoutStatusFailed:    domain.DisbursementStatusFailed,
+}
+
+type DisbursementPollingUsecase struct {
+	disbursementRepository domain.DisbursementRepository
+	userBalanceUsecase     domain.UserBalanceUsecase
+	payoutRouter           external.PayoutRouter
+	policy                 domain.DisbursementPollPolicy
+}
+
+func NewDisbursementPollingUsecase(disbursementRepository domain.DisbursementRepository, userBalanceUsecase domain.UserBalanceUsecase, payoutRouter external.PayoutRouter, policy domain.DisbursementPollPolicy) domain.DisbursementPollingUsecase {
+	if policy.Backoff <= 0 {
+		policy.Backoff = defaultDisbursementPollBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = defaultDisbursementPollMaxBackoff
+	}
+	if policy.MaxBackoff < policy.Backoff {
+		policy.MaxBackoff = policy.Backoff
+	}
+	if policy.ReviewDeadline <= 0 {
+		policy.ReviewDeadline = defaultDisbursementReviewDeadline
+	}
+	if policy.PendingTimeout <= 0 {
+		policy.PendingTimeout = defaultDisbursementPendingTimeout
+	}
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = defaultDisbursementPollBatchSize
+	}
+
+	return &DisbursementPollingUsecase{
+		disbursementRepository: disbursementRepository,
+		userBalanceUsecase:     userBalanceUsecase,
+		payoutRouter:           payoutRouter,
+		policy:                 policy,
+	}
+}
+
+// PollSubmittedDisbursements asks the providers for the outcome of the submitted
+// disbursements that are due. A reported outcome settles the disbursement, otherwise it is
+// polled again after a growing backoff, until it is past the review deadline and escalated
+// to manual review. Stale pending disbursements, whose submission never finished, have no
+// provider to ask and only wait for that deadline. One disbursement failing does not stop
+// the others from being polled.
+func (u *DisbursementPollingUsecase) PollSubmittedDisbursements(ctx context.Context) (*domain.DisbursementPollReport, error) {
+	now := time.Now().UTC()
+
+	disbursements, err := u.disbursementRepository.GetDueForPolling(ctx, now, now.Add(-u.policy.PendingTimeout), u.policy.BatchSize)
+	if err != nil {
+		log.Println("[PollSubmittedDisbursements] GetDueForPolling err:", err)
+		return nil, err
+	}
+
+	report := &domain.DisbursementPollReport{}
+	for _, disbursement := range disbursements {
+		report.Checked++
+
+		status, err := u.pollDisbursement(ctx, disbursement, now)
+		if err != nil {
+			log.Printf("[PollSubmittedDisbursements] poll disbursement %s err: %v", disbursement.ReferenceID, err)
+			report.Errors++
+		}
+
+		switch status {
+		case domain.DisbursementStatusCompleted:
+			report.Completed++
+		case domain.DisbursementStatusFailed:
+			report.Failed++
+		case domain.DisbursementStatusManualReview:
+			report.Escalated++
+		case domain.DisbursementStatusPending, domain.DisbursementStatusSubmitted:
+			report.Pending++
+		}
+	}
+
+	return report, nil
+}
+
+// pollDisbursement returns the status disbursement ends up with, or an empty one when it
+// could not be changed.
+func (u *DisbursementPollingUsecase) pollDisbursement(ctx context.Context, disbursement *domain.Disbursement, now time.Time) (domain.DisbursementStatus, error) {
+	payout, pollErr := u.getPayout(ctx, disbursement)
+	if payout != nil {
+		if status, ok := payoutSettlementStatuses[payout.Status]; ok {
+			settled, err := u.userBalanceUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+				ReferenceID:           disbursement.ReferenceID,
+				PartnerDisbursementID: payout.PartnerID,
+				Status:                status,
+				FailureReason:         payout.Message,
+				Providers:             []string{disbursement.Provider},
+			})
+			if err != nil {
+				return "", err
+			}
+
+			return settled.Status, nil
+		}
+	}
+
+	// without an outcome the disbursement keeps its hold, a person has to find out what
+	// happened to it once the provider had enough time
+	if u.policy.ReviewDue(disbursement, now) {
+		updated := *disbursement
+		if err := updated.TransitionTo(domain.DisbursementStatusManualReview, now); err != nil {
+			return "", err
+		}
+		if err := u.disbursementRepository.UpdateStatus(ctx, &updated, disbursement.Status); err != nil {
+			return "", err
+		}
+		log.Printf("[PollSubmittedDisbursements] disbursement %s escalated to manual review after %d polls", disbursement.ReferenceID, disbursement.PollAttempts)
+
+		return domain.DisbursementStatusManualReview, pollErr
+	}
+
+	disbursement.PollAttempts++
+	nextPollAt := u.policy.NextPollAt(disbursement.PollAttempts, now)
+	disbursement.NextPollAt = &nextPollAt
+	if err := u.disbursementRepository.UpdatePollSchedule(ctx, disbursement); err != nil {
+		return "", err
+	}
+
+	return disbursement.Status, pollErr
+}
+
+// getPayout returns nil without an error for a disbursement whose submission got no answer:
+// the provider never told us its id, so only its callback or a person can settle it.
+func (u *DisbursementPollingUsecase) getPayout(ctx context.Context, disbursement *domain.Disbursement) (*external.PayoutStatusResponse, error) {
+	if disbursement.PartnerDisbursementID == "" {
+		return nil, nil
+	}
+
+	provider, err := u.payoutRouter.Provider(disbursement.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.GetPayout(ctx, disbursement.PartnerDisbursementID)
+}
@@ -0,0 +1,170 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/external"
+	extMocks "github.com/krisdioles/ppr-wallet/app/external/mocks"
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database/migration"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDisbursementPollingUsecase_PollSubmittedDisbursements(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+
+	// the bank accepts the payouts and never calls back
+	bank1Client := new(extMocks.IBank1Client)
+	for _, partnerID := range []string{"bank-1", "bank-2", "bank-3", "bank-4", "bank-5"} {
+		bank1Client.On("CreateDisbursement", mock.Anything, mock.Anything).Return(&external.Bank1CreateDisbursementResponse{
+			Status: "ok",
+			Data:   external.Bank1CreateDisbursementResponseData{ID: partnerID, Status: external.Bank1DisbursementStatusPending},
+		}, nil).Once()
+	}
+	// and the last one times out, so it never learns the id the bank gave it
+	bank1Client.On("CreateDisbursement", mock.Anything, mock.Anything).Return(nil, errors.New("timeout")).Once()
+	getDisbursementResponse := func(partnerID, status, message string) *external.Bank1GetDisbursementResponse {
+		return &external.Bank1GetDisbursementResponse{
+			Status:  "ok",
+			Message: message,
+			Data:    external.Bank1CreateDisbursementResponseData{ID: partnerID, Status: status},
+		}
+	}
+	bank1Client.On("GetDisbursement", mock.Anything, "bank-1").Return(getDisbursementResponse("bank-1", external.Bank1DisbursementStatusCompleted, ""), nil)
+	bank1Client.On("GetDisbursement", mock.Anything, "bank-2").Return(getDisbursementResponse("bank-2", external.Bank1DisbursementStatusFailed, "account closed"), nil)
+	bank1Client.On("GetDisbursement", mock.Anything, "bank-3").Return(getDisbursementResponse("bank-3", external.Bank1DisbursementStatusPending, ""), nil)
+	bank1Client.On("GetDisbursement", mock.Anything, "bank-4").Return(nil, errors.New("connection refused"))
+	bank1Client.On("GetDisbursement", mock.Anything, "bank-5").Return(getDisbursementResponse("bank-5", external.Bank1DisbursementStatusPending, ""), nil)
+
+	walletUsecase := newWalletUsecase(db, bank1Client)
+	disbursementRepository := repository.NewDisbursementRepository(db)
+	pollingUsecase := usecase.NewDisbursementPollingUsecase(disbursementRepository, walletUsecase, newPayoutRouter(bank1Client), domain.DisbursementPollPolicy{
+		Backoff:        time.Minute,
+		MaxBackoff:     time.Hour,
+		ReviewDeadline: time.Hour,
+	})
+
+	var referenceIDs []string
+	for _, amount := range []int64{100, 200, 300, 50, 150, 25} {
+		disbursement, err := walletUsecase.DisburseBalance(ctx, 1, &domain.DisburseBalanceRequest{Amount: amount})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusSubmitted, disbursement.Status)
+		referenceIDs = append(referenceIDs, disbursement.ReferenceID)
+	}
+
+	// the fifth one was submitted longer ago than the review deadline
+	_, err := db.Exec("UPDATE disbursements SET submitted_at = ? WHERE reference_id = ?", time.Now().UTC().Add(-2*time.Hour), referenceIDs[4])
+	assert.NoError(t, err)
+
+	report, err := pollingUsecase.PollSubmittedDisbursements(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.DisbursementPollReport{Checked: 6, Completed: 1, Failed: 1, Pending: 3, Escalated: 1, Errors: 1}, report)
+
+	assertStatus := func(referenceID string, expected domain.DisbursementStatus) *domain.Disbursement {
+		disbursement, err := disbursementRepository.GetByReferenceID(ctx, referenceID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, disbursement.Status)
+		return disbursement
+	}
+	assertStatus(referenceIDs[0], domain.DisbursementStatusCompleted)
+	assert.Equal(t, "account closed", assertStatus(referenceIDs[1], domain.DisbursementStatusFailed).FailureReason)
+	assertStatus(referenceIDs[4], domain.DisbursementStatusManualReview)
+
+	pending := assertStatus(referenceIDs[2], domain.DisbursementStatusSubmitted)
+	assert.Equal(t, 1, pending.PollAttempts)
+	if assert.NotNil(t, pending.NextPollAt) {
+		assert.WithinDuration(t, time.Now().Add(time.Minute), *pending.NextPollAt, 5*time.Second)
+	}
+	assert.Equal(t, 1, assertStatus(referenceIDs[3], domain.DisbursementStatusSubmitted).PollAttempts)
+	assert.Equal(t, 1, assertStatus(referenceIDs[5], domain.DisbursementStatusSubmitted).PollAttempts)
+	bank1Client.AssertNotCalled(t, "GetDisbursement", mock.Anything, "")
+
+	// the completed one left the wallet, the failed one was refunded, the others are still held
+	wallet, err := walletUsecase.GetUserBalanceByID(ctx, 1, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(900), wallet.Balance)
+	assert.Equal(t, int64(525), wallet.HeldBalance)
+	assertLedgerMatchesWallets(t, db)
+
+	t.Run("NothingDue", func(t *testing.T) {
+		report, err := pollingUsecase.PollSubmittedDisbursements(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.DisbursementPollReport{}, report)
+	})
+
+	t.Run("SettledFromManualReview", func(t *testing.T) {
+		result, err := walletUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+			ReferenceID: referenceIDs[4],
+			Status:      domain.DisbursementStatusCompleted,
+			ByOperator:  true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusCompleted, result.Status)
+
+		wallet, err := walletUsecase.GetUserBalanceByID(ctx, 1, domain.DefaultCurrency)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(750), wallet.Balance)
+		assert.Equal(t, int64(375), wallet.HeldBalance)
+		assertLedgerMatchesWallets(t, db)
+	})
+}
+
+func TestDisbursementPollingUsecase_StalePending(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	bank1Client := new(extMocks.IBank1Client)
+	disbursementRepository := repository.NewDisbursementRepository(db)
+	pollingUsecase := usecase.NewDisbursementPollingUsecase(disbursementRepository, newWalletUsecase(db, bank1Client), newPayoutRouter(bank1Client), domain.DisbursementPollPolicy{
+		Backoff:        time.Minute,
+		MaxBackoff:     time.Hour,
+		ReviewDeadline: time.Hour,
+		PendingTimeout: 5 * time.Minute,
+	})
+
+	// the service stopped after recording these and before submitting them, except for the
+	// fresh one which is still being submitted
+	now := time.Now().UTC()
+	for referenceID, createdAt := range map[string]time.Time{
+		"WLT-FRESH":     now,
+		"WLT-STALE":     now.Add(-10 * time.Minute),
+		"WLT-ABANDONED": now.Add(-2 * time.Hour),
+	} {
+		_, err := disbursementRepository.Create(ctx, &domain.Disbursement{
+			ReferenceID: referenceID,
+			UserID:      1,
+			Amount:      100,
+			Currency:    domain.DefaultCurrency,
+			Status:      domain.DisbursementStatusPending,
+			Provider:    "bank1",
+			CreatedAt:   createdAt,
+		})
+		assert.NoError(t, err)
+	}
+
+	report, err := pollingUsecase.PollSubmittedDisbursements(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.DisbursementPollReport{Checked: 2, Pending: 1, Escalated: 1}, report)
+
+	assertStatus := func(referenceID string, expected domain.DisbursementStatus) *domain.Disbursement {
+		disbursement, err := disbursementRepository.GetByReferenceID(ctx, referenceID)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, disbursement.Status)
+		return disbursement
+	}
+	assert.Equal(t, 0, assertStatus("WLT-FRESH", domain.DisbursementStatusPending).PollAttempts)
+	assert.Equal(t, 1, assertStatus("WLT-STALE", domain.DisbursementStatusPending).PollAttempts)
+	assertStatus("WLT-ABANDONED", domain.DisbursementStatusManualReview)
+
+	// they were never submitted, so there is nothing to ask the bank
+	bank1Client.AssertNotCalled(t, "GetDisbursement", mock.Anything, mock.Anything)
+}
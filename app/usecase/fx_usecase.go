@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+// fxRateDecimals is the precision of a rate derived by inverting the rate of the opposite pair.
+const fxRateDecimals = 10
+
+type FXUsecase struct {
+	transactionManager    domain.TransactionManager
+	userBalanceRepository domain.UserBalanceRepository
+	fxRateRepository      domain.FXRateRepository
+	fxQuoteRepository     domain.FXQuoteRepository
+	referenceIDGenerator  domain.ReferenceIDGenerator
+	quoteTTL              time.Duration
+}
+
+func NewFXUsecase(transactionManager domain.TransactionManager, userBalanceRepository domain.UserBalanceRepository, fxRateRepository domain.FXRateRepository, fxQuoteRepository domain.FXQuoteRepository, referenceIDGenerator domain.ReferenceIDGenerator, quoteTTL time.Duration) domain.FXUsecase {
+	if quoteTTL <= 0 {
+		quoteTTL = domain.DefaultFXQuoteTTL
+	}
+
+	return &FXUsecase{
+		transactionManager:    transactionManager,
+		userBalanceRepository: userBalanceRepository,
+		fxRateRepository:      fxRateRepository,
+		fxQuoteRepository:     fxQuoteRepository,
+		referenceIDGenerator:  referenceIDGenerator,
+		quoteTTL:              quoteTTL,
+	}
+}
+
+func (u *FXUsecase) GetRates(ctx context.Context) ([]*domain.FXRate, error) {
+	rates, err := u.fxRateRepository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if rates == nil {
+		rates = []*domain.FXRate{}
+	}
+
+	return rates, nil
+}
+
+// SetRates stores all rates of request or, when one of them is invalid, none of them.
+func (u *FXUsecase) SetRates(ctx context.Context, request *domain.SetFXRatesRequest) ([]*domain.FXRate, error) {
+	if request == nil || len(request.Rates) == 0 {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	rates := make([]*domain.FXRate, 0, len(request.Rates))
+	for _, input := range request.Rates {
+		rate := &domain.FXRate{
+			BaseCurrency:  input.BaseCurrency,
+			QuoteCurrency: input.QuoteCurrency,
+			Rate:          input.Rate,
+		}
+		if err := rate.Validate(); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+
+	err := u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		for _, rate := range rates {
+			if err := u.fxRateRepository.Upsert(txCtx, rate); err != nil {
+				log.Println("[SetRates] Upsert err:", err)
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.GetRates(ctx)
+}
+
+// LoadRatesFile sets the rates of the JSON file at path, which has the shape of SetFXRatesRequest.
+func (u *FXUsecase) LoadRatesFile(ctx context.Context, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var request domain.SetFXRatesRequest
+	if err := json.Unmarshal(content, &request); err != nil {
+		return err
+	}
+
+	_, err = u.SetRates(ctx, &request)
+	return err
+}
+
+// CreateQuote prices the conversion of an amount between two wallets of the user and locks
+// the rate for the quote TTL. The converted amount is rounded down.
+func (u *FXUsecase) CreateQuote(ctx context.Context, userID int64, request *domain.CreateFXQuoteRequest) (*domain.FXQuote, error) {
+	if request == nil || request.Amount <= 0 {
+		return nil, errors.ErrInvalidParameter
+	}
+	if request.FromCurrency == request.ToCurrency {
+		return nil, errors.ErrSameCurrencyConversion
+	}
+
+	for _, currency := range []string{request.FromCurrency, request.ToCurrency} {
+		if _, err := u.userBalanceRepository.GetByUserIDAndCurrency(ctx, userID, currency); err != nil {
+			if err == sql.ErrNoRows {
+				return nil, errors.ErrUserNotFound
+			}
+
+			return nil, err
+		}
+	}
+
+	rate, rateText, err := u.rateFor(ctx, request.FromCurrency, request.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	from := domain.NewMoney(request.Amount, request.FromCurrency)
+	to, err := domain.ConvertMoney(from, rate, request.ToCurrency)
+	if err != nil {
+		return nil, err
+	}
+	if to.Amount <= 0 {
+		// too small to buy a single minor unit of the target currency
+		return nil, errors.ErrInvalidParameter
+	}
+
+	now := time.Now().UTC()
+	quote, err := u.fxQuoteRepository.Create(ctx, &domain.FXQuote{
+		ReferenceID:  u.referenceIDGenerator.Generate(),
+		UserID:       userID,
+		FromCurrency: from.Currency,
+		FromAmount:   from.Amount,
+		ToCurrency:   to.Currency,
+		ToAmount:     to.Amount,
+		Rate:         rateText,
+		ExpiresAt:    now.Add(u.quoteTTL),
+		CreatedAt:    now,
+	})
+	if err != nil {
+		log.Println("[CreateQuote] Create quote err:", err)
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// rateFor returns the rate from one currency into another, derived from the opposite pair
+// when only that one is set.
+func (u *FXUsecase) rateFor(ctx context.Context, from, to string) (*big.Rat, string, error) {
+	fxRate, err := u.fxRateRepository.GetByPair(ctx, from, to)
+	if err == nil {
+		rate, err := fxRate.RateValue()
+		return rate, fxRate.Rate, err
+	}
+	if err != sql.ErrNoRows {
+		return nil, "", err
+	}
+
+	fxRate, err = u.fxRateRepository.GetByPair(ctx, to, from)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, "", errors.ErrFXRateNotFound
+		}
+
+		return nil, "", err
+	}
+
+	inverse, err := fxRate.RateValue()
+	if err != nil {
+		return nil, "", err
+	}
+	// round the rate itself, so the quoted amount follows from the rate shown with it
+	rateText := new(big.Rat).Inv(inverse).FloatString(fxRateDecimals)
+	rate, _ := new(big.Rat).SetString(rateText)
+
+	return rate, rateText, nil
+}
@@ -0,0 +1,152 @@
+package usecase_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database/migration"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/krisdioles/ppr-wallet/pkg/refid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFXUsecase(db *sqlx.DB, quoteTTL time.Duration) domain.FXUsecase {
+	return usecase.NewFXUsecase(
+		repository.NewTransactionManager(db),
+		repository.NewUserBalanceRepository(db),
+		repository.NewFXRateRepository(db),
+		repository.NewFXQuoteRepository(db),
+		refid.NewGenerator("WLT"),
+		quoteTTL,
+	)
+}
+
+// newFXTestDB creates a database with a user holding an IDR and a USD wallet.
+func newFXTestDB(t *testing.T) *sqlx.DB {
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{UserID: 1, Username: "andy123", Balance: 1000000})
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{UserID: 1, Username: "andy123", Currency: "USD", Balance: 1000})
+
+	return db
+}
+
+func TestFXUsecase_SetRates(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	fxUsecase := newFXUsecase(db, 0)
+
+	rates, err := fxUsecase.GetRates(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, rates)
+
+	rates, err = fxUsecase.SetRates(ctx, &domain.SetFXRatesRequest{Rates: []*domain.FXRateInput{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "16000"},
+		{BaseCurrency: "SGD", QuoteCurrency: "IDR", Rate: "12000"},
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, rates, 2)
+
+	// a rate is replaced rather than added
+	_, err = fxUsecase.SetRates(ctx, &domain.SetFXRatesRequest{Rates: []*domain.FXRateInput{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "16250.50"},
+	}})
+	assert.NoError(t, err)
+
+	// one invalid rate rejects the whole request
+	_, err = fxUsecase.SetRates(ctx, &domain.SetFXRatesRequest{Rates: []*domain.FXRateInput{
+		{BaseCurrency: "SGD", QuoteCurrency: "IDR", Rate: "1"},
+		{BaseCurrency: "USD", QuoteCurrency: "USD", Rate: "1"},
+	}})
+	assert.ErrorIs(t, err, domErr.ErrSameCurrencyConversion)
+
+	rates, err = fxUsecase.GetRates(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, rates, 2)
+	for _, rate := range rates {
+		switch rate.BaseCurrency {
+		case "USD":
+			assert.Equal(t, "16250.50", rate.Rate)
+		case "SGD":
+			assert.Equal(t, "12000", rate.Rate)
+		}
+	}
+}
+
+func TestFXUsecase_LoadRatesFile(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	fxUsecase := newFXUsecase(db, 0)
+
+	path := filepath.Join(t.TempDir(), "fxrates.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"rates":[{"base_currency":"USD","quote_currency":"IDR","rate":"16000"}]}`), 0o600))
+
+	assert.NoError(t, fxUsecase.LoadRatesFile(ctx, path))
+
+	rates, err := fxUsecase.GetRates(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, rates, 1)
+
+	assert.Error(t, fxUsecase.LoadRatesFile(ctx, filepath.Join(t.TempDir(), "missing.json")))
+}
+
+func TestFXUsecase_CreateQuote(t *testing.T) {
+	ctx := context.Background()
+
+	db := newFXTestDB(t)
+	fxUsecase := newFXUsecase(db, time.Minute)
+	_, err := fxUsecase.SetRates(ctx, &domain.SetFXRatesRequest{Rates: []*domain.FXRateInput{
+		{BaseCurrency: "USD", QuoteCurrency: "IDR", Rate: "16000"},
+	}})
+	assert.NoError(t, err)
+
+	t.Run("DirectRate", func(t *testing.T) {
+		quote, err := fxUsecase.CreateQuote(ctx, 1, &domain.CreateFXQuoteRequest{FromCurrency: "USD", ToCurrency: "IDR", Amount: 150})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, quote.ReferenceID)
+		assert.Equal(t, "16000", quote.Rate)
+		assert.Equal(t, domain.NewMoney(2400000, "IDR"), quote.ToMoney())
+		assert.WithinDuration(t, time.Now().Add(time.Minute), quote.ExpiresAt, 5*time.Second)
+
+		stored, err := repository.NewFXQuoteRepository(db).GetByReferenceID(ctx, quote.ReferenceID)
+		assert.NoError(t, err)
+		assert.Equal(t, quote.ToAmount, stored.ToAmount)
+	})
+
+	t.Run("InverseRate", func(t *testing.T) {
+		quote, err := fxUsecase.CreateQuote(ctx, 1, &domain.CreateFXQuoteRequest{FromCurrency: "IDR", ToCurrency: "USD", Amount: 1600000})
+		assert.NoError(t, err)
+		assert.Equal(t, "0.0000625000", quote.Rate)
+		assert.Equal(t, domain.NewMoney(100, "USD"), quote.ToMoney())
+	})
+
+	t.Run("TooSmall", func(t *testing.T) {
+		_, err := fxUsecase.CreateQuote(ctx, 1, &domain.CreateFXQuoteRequest{FromCurrency: "IDR", ToCurrency: "USD", Amount: 100})
+		assert.ErrorIs(t, err, domErr.ErrInvalidParameter)
+	})
+
+	t.Run("SameCurrency", func(t *testing.T) {
+		_, err := fxUsecase.CreateQuote(ctx, 1, &domain.CreateFXQuoteRequest{FromCurrency: "USD", ToCurrency: "USD", Amount: 100})
+		assert.ErrorIs(t, err, domErr.ErrSameCurrencyConversion)
+	})
+
+	t.Run("WalletNotFound", func(t *testing.T) {
+		_, err := fxUsecase.CreateQuote(ctx, 1, &domain.CreateFXQuoteRequest{FromCurrency: "USD", ToCurrency: "SGD", Amount: 100})
+		assert.ErrorIs(t, err, domErr.ErrUserNotFound)
+	})
+
+	t.Run("RateNotFound", func(t *testing.T) {
+		migration.InsertUserBalancesRecord(db, domain.UserBalance{UserID: 1, Username: "andy123", Currency: "SGD"})
+
+		_, err := fxUsecase.CreateQuote(ctx, 1, &domain.CreateFXQuoteRequest{FromCurrency: "USD", ToCurrency: "SGD", Amount: 100})
+		assert.ErrorIs(t, err, domErr.ErrFXRateNotFound)
+	})
+}
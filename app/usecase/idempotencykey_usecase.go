@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+const (
+	maxIdempotencyKeyCompleteAttempts = 3
+	idempotencyKeyCompleteRetryDelay  = 50 * time.Millisecond
+)
+
+type IdempotencyKeyUsecase struct {
+	idempotencyKeyRepository domain.IdempotencyKeyRepository
+	lockTimeout              time.Duration
+}
+
+func NewIdempotencyKeyUsecase(idempotencyKeyRepository domain.IdempotencyKeyRepository, lockTimeout time.Duration) domain.IdempotencyKeyUsecase {
+	if lockTimeout <= 0 {
+		lockTimeout = domain.DefaultIdempotencyKeyLockTimeout
+	}
+
+	return &IdempotencyKeyUsecase{
+		idempotencyKeyRepository: idempotencyKeyRepository,
+		lockTimeout:              lockTimeout,
+	}
+}
+
+// Begin claims key within scope for a new request. When the key was already used for the
+// same request, the stored record is returned so the caller can replay its response. A key
+// whose request is still in progress after the lock timeout was left behind, e.g. by a crash,
+// and is taken over so the request runs again.
+func (u *IdempotencyKeyUsecase) Begin(ctx context.Context, scope, key, requestFingerprint string) (*domain.IdempotencyKey, error) {
+	idempotencyKey := &domain.IdempotencyKey{
+		Scope:              scope,
+		Key:                key,
+		RequestFingerprint: requestFingerprint,
+	}
+
+	err := u.idempotencyKeyRepository.Create(ctx, idempotencyKey)
+	if err == nil {
+		return idempotencyKey, nil
+	}
+
+	if err != errors.ErrDuplicateIdempotencyKey {
+		log.Println("[Begin] Create err:", err)
+		return nil, err
+	}
+
+	existingIdempotencyKey, err := u.idempotencyKeyRepository.GetByKey(ctx, scope, key)
+	if err != nil {
+		log.Println("[Begin] GetByKey err:", err)
+		return nil, err
+	}
+
+	if existingIdempotencyKey.RequestFingerprint != requestFingerprint {
+		return nil, errors.ErrIdempotencyKeyConflict
+	}
+
+	if existingIdempotencyKey.IsCompleted() {
+		return existingIdempotencyKey, nil
+	}
+
+	if err := u.idempotencyKeyRepository.LockStaleByKey(ctx, scope, key, time.Now().Add(-u.lockTimeout)); err != nil {
+		if err != errors.ErrIdempotencyKeyInProgress {
+			log.Println("[Begin] LockStaleByKey err:", err)
+		}
+		return nil, err
+	}
+
+	return existingIdempotencyKey, nil
+}
+
+// Complete stores the response of the request that claimed key. The write is retried a few
+// times, a key left in progress answers 409 to every retry until its lock times out.
+func (u *IdempotencyKeyUsecase) Complete(ctx context.Context, scope, key string, responseStatus int, responseBody string) error {
+	var err error
+	for attempt := 1; attempt <= maxIdempotencyKeyCompleteAttempts; attempt++ {
+		err = u.idempotencyKeyRepository.UpdateResponseByKey(ctx, scope, key, responseStatus, responseBody)
+		if err == nil {
+			return nil
+		}
+		log.Println("[Complete] UpdateResponseByKey err:", err)
+
+		if attempt == maxIdempotencyKeyCompleteAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * idempotencyKeyCompleteRetryDelay):
+		}
+	}
+
+	return err
+}
+
+// Release gives up the claim on key without storing a response, so a retry with it runs the
+// request again.
+func (u *IdempotencyKeyUsecase) Release(ctx context.Context, scope, key string) error {
+	if err := u.idempotencyKeyRepository.DeleteByKey(ctx, scope, key); err != nil {
+		log.Println("[Release] DeleteByKey err:", err)
+		return err
+	}
+
+	return nil
+}
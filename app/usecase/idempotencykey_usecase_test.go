@@ -0,0 +1,236 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/domain/mocks"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotencyKeyUsecase_Begin(t *testing.T) {
+	ctx := context.Background()
+	scope := "1"
+	key := "key-1"
+	fingerprint := "fingerprint"
+	newIdempotencyKey := &domain.IdempotencyKey{
+		Scope:              scope,
+		Key:                key,
+		RequestFingerprint: fingerprint,
+	}
+
+	t.Run("NewKey", func(t *testing.T) {
+		mockIdempotencyKeyRepo := new(mocks.IdempotencyKeyRepository)
+		usecase := usecase.NewIdempotencyKeyUsecase(mockIdempotencyKeyRepo, 0)
+
+		mockIdempotencyKeyRepo.On("Create", ctx, newIdempotencyKey).Return(nil)
+
+		result, err := usecase.Begin(ctx, scope, key, fingerprint)
+		assert.NoError(t, err)
+		assert.False(t, result.IsCompleted())
+
+		mockIdempotencyKeyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		mockIdempotencyKeyRepo := new(mocks.IdempotencyKeyRepository)
+		usecase := usecase.NewIdempotencyKeyUsecase(mockIdempotencyKeyRepo, 0)
+
+		storedIdempotencyKey := &domain.IdempotencyKey{
+			Scope:              scope,
+			Key:                key,
+			RequestFingerprint: fingerprint,
+			ResponseStatus:     200,
+			ResponseBody:       `{"status":"ok"}`,
+		}
+
+		mockIdempotencyKeyRepo.On("Create", ctx, newIdempotencyKey).Return(domErr.ErrDuplicateIdempotencyKey)
+		mockIdempotencyKeyRepo.On("GetByKey", ctx, scope, key).Return(storedIdempotencyKey, nil)
+
+		result, err := usecase.Begin(ctx, scope, key, fingerprint)
+		assert.NoError(t, err)
+		assert.Equal(t, storedIdempotencyKey, result)
+
+		mockIdempotencyKeyRepo.AssertExpectations(t)
+	})
+
+	t.Run("DifferentPayload", func(t *testing.T) {
+		mockIdempotencyKeyRepo := new(mocks.IdempotencyKeyRepository)
+		usecase := usecase.NewIdempotencyKeyUsecase(mockIdempotencyKeyRepo, 0)
+
+		mockIdempotencyKeyRepo.On("Create", ctx, newIdempotencyKey).Return(domErr.ErrDuplicateIdempotencyKey)
+		mockIdempotencyKeyRepo.On("GetByKey", ctx, scope, key).Return(&domain.IdempotencyKey{
+			Key:                key,
+			RequestFingerprint: "other-fingerprint",
+			ResponseStatus:     200,
+		}, nil)
+
+		result, err := usecase.Begin(ctx, scope, key, fingerprint)
+		assert.ErrorIs(t, err, domErr.ErrIdempotencyKeyConflict)
+		assert.Nil(t, result)
+
+		mockIdempotencyKeyRepo.AssertExpectations(t)
+	})
+
+	t.Run("InProgress", func(t *testing.T) {
+		mockIdempotencyKeyRepo := new(mocks.IdempotencyKeyRepository)
+		usecase := usecase.NewIdempotencyKeyUsecase(mockIdempotencyKeyRepo, 0)
+
+		mockIdempotencyKeyRepo.On("Create", ctx, newIdempotencyKey).Return(domErr.ErrDuplicateIdempotencyKey)
+		mockIdempotencyKeyRepo.On("GetByKey", ctx, scope, key).Return(&domain.IdempotencyKey{
+			Key:                key,
+			RequestFingerprint: fingerprint,
+		}, nil)
+		mockIdempotencyKeyRepo.On("LockStaleByKey", ctx, scope, key, mock.AnythingOfType("time.Time")).Return(domErr.ErrIdempotencyKeyInProgress)
+
+		result, err := usecase.Begin(ctx, scope, key, fingerprint)
+		assert.ErrorIs(t, err, domErr.ErrIdempotencyKeyInProgress)
+		assert.Nil(t, result)
+
+		mockIdempotencyKeyRepo.AssertExpectations(t)
+	})
+
+	t.Run("StaleInProgress", func(t *testing.T) {
+		mockIdempotencyKeyRepo := new(mocks.IdempotencyKeyRepository)
+		usecase := usecase.NewIdempotencyKeyUsecase(mockIdempotencyKeyRepo, time.Minute)
+
+		staleIdempotencyKey := &domain.IdempotencyKey{
+			Key:                key,
+			RequestFingerprint: fingerprint,
+		}
+
+		mockIdempotencyKeyRepo.On("Create", ctx, newIdempotencyKey).Return(domErr.ErrDuplicateIdempotencyKey)
+		mockIdempotencyKeyRepo.On("GetByKey", ctx, scope, key).Return(staleIdempotencyKey, nil)
+		mockIdempotencyKeyRepo.On("LockStaleByKey", ctx, scope, key, mock.MatchedBy(func(lockedBefore time.Time) bool {
+			return time.Since(lockedBefore) >= time.Minute
+		})).Return(nil)
+
+		result, err := usecase.Begin(ctx, scope, key, fingerprint)
+		assert.NoError(t, err)
+		assert.Equal(t, staleIdempotencyKey, result)
+		assert.False(t, result.IsCompleted())
+
+		mockIdempotencyKeyRepo.AssertExpectations(t)
+	})
+
+	t.Run("CreateError", func(t *testing.T) {
+		mockIdempotencyKeyRepo := new(mocks.IdempotencyKeyRepository)
+		usecase := usecase.NewIdempotencyKeyUsecase(mockIdempotencyKeyRepo, 0)
+
+		mockIdempotencyKeyRepo.On("Create", ctx, newIdempotencyKey).Return(errors.New("some error"))
+
+		result, err := usecase.Begin(ctx, scope, key, fingerprint)
+		assert.Error(t, err)
+		assert.Nil(t, result)
+
+		mockIdempotencyKeyRepo.AssertExpectations(t)
+		mockIdempotencyKeyRepo.AssertNotCalled(t, "GetByKey", ctx, scope, key)
+	})
+}
+
+func TestIdempotencyKeyUsecase_Complete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockIdempotencyKeyRepo := new(mocks.IdempotencyKeyRepository)
+		usecase := usecase.NewIdempotencyKeyUsecase(mockIdempotencyKeyRepo, 0)
+
+		mockIdempotencyKeyRepo.On("UpdateResponseByKey", ctx, "1", "key-1", 200, `{"status":"ok"}`).Return(nil)
+
+		err := usecase.Complete(ctx, "1", "key-1", 200, `{"status":"ok"}`)
+		assert.NoError(t, err)
+
+		mockIdempotencyKeyRepo.AssertExpectations(t)
+	})
+
+	t.Run("UpdateError", func(t *testing.T) {
+		mockIdempotencyKeyRepo := new(mocks.IdempotencyKeyRepository)
+		usecase := usecase.NewIdempotencyKeyUsecase(mockIdempotencyKeyRepo, 0)
+
+		mockIdempotencyKeyRepo.On("UpdateResponseByKey", ctx, "1", "key-1", 200, `{"status":"ok"}`).Return(errors.New("some error"))
+
+		err := usecase.Complete(ctx, "1", "key-1", 200, `{"status":"ok"}`)
+		assert.Error(t, err)
+
+		mockIdempotencyKeyRepo.AssertExpectations(t)
+		mockIdempotencyKeyRepo.AssertNumberOfCalls(t, "UpdateResponseByKey", 3)
+	})
+
+	t.Run("RetriedError", func(t *testing.T) {
+		mockIdempotencyKeyRepo := new(mocks.IdempotencyKeyRepository)
+		usecase := usecase.NewIdempotencyKeyUsecase(mockIdempotencyKeyRepo, 0)
+
+		mockIdempotencyKeyRepo.On("UpdateResponseByKey", ctx, "1", "key-1", 200, `{"status":"ok"}`).Return(errors.New("some error")).Once()
+		mockIdempotencyKeyRepo.On("UpdateResponseByKey", ctx, "1", "key-1", 200, `{"status":"ok"}`).Return(nil).Once()
+
+		err := usecase.Complete(ctx, "1", "key-1", 200, `{"status":"ok"}`)
+		assert.NoError(t, err)
+
+		mockIdempotencyKeyRepo.AssertExpectations(t)
+	})
+}
+
+func TestIdempotencyKeyUsecase_Begin_StaleInProgressKey(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	usecase := usecase.NewIdempotencyKeyUsecase(repository.NewIdempotencyKeyRepository(db), time.Minute)
+
+	_, err := usecase.Begin(ctx, "1", "key-1", "fingerprint")
+	assert.NoError(t, err)
+
+	// the first request is still running
+	_, err = usecase.Begin(ctx, "1", "key-1", "fingerprint")
+	assert.ErrorIs(t, err, domErr.ErrIdempotencyKeyInProgress)
+
+	// the first request died without completing the key
+	db.MustExec(`UPDATE idempotency_keys SET locked_at = ? WHERE scope = ? AND idempotency_key = ?`, time.Now().UTC().Add(-2*time.Minute), "1", "key-1")
+
+	result, err := usecase.Begin(ctx, "1", "key-1", "fingerprint")
+	assert.NoError(t, err)
+	assert.False(t, result.IsCompleted())
+
+	// the retry holds the key now
+	_, err = usecase.Begin(ctx, "1", "key-1", "fingerprint")
+	assert.ErrorIs(t, err, domErr.ErrIdempotencyKeyInProgress)
+
+	assert.NoError(t, usecase.Complete(ctx, "1", "key-1", 200, `{"status":"ok"}`))
+
+	result, err = usecase.Begin(ctx, "1", "key-1", "fingerprint")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, result.ResponseStatus)
+}
+
+func TestIdempotencyKeyUsecase_Release(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockIdempotencyKeyRepo := new(mocks.IdempotencyKeyRepository)
+		usecase := usecase.NewIdempotencyKeyUsecase(mockIdempotencyKeyRepo, 0)
+
+		mockIdempotencyKeyRepo.On("DeleteByKey", ctx, "1", "key-1").Return(nil)
+
+		err := usecase.Release(ctx, "1", "key-1")
+		assert.NoError(t, err)
+
+		mockIdempotencyKeyRepo.AssertExpectations(t)
+	})
+
+	t.Run("DeleteError", func(t *testing.T) {
+		mockIdempotencyKeyRepo := new(mocks.IdempotencyKeyRepository)
+		usecase := usecase.NewIdempotencyKeyUsecase(mockIdempotencyKeyRepo, 0)
+
+		mockIdempotencyKeyRepo.On("DeleteByKey", ctx, "1", "key-1").Return(errors.New("some error"))
+
+		err := usecase.Release(ctx, "1", "key-1")
+		assert.Error(t, err)
+
+		mockIdempotencyKeyRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type JournalEntryUsecase struct {
+	transactionManager     domain.TransactionManager
+	userBalanceRepository  domain.UserBalanceRepository
+	journalEntryRepository domain.JournalEntryRepository
+}
+
+func NewJournalEntryUsecase(transactionManager domain.TransactionManager, userBalanceRepository domain.UserBalanceRepository, journalEntryRepository domain.JournalEntryRepository) domain.JournalEntryUsecase {
+	return &JournalEntryUsecase{
+		transactionManager:     transactionManager,
+		userBalanceRepository:  userBalanceRepository,
+		journalEntryRepository: journalEntryRepository,
+	}
+}
+
+func (u *JournalEntryUsecase) GetJournalEntries(ctx context.Context, filter *domain.JournalEntryFilter) (*domain.JournalEntryPage, error) {
+	limit, err := pageLimit(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, nextCursor, err := u.getPage(ctx, filter, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.JournalEntryPage{
+		Entries:    entries,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// GetUserStatement lists the postings to the wallet of userID with the balance after each of
+// them. Only the currency of the wallet, the date range and the page of filter apply,
+// filtering out single postings would break the running balance.
+func (u *JournalEntryUsecase) GetUserStatement(ctx context.Context, userID int64, filter *domain.JournalEntryFilter) (*domain.UserStatement, error) {
+	limit, err := pageLimit(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := filter.Currency
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+
+	statementFilter := &domain.JournalEntryFilter{
+		AccountID: domain.WalletAccountID(userID, currency),
+		From:      filter.From,
+		To:        filter.To,
+		Cursor:    filter.Cursor,
+	}
+	statement := &domain.UserStatement{
+		UserID:    userID,
+		AccountID: statementFilter.AccountID,
+		Lines:     []*domain.StatementLine{},
+	}
+
+	// the page and the balance before it are read in one transaction, so a posting in
+	// between cannot shift the running balance
+	var entries []*domain.JournalEntry
+	err = u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := u.userBalanceRepository.GetByUserIDAndCurrency(txCtx, userID, currency); err != nil {
+			if err == sql.ErrNoRows {
+				return errors.ErrUserNotFound
+			}
+
+			return err
+		}
+
+		var err error
+		if entries, statement.NextCursor, err = u.getPage(txCtx, statementFilter, limit); err != nil || len(entries) == 0 {
+			return err
+		}
+
+		openingBalance, err := u.journalEntryRepository.GetAccountBalanceBefore(txCtx, statementFilter.AccountID, entries[0].ID)
+		if err != nil {
+			return err
+		}
+		statement.OpeningBalance = openingBalance.Balance
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	runningBalance := statement.OpeningBalance
+	for _, entry := range entries {
+		amount := entry.CreditAmount - entry.DebitAmount
+		runningBalance += amount
+
+		statement.Lines = append(statement.Lines, &domain.StatementLine{
+			EntryID:         entry.ID,
+			Folio:           entry.Folio,
+			TransactionName: entry.TransactionName,
+			Amount:          amount,
+			RunningBalance:  runningBalance,
+			PostedAt:        entry.PostedAt,
+			ValueDate:       entry.ValueDate,
+		})
+	}
+	statement.ClosingBalance = runningBalance
+
+	return statement, nil
+}
+
+// getPage reads one entry more than limit to find out whether there is a next page.
+func (u *JournalEntryUsecase) getPage(ctx context.Context, filter *domain.JournalEntryFilter, limit int) ([]*domain.JournalEntry, int64, error) {
+	pageFilter := *filter
+	pageFilter.Limit = limit + 1
+
+	entries, err := u.journalEntryRepository.GetEntries(ctx, &pageFilter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if len(entries) <= limit {
+		return entries, 0, nil
+	}
+
+	entries = entries[:limit]
+	return entries, entries[limit-1].ID, nil
+}
+
+func pageLimit(filter *domain.JournalEntryFilter) (int, error) {
+	if filter.Cursor < 0 || filter.Limit < 0 || filter.Limit > domain.MaxJournalEntryPageLimit {
+		return 0, errors.ErrInvalidParameter
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return 0, errors.ErrInvalidParameter
+	}
+
+	if filter.Limit == 0 {
+		return domain.DefaultJournalEntryPageLimit, nil
+	}
+
+	return filter.Limit, nil
+}
@@ -0,0 +1,184 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/domain/mocks"
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database/migration"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestJournalEntryUsecase_GetJournalEntries(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("NextPage", func(t *testing.T) {
+		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+		usecase := usecase.NewJournalEntryUsecase(nil, nil, mockJournalEntryRepo)
+
+		// one entry more than the limit means there is a next page
+		mockJournalEntryRepo.On("GetEntries", ctx, &domain.JournalEntryFilter{Folio: "WLT-1", Cursor: 4, Limit: 3}).
+			Return([]*domain.JournalEntry{{ID: 5}, {ID: 6}, {ID: 7}}, nil)
+
+		page, err := usecase.GetJournalEntries(ctx, &domain.JournalEntryFilter{Folio: "WLT-1", Cursor: 4, Limit: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []*domain.JournalEntry{{ID: 5}, {ID: 6}}, page.Entries)
+		assert.Equal(t, int64(6), page.NextCursor)
+
+		mockJournalEntryRepo.AssertExpectations(t)
+	})
+
+	t.Run("LastPage", func(t *testing.T) {
+		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+		usecase := usecase.NewJournalEntryUsecase(nil, nil, mockJournalEntryRepo)
+
+		mockJournalEntryRepo.On("GetEntries", ctx, &domain.JournalEntryFilter{Limit: domain.DefaultJournalEntryPageLimit + 1}).
+			Return([]*domain.JournalEntry{{ID: 1}}, nil)
+
+		page, err := usecase.GetJournalEntries(ctx, &domain.JournalEntryFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, page.Entries, 1)
+		assert.Zero(t, page.NextCursor)
+
+		mockJournalEntryRepo.AssertExpectations(t)
+	})
+
+	t.Run("InvalidParameter", func(t *testing.T) {
+		now := time.Now()
+		filters := []*domain.JournalEntryFilter{
+			{Limit: domain.MaxJournalEntryPageLimit + 1},
+			{Cursor: -1},
+			{From: now, To: now.Add(-time.Hour)},
+		}
+
+		for _, filter := range filters {
+			mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+			usecase := usecase.NewJournalEntryUsecase(nil, nil, mockJournalEntryRepo)
+
+			_, err := usecase.GetJournalEntries(ctx, filter)
+			assert.ErrorIs(t, err, domErr.ErrInvalidParameter)
+
+			mockJournalEntryRepo.AssertNotCalled(t, "GetEntries", mock.Anything, mock.Anything)
+		}
+	})
+}
+
+func TestJournalEntryUsecase_GetJournalEntries_SubSecondBounds(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "brandy345", Balance: 2000})
+
+	postedAt := time.Date(2026, 1, 15, 10, 0, 0, 500_000_000, time.UTC)
+	journal := domain.NewJournalTransaction("WLT-1", "Wallet transfer").
+		Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 300).
+		Credit(domain.WalletAccountID(2, domain.DefaultCurrency), 300)
+	journal.PostedAt = postedAt
+	migration.InsertJournalTransactionRecord(db, journal)
+
+	usecase := usecase.NewJournalEntryUsecase(nil, nil, repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)))
+
+	wib := time.FixedZone("WIB", 7*60*60)
+	tests := []struct {
+		name     string
+		from, to time.Time
+		expected int
+	}{
+		{name: "FromIsInclusive", from: postedAt, expected: 2},
+		{name: "FromInAnotherZone", from: postedAt.In(wib), expected: 2},
+		{name: "FromAfterInSameSecond", from: postedAt.Add(time.Millisecond), expected: 0},
+		{name: "ToIsExclusive", to: postedAt, expected: 0},
+		{name: "ToAfterInSameSecond", to: postedAt.Add(time.Millisecond), expected: 2},
+		{name: "ToBeforeInSameSecond", to: postedAt.Add(-100 * time.Millisecond), expected: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := usecase.GetJournalEntries(ctx, &domain.JournalEntryFilter{Folio: "WLT-1", From: tt.from, To: tt.to})
+			assert.NoError(t, err)
+			assert.Len(t, page.Entries, tt.expected)
+		})
+	}
+}
+
+func TestJournalEntryUsecase_GetUserStatement_UserNotFound(t *testing.T) {
+	ctx := context.Background()
+
+	mockTransactionManager := new(mocks.TransactionManager)
+	mockUserBalanceRepo := new(mocks.UserBalanceRepository)
+	mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+	usecase := usecase.NewJournalEntryUsecase(mockTransactionManager, mockUserBalanceRepo, mockJournalEntryRepo)
+
+	mockTransactionManager.On("WithinTransaction", ctx, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	mockUserBalanceRepo.On("GetByUserIDAndCurrency", ctx, int64(99), domain.DefaultCurrency).Return(nil, sql.ErrNoRows)
+
+	_, err := usecase.GetUserStatement(ctx, 99, &domain.JournalEntryFilter{})
+	assert.ErrorIs(t, err, domErr.ErrUserNotFound)
+
+	mockJournalEntryRepo.AssertNotCalled(t, "GetEntries", mock.Anything, mock.Anything)
+}
+
+func TestJournalEntryUsecase_GetUserStatement(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "brandy345", Balance: 2000})
+	migration.InsertJournalTransactionRecord(db, domain.NewJournalTransaction("WLT-1", "Wallet transfer").
+		Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 300).
+		Credit(domain.WalletAccountID(2, domain.DefaultCurrency), 300))
+	migration.InsertJournalTransactionRecord(db, domain.NewJournalTransaction("WLT-2", "Wallet top-up").
+		Debit(domain.FundingClearingAccountID, 500).
+		Credit(domain.WalletAccountID(1, domain.DefaultCurrency), 500))
+	migration.InsertJournalTransactionRecord(db, domain.NewJournalTransaction("WLT-3", "Balance disbursement").
+		Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 200).
+		Credit(domain.BankClearingAccountID, 200))
+
+	usecase := usecase.NewJournalEntryUsecase(repository.NewTransactionManager(db), repository.NewUserBalanceRepository(db), repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)))
+
+	// the postings of the other wallet do not show up, and every page continues the
+	// running balance of the previous one
+	var folios []string
+	var runningBalances []int64
+	filter := &domain.JournalEntryFilter{Limit: 2}
+	for page := 0; ; page++ {
+		statement, err := usecase.GetUserStatement(ctx, 1, filter)
+		assert.NoError(t, err)
+		assert.Equal(t, "wallet:1", statement.AccountID)
+
+		for _, line := range statement.Lines {
+			folios = append(folios, line.Folio)
+			runningBalances = append(runningBalances, line.RunningBalance)
+		}
+		if page == 1 {
+			assert.Equal(t, int64(700), statement.OpeningBalance)
+			assert.Equal(t, int64(1000), statement.ClosingBalance)
+		}
+
+		if statement.NextCursor == 0 {
+			break
+		}
+		filter.Cursor = statement.NextCursor
+	}
+
+	assert.Equal(t, []string{"OPENING-1", "WLT-1", "WLT-2", "WLT-3"}, folios)
+	assert.Equal(t, []int64{1000, 700, 1200, 1000}, runningBalances)
+
+	statement, err := usecase.GetUserStatement(ctx, 1, &domain.JournalEntryFilter{From: time.Now().Add(-time.Hour), To: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+	assert.Len(t, statement.Lines, 4)
+
+	// a date range after every posting leaves the statement empty
+	statement, err = usecase.GetUserStatement(ctx, 1, &domain.JournalEntryFilter{From: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+	assert.Empty(t, statement.Lines)
+}
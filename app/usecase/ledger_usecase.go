@@ -0,0 +1,194 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type LedgerUsecase struct {
+	transactionManager            domain.TransactionManager
+	userBalanceRepository         domain.UserBalanceRepository
+	journalEntryRepository        domain.JournalEntryRepository
+	balanceVerificationRepository domain.BalanceVerificationRepository
+}
+
+func NewLedgerUsecase(transactionManager domain.TransactionManager, userBalanceRepository domain.UserBalanceRepository, journalEntryRepository domain.JournalEntryRepository, balanceVerificationRepository domain.BalanceVerificationRepository) domain.LedgerUsecase {
+	return &LedgerUsecase{
+		transactionManager:            transactionManager,
+		userBalanceRepository:         userBalanceRepository,
+		journalEntryRepository:        journalEntryRepository,
+		balanceVerificationRepository: balanceVerificationRepository,
+	}
+}
+
+func (u *LedgerUsecase) GetAccountBalance(ctx context.Context, accountID string) (*domain.LedgerAccountBalance, error) {
+	accountBalance, err := u.journalEntryRepository.GetAccountBalance(ctx, accountID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrLedgerAccountNotFound
+		}
+
+		return nil, err
+	}
+
+	return accountBalance, nil
+}
+
+// VerifyWalletBalances compares the stored balance of every wallet with the balance derived
+// from the postings to its ledger account, and logs and reports every wallet that differs. A
+// report with mismatches is stored, so drift found by a scheduled run can be looked up later.
+func (u *LedgerUsecase) VerifyWalletBalances(ctx context.Context) (*domain.BalanceVerificationReport, error) {
+	var userBalances []*domain.UserBalance
+	var accountBalances []*domain.LedgerAccountBalance
+
+	// both are read in one transaction, so a disbursement that is posted in between does
+	// not show up as a mismatch
+	err := u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if userBalances, err = u.userBalanceRepository.GetAll(txCtx); err != nil {
+			return err
+		}
+
+		accountBalances, err = u.journalEntryRepository.GetAccountBalances(txCtx)
+		return err
+	})
+	if err != nil {
+		log.Println("[VerifyWalletBalances] read balances err:", err)
+		return nil, err
+	}
+
+	ledgerBalances := make(map[string]int64, len(accountBalances))
+	for _, accountBalance := range accountBalances {
+		ledgerBalances[accountBalance.AccountID] = accountBalance.Balance
+	}
+
+	report := &domain.BalanceVerificationReport{
+		CheckedAt:      time.Now().UTC(),
+		WalletsChecked: len(userBalances),
+		Mismatches:     []*domain.BalanceMismatch{},
+	}
+	for _, userBalance := range userBalances {
+		accountID := domain.WalletAccountID(userBalance.UserID, userBalance.Currency)
+		ledgerBalance := ledgerBalances[accountID]
+		if userBalance.Balance == ledgerBalance {
+			continue
+		}
+
+		mismatch := &domain.BalanceMismatch{
+			UserID:        userBalance.UserID,
+			AccountID:     accountID,
+			WalletBalance: userBalance.Balance,
+			LedgerBalance: ledgerBalance,
+			Difference:    userBalance.Balance - ledgerBalance,
+		}
+		log.Printf("[VerifyWalletBalances] balance mismatch user_id=%d wallet=%d ledger=%d difference=%d", mismatch.UserID, mismatch.WalletBalance, mismatch.LedgerBalance, mismatch.Difference)
+		report.Mismatches = append(report.Mismatches, mismatch)
+	}
+
+	if len(report.Mismatches) == 0 {
+		return report, nil
+	}
+
+	err = u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		_, err := u.balanceVerificationRepository.Create(txCtx, report)
+		return err
+	})
+	if err != nil {
+		log.Println("[VerifyWalletBalances] store report err:", err)
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// GetBalanceVerifications returns the stored reports with their mismatches, newest first.
+func (u *LedgerUsecase) GetBalanceVerifications(ctx context.Context) ([]*domain.BalanceVerificationReport, error) {
+	reports, err := u.balanceVerificationRepository.GetAll(ctx)
+	if err != nil {
+		log.Println("[GetBalanceVerifications] get reports err:", err)
+		return nil, err
+	}
+
+	for _, report := range reports {
+		if report.Mismatches, err = u.balanceVerificationRepository.GetMismatches(ctx, report.ID); err != nil {
+			log.Println("[GetBalanceVerifications] get mismatches err:", err)
+			return nil, err
+		}
+	}
+
+	return reports, nil
+}
+
+const hashChainPageLimit = 500
+
+// VerifyHashChain walks the journal in posting order and recomputes the hash of every entry,
+// reporting the first entry that was changed or does not link to the entry before it. A
+// non-empty checkpoint is the hash of an entry reported by an earlier walk; a chain without
+// it lost its end since then.
+func (u *LedgerUsecase) VerifyHashChain(ctx context.Context, checkpoint string) (*domain.HashChainReport, error) {
+	report := &domain.HashChainReport{
+		CheckedAt: time.Now().UTC(),
+		Valid:     true,
+		LastHash:  domain.GenesisHash,
+	}
+	checkpointFound := checkpoint == "" || checkpoint == domain.GenesisHash
+
+	filter := &domain.JournalEntryFilter{Limit: hashChainPageLimit}
+	for {
+		entries, err := u.journalEntryRepository.GetEntries(ctx, filter)
+		if err != nil {
+			log.Println("[VerifyHashChain] get entries err:", err)
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			report.EntriesChecked++
+
+			if entry.PreviousHash != report.LastHash {
+				report.FirstBreak = &domain.HashChainBreak{
+					EntryID:      entry.ID,
+					Reason:       domain.HashChainBreakPreviousHash,
+					ExpectedHash: report.LastHash,
+					ActualHash:   entry.PreviousHash,
+				}
+			} else if hash := entry.ComputeHash(); entry.Hash != hash {
+				report.FirstBreak = &domain.HashChainBreak{
+					EntryID:      entry.ID,
+					Reason:       domain.HashChainBreakHash,
+					ExpectedHash: hash,
+					ActualHash:   entry.Hash,
+				}
+			}
+
+			if report.FirstBreak != nil {
+				report.Valid = false
+				log.Printf("[VerifyHashChain] hash chain broken entry_id=%d reason=%s", report.FirstBreak.EntryID, report.FirstBreak.Reason)
+				return report, nil
+			}
+			report.LastHash = entry.Hash
+			checkpointFound = checkpointFound || entry.Hash == checkpoint
+		}
+
+		if len(entries) < filter.Limit {
+			break
+		}
+		filter.Cursor = entries[len(entries)-1].ID
+	}
+
+	if !checkpointFound {
+		report.Valid = false
+		report.FirstBreak = &domain.HashChainBreak{
+			Reason:       domain.HashChainBreakCheckpoint,
+			ExpectedHash: checkpoint,
+			ActualHash:   report.LastHash,
+		}
+		log.Printf("[VerifyHashChain] hash chain broken checkpoint=%s reason=%s", checkpoint, report.FirstBreak.Reason)
+	}
+
+	return report, nil
+}
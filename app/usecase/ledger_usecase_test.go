@@ -0,0 +1,297 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/domain/mocks"
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database/migration"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLedgerUsecase_GetAccountBalance(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+		usecase := usecase.NewLedgerUsecase(nil, nil, mockJournalEntryRepo, nil)
+
+		expected := &domain.LedgerAccountBalance{AccountID: "wallet:1", NormalBalance: domain.NormalBalanceCredit, TotalCredit: 1000, Balance: 1000}
+		mockJournalEntryRepo.On("GetAccountBalance", ctx, "wallet:1").Return(expected, nil)
+
+		result, err := usecase.GetAccountBalance(ctx, "wallet:1")
+		assert.NoError(t, err)
+		assert.Equal(t, expected, result)
+
+		mockJournalEntryRepo.AssertExpectations(t)
+	})
+
+	t.Run("AccountNotFound", func(t *testing.T) {
+		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+		usecase := usecase.NewLedgerUsecase(nil, nil, mockJournalEntryRepo, nil)
+
+		mockJournalEntryRepo.On("GetAccountBalance", ctx, "wallet:99").Return(nil, sql.ErrNoRows)
+
+		_, err := usecase.GetAccountBalance(ctx, "wallet:99")
+		assert.ErrorIs(t, err, domErr.ErrLedgerAccountNotFound)
+
+		mockJournalEntryRepo.AssertExpectations(t)
+	})
+}
+
+func TestLedgerUsecase_VerifyWalletBalances(t *testing.T) {
+	ctx := context.Background()
+	inTransaction := func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+
+	type mockSet struct {
+		userBalanceRepo         *mocks.UserBalanceRepository
+		journalEntryRepo        *mocks.JournalEntryRepository
+		balanceVerificationRepo *mocks.BalanceVerificationRepository
+		transactionManager      *mocks.TransactionManager
+	}
+	newUsecase := func() (domain.LedgerUsecase, *mockSet) {
+		m := &mockSet{
+			userBalanceRepo:         new(mocks.UserBalanceRepository),
+			journalEntryRepo:        new(mocks.JournalEntryRepository),
+			balanceVerificationRepo: new(mocks.BalanceVerificationRepository),
+			transactionManager:      new(mocks.TransactionManager),
+		}
+		m.transactionManager.On("WithinTransaction", ctx, mock.Anything).Return(inTransaction)
+
+		return usecase.NewLedgerUsecase(m.transactionManager, m.userBalanceRepo, m.journalEntryRepo, m.balanceVerificationRepo), m
+	}
+	accountBalances := []*domain.LedgerAccountBalance{
+		{AccountID: domain.BankClearingAccountID, Balance: -400},
+		{AccountID: "wallet:1", Balance: 600},
+		{AccountID: "wallet:2", Balance: 1500},
+	}
+
+	t.Run("AllMatch", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("GetAll", ctx).Return([]*domain.UserBalance{{ID: 1, UserID: 1, Currency: domain.DefaultCurrency, Balance: 600}, {ID: 2, UserID: 2, Currency: domain.DefaultCurrency, Balance: 1500}}, nil)
+		m.journalEntryRepo.On("GetAccountBalances", ctx).Return(accountBalances, nil)
+
+		report, err := usecase.VerifyWalletBalances(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.WalletsChecked)
+		assert.Empty(t, report.Mismatches)
+
+		m.balanceVerificationRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Mismatches", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("GetAll", ctx).Return([]*domain.UserBalance{
+			{ID: 1, UserID: 1, Currency: domain.DefaultCurrency, Balance: 600},
+			{ID: 2, UserID: 2, Currency: domain.DefaultCurrency, Balance: 1200},
+			// no posting was ever made to the account of this wallet
+			{ID: 3, UserID: 3, Currency: domain.DefaultCurrency, Balance: 50},
+		}, nil)
+		m.journalEntryRepo.On("GetAccountBalances", ctx).Return(accountBalances, nil)
+		m.balanceVerificationRepo.On("Create", ctx, mock.AnythingOfType("*domain.BalanceVerificationReport")).
+			Return(func(ctx context.Context, report *domain.BalanceVerificationReport) (*domain.BalanceVerificationReport, error) {
+				report.ID = 1
+				return report, nil
+			})
+
+		report, err := usecase.VerifyWalletBalances(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), report.ID)
+		assert.Equal(t, 3, report.WalletsChecked)
+		assert.Equal(t, []*domain.BalanceMismatch{
+			{UserID: 2, AccountID: "wallet:2", WalletBalance: 1200, LedgerBalance: 1500, Difference: -300},
+			{UserID: 3, AccountID: "wallet:3", WalletBalance: 50, LedgerBalance: 0, Difference: 50},
+		}, report.Mismatches)
+
+		m.balanceVerificationRepo.AssertExpectations(t)
+	})
+
+	t.Run("StoreError", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("GetAll", ctx).Return([]*domain.UserBalance{{ID: 2, UserID: 2, Currency: domain.DefaultCurrency, Balance: 1200}}, nil)
+		m.journalEntryRepo.On("GetAccountBalances", ctx).Return(accountBalances, nil)
+		m.balanceVerificationRepo.On("Create", ctx, mock.Anything).Return(nil, errors.New("insert error"))
+
+		_, err := usecase.VerifyWalletBalances(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("ReadError", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("GetAll", ctx).Return(nil, errors.New("query error"))
+
+		_, err := usecase.VerifyWalletBalances(ctx)
+		assert.Error(t, err)
+
+		m.journalEntryRepo.AssertNotCalled(t, "GetAccountBalances", mock.Anything)
+	})
+}
+
+func TestLedgerUsecase_VerifyWalletBalances_DetectsDrift(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "brandy345", Balance: 2000})
+	usecase := usecase.NewLedgerUsecase(repository.NewTransactionManager(db), repository.NewUserBalanceRepository(db), repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)), repository.NewBalanceVerificationRepository(db))
+
+	report, err := usecase.VerifyWalletBalances(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Mismatches)
+
+	// a balance changed without a journal posting
+	db.MustExec(`UPDATE user_balances SET balance = balance + 250 WHERE id = 2`)
+
+	report, err = usecase.VerifyWalletBalances(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []*domain.BalanceMismatch{
+		{ID: 1, VerificationID: 1, UserID: 2, AccountID: "wallet:2", WalletBalance: 2250, LedgerBalance: 2000, Difference: 250},
+	}, report.Mismatches)
+
+	// only the run that found the drift is stored
+	reports, err := usecase.GetBalanceVerifications(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, reports, 1)
+	assert.Equal(t, int64(1), reports[0].ID)
+	assert.Equal(t, 2, reports[0].WalletsChecked)
+	assert.Equal(t, report.Mismatches, reports[0].Mismatches)
+}
+
+func TestLedgerUsecase_VerifyHashChain(t *testing.T) {
+	ctx := context.Background()
+
+	newLedger := func(t *testing.T) (*sqlx.DB, domain.LedgerUsecase) {
+		db := newTestDB(t)
+		migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+		migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "brandy345", Balance: 2000})
+		migration.InsertJournalTransactionRecord(db, domain.NewJournalTransaction("WLT-1", "Wallet transfer").
+			Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 300).
+			Credit(domain.WalletAccountID(2, domain.DefaultCurrency), 300).
+			WithMetadata("from_user_id", "1"))
+
+		return db, usecase.NewLedgerUsecase(repository.NewTransactionManager(db), repository.NewUserBalanceRepository(db), repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)), repository.NewBalanceVerificationRepository(db))
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		db, usecase := newLedger(t)
+
+		report, err := usecase.VerifyHashChain(ctx, "")
+		assert.NoError(t, err)
+		assert.True(t, report.Valid)
+		assert.Equal(t, 6, report.EntriesChecked)
+		assert.Nil(t, report.FirstBreak)
+
+		var lastHash string
+		assert.NoError(t, db.Get(&lastHash, `SELECT hash FROM journal_entries ORDER BY id DESC LIMIT 1`))
+		assert.Equal(t, lastHash, report.LastHash)
+	})
+
+	t.Run("EditedEntry", func(t *testing.T) {
+		db, usecase := newLedger(t)
+		db.MustExec(`UPDATE journal_entries SET debit_amount = 30 WHERE id = 5`)
+
+		report, err := usecase.VerifyHashChain(ctx, "")
+		assert.NoError(t, err)
+		assert.False(t, report.Valid)
+		assert.Equal(t, int64(5), report.FirstBreak.EntryID)
+		assert.Equal(t, domain.HashChainBreakHash, report.FirstBreak.Reason)
+	})
+
+	t.Run("DeletedEntry", func(t *testing.T) {
+		db, usecase := newLedger(t)
+		db.MustExec(`DELETE FROM journal_entries WHERE id = 3`)
+
+		report, err := usecase.VerifyHashChain(ctx, "")
+		assert.NoError(t, err)
+		assert.False(t, report.Valid)
+		assert.Equal(t, int64(4), report.FirstBreak.EntryID)
+		assert.Equal(t, domain.HashChainBreakPreviousHash, report.FirstBreak.Reason)
+		assert.Equal(t, 3, report.EntriesChecked)
+	})
+
+	t.Run("Checkpoint", func(t *testing.T) {
+		db, usecase := newLedger(t)
+
+		var checkpoint string
+		assert.NoError(t, db.Get(&checkpoint, `SELECT hash FROM journal_entries WHERE id = 4`))
+
+		report, err := usecase.VerifyHashChain(ctx, checkpoint)
+		assert.NoError(t, err)
+		assert.True(t, report.Valid)
+		assert.Nil(t, report.FirstBreak)
+	})
+
+	t.Run("TruncatedChain", func(t *testing.T) {
+		db, usecase := newLedger(t)
+
+		var checkpoint string
+		assert.NoError(t, db.Get(&checkpoint, `SELECT hash FROM journal_entries ORDER BY id DESC LIMIT 1`))
+		db.MustExec(`DELETE FROM journal_entries WHERE id >= 5`)
+
+		// without the checkpoint the shorter chain looks valid
+		report, err := usecase.VerifyHashChain(ctx, "")
+		assert.NoError(t, err)
+		assert.True(t, report.Valid)
+
+		report, err = usecase.VerifyHashChain(ctx, checkpoint)
+		assert.NoError(t, err)
+		assert.False(t, report.Valid)
+		assert.Equal(t, 4, report.EntriesChecked)
+		assert.Equal(t, domain.HashChainBreakCheckpoint, report.FirstBreak.Reason)
+		assert.Equal(t, checkpoint, report.FirstBreak.ExpectedHash)
+		assert.Equal(t, report.LastHash, report.FirstBreak.ActualHash)
+	})
+
+	t.Run("ForkIsRejected", func(t *testing.T) {
+		db, _ := newLedger(t)
+
+		// a second entry linking to the same predecessor as the last one
+		var previousHash string
+		assert.NoError(t, db.Get(&previousHash, `SELECT previous_hash FROM journal_entries ORDER BY id DESC LIMIT 1`))
+		_, err := db.Exec(`INSERT INTO journal_entries (account_id, currency, transaction_group_id, posted_at, value_date, previous_hash, hash)
+		VALUES ('suspense', 'IDR', 'X', CURRENT_TIMESTAMP, CURRENT_DATE, ?, 'forged')`, previousHash)
+		assert.ErrorContains(t, err, "UNIQUE constraint failed: journal_entries.previous_hash")
+	})
+}
+
+func TestLedgerUsecase_VerifyHashChain_Pages(t *testing.T) {
+	ctx := context.Background()
+
+	// a valid chain one entry longer than a page
+	var entries []*domain.JournalEntry
+	previousHash := domain.GenesisHash
+	for id := int64(1); id <= 501; id++ {
+		entry := &domain.JournalEntry{ID: id, AccountID: domain.SuspenseAccountID, DebitAmount: id, PreviousHash: previousHash}
+		entry.Hash = entry.ComputeHash()
+		entries = append(entries, entry)
+		previousHash = entry.Hash
+	}
+
+	mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+	usecase := usecase.NewLedgerUsecase(nil, nil, mockJournalEntryRepo, nil)
+
+	mockJournalEntryRepo.On("GetEntries", ctx, &domain.JournalEntryFilter{Limit: 500}).Return(entries[:500], nil).Once()
+	mockJournalEntryRepo.On("GetEntries", ctx, &domain.JournalEntryFilter{Cursor: 500, Limit: 500}).Return(entries[500:], nil).Once()
+
+	report, err := usecase.VerifyHashChain(ctx, "")
+	assert.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, 501, report.EntriesChecked)
+	assert.Equal(t, previousHash, report.LastHash)
+
+	mockJournalEntryRepo.AssertExpectations(t)
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type ReconciliationUsecase struct {
+	reconciliationRepository domain.ReconciliationRepository
+	disbursementRepository   domain.DisbursementRepository
+	journalEntryRepository   domain.JournalEntryRepository
+}
+
+func NewReconciliationUsecase(reconciliationRepository domain.ReconciliationRepository, disbursementRepository domain.DisbursementRepository, journalEntryRepository domain.JournalEntryRepository) domain.ReconciliationUsecase {
+	return &ReconciliationUsecase{
+		reconciliationRepository: reconciliationRepository,
+		disbursementRepository:   disbursementRepository,
+		journalEntryRepository:   journalEntryRepository,
+	}
+}
+
+// ReconcileSettlement matches the lines of a settlement file against our records and stores
+// the result. A line references a disbursement by our reference ID or the partner's ID, or
+// any other journal by its folio; a disbursement only counts as recorded once it completed.
+// Every disbursement completed on the settlement date that no line matched is added as
+// missing at the bank.
+func (u *ReconciliationUsecase) ReconcileSettlement(ctx context.Context, request *domain.ReconcileSettlementRequest, fileName string, file io.Reader) (*domain.Reconciliation, error) {
+	if request == nil || request.SettlementDate.IsZero() {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	settlementLines, err := domain.ParseSettlementFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	reconciliation := &domain.Reconciliation{
+		FileName:       fileName,
+		Provider:       request.Provider,
+		SettlementDate: request.SettlementDate,
+	}
+
+	// the line each folio was matched by, a folio can only be settled once
+	matchedFolios := map[string]int{}
+	for _, settlementLine := range settlementLines {
+		record, note, err := u.findRecord(ctx, settlementLine)
+		if err != nil {
+			return nil, err
+		}
+
+		if record != nil {
+			if lineNo, ok := matchedFolios[record.Folio]; ok {
+				record = nil
+				note = fmt.Sprintf("duplicate of line %d", lineNo)
+			} else {
+				matchedFolios[record.Folio] = settlementLine.LineNo
+			}
+		}
+
+		reconciliationLine := domain.MatchSettlementLine(settlementLine, record)
+		if note != "" {
+			reconciliationLine.Note = note
+		}
+		reconciliation.AddLine(reconciliationLine)
+	}
+
+	from := request.SettlementDate
+	completed, err := u.disbursementRepository.GetCompletedBetween(ctx, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		log.Println("[ReconcileSettlement] GetCompletedBetween err:", err)
+		return nil, err
+	}
+	for _, disbursement := range completed {
+		if request.Provider != "" && disbursement.Provider != request.Provider {
+			continue
+		}
+		if _, ok := matchedFolios[disbursement.ReferenceID]; ok {
+			continue
+		}
+
+		reconciliation.AddLine(domain.NewMissingAtBankLine(disbursement.ReferenceID, &domain.ReconciliationRecord{
+			Folio:    disbursement.ReferenceID,
+			Amount:   disbursement.Amount,
+			Currency: disbursement.Currency,
+		}))
+	}
+
+	createdReconciliation, err := u.reconciliationRepository.Create(ctx, reconciliation)
+	if err != nil {
+		log.Println("[ReconcileSettlement] Create err:", err)
+		return nil, err
+	}
+
+	return createdReconciliation, nil
+}
+
+// findRecord returns what we recorded for settlementLine, or nil with the reason in note when
+// we have not recorded it.
+func (u *ReconciliationUsecase) findRecord(ctx context.Context, settlementLine *domain.SettlementLine) (record *domain.ReconciliationRecord, note string, err error) {
+	disbursement, err := u.disbursementRepository.GetByReferenceID(ctx, settlementLine.Reference)
+	if err == sql.ErrNoRows {
+		disbursement, err = u.disbursementRepository.GetByPartnerDisbursementID(ctx, settlementLine.Reference)
+	}
+	switch {
+	case err == nil:
+		if disbursement.Status != domain.DisbursementStatusCompleted && disbursement.Status != domain.DisbursementStatusReversed {
+			return nil, fmt.Sprintf("disbursement %s is %s", disbursement.ReferenceID, disbursement.Status), nil
+		}
+
+		return &domain.ReconciliationRecord{
+			Folio:    disbursement.ReferenceID,
+			Amount:   disbursement.Amount,
+			Currency: disbursement.Currency,
+		}, "", nil
+	case err != sql.ErrNoRows:
+		log.Println("[ReconcileSettlement] get disbursement err:", err)
+		return nil, "", err
+	}
+
+	entries, err := u.journalEntryRepository.GetEntries(ctx, &domain.JournalEntryFilter{Folio: settlementLine.Reference})
+	if err != nil {
+		log.Println("[ReconcileSettlement] GetEntries err:", err)
+		return nil, "", err
+	}
+	if len(entries) == 0 {
+		return nil, "", nil
+	}
+
+	// a journal moves the same amount on both sides, per currency when it converts
+	currency := entries[0].Currency
+	for _, entry := range entries {
+		if entry.Currency == settlementLine.Currency {
+			currency = settlementLine.Currency
+			break
+		}
+	}
+	record = &domain.ReconciliationRecord{Folio: settlementLine.Reference, Currency: currency}
+	for _, entry := range entries {
+		if entry.Currency == currency {
+			record.Amount += entry.DebitAmount
+		}
+	}
+
+	return record, "", nil
+}
+
+func (u *ReconciliationUsecase) GetReconciliations(ctx context.Context) ([]*domain.Reconciliation, error) {
+	reconciliations, err := u.reconciliationRepository.GetAll(ctx)
+	if err != nil {
+		log.Println("[GetReconciliations] GetAll err:", err)
+		return nil, err
+	}
+
+	return reconciliations, nil
+}
+
+// GetReconciliation returns a reconciliation with all its lines.
+func (u *ReconciliationUsecase) GetReconciliation(ctx context.Context, id int64) (*domain.Reconciliation, error) {
+	reconciliation, err := u.reconciliationRepository.GetByID(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrReconciliationNotFound
+		}
+
+		log.Println("[GetReconciliation] GetByID err:", err)
+		return nil, err
+	}
+
+	reconciliation.Lines, err = u.reconciliationRepository.GetLines(ctx, reconciliation.ID)
+	if err != nil {
+		log.Println("[GetReconciliation] GetLines err:", err)
+		return nil, err
+	}
+
+	return reconciliation, nil
+}
@@ -0,0 +1,129 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/external"
+	extMocks "github.com/krisdioles/ppr-wallet/app/external/mocks"
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database/migration"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReconciliationUsecase_ReconcileSettlement(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+
+	// the bank pays out the first three right away and the last one later
+	bankResponse := func(partnerID, status string) *external.Bank1CreateDisbursementResponse {
+		return &external.Bank1CreateDisbursementResponse{
+			Status: "ok",
+			Data:   external.Bank1CreateDisbursementResponseData{ID: partnerID, Status: status},
+		}
+	}
+	bank1Client := new(extMocks.IBank1Client)
+	bank1Client.On("CreateDisbursement", mock.Anything, mock.Anything).Return(bankResponse("bank-1", external.Bank1DisbursementStatusCompleted), nil).Once()
+	bank1Client.On("CreateDisbursement", mock.Anything, mock.Anything).Return(bankResponse("bank-2", external.Bank1DisbursementStatusCompleted), nil).Once()
+	bank1Client.On("CreateDisbursement", mock.Anything, mock.Anything).Return(bankResponse("bank-3", external.Bank1DisbursementStatusCompleted), nil).Once()
+	bank1Client.On("CreateDisbursement", mock.Anything, mock.Anything).Return(bankResponse("bank-4", external.Bank1DisbursementStatusPending), nil).Once()
+	walletUsecase := newWalletUsecase(db, bank1Client)
+
+	var disbursements []*domain.Disbursement
+	for _, amount := range []int64{300, 200, 100, 50} {
+		disbursement, err := walletUsecase.DisburseBalance(ctx, 1, &domain.DisburseBalanceRequest{Amount: amount})
+		assert.NoError(t, err)
+		disbursements = append(disbursements, disbursement)
+	}
+	topUp, err := walletUsecase.TopUpBalance(ctx, 1, &domain.TopUpBalanceRequest{Amount: 500, SourceReference: "va-1"})
+	assert.NoError(t, err)
+
+	reconciliationUsecase := usecase.NewReconciliationUsecase(
+		repository.NewReconciliationRepository(db),
+		repository.NewDisbursementRepository(db),
+		repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)),
+	)
+	settlementDate := time.Now().UTC().Truncate(24 * time.Hour)
+
+	file := "reference,amount,currency\n" +
+		fmt.Sprintf("%s,300,IDR\n", disbursements[0].ReferenceID) +
+		"bank-2,250,IDR\n" +
+		"bank-4,50,IDR\n" +
+		fmt.Sprintf("%s,500,IDR\n", topUp.ReferenceID) +
+		"UNKNOWN-1,70,IDR\n" +
+		"bank-1,300,IDR\n"
+
+	reconciliation, err := reconciliationUsecase.ReconcileSettlement(ctx, &domain.ReconcileSettlementRequest{SettlementDate: settlementDate}, "settlement.csv", strings.NewReader(file))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reconciliation.Matched)
+	assert.Equal(t, 1, reconciliation.AmountMismatch)
+	assert.Equal(t, 3, reconciliation.MissingOnOurSide)
+	assert.Equal(t, 1, reconciliation.MissingAtBank)
+
+	stored, err := reconciliationUsecase.GetReconciliation(ctx, reconciliation.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "settlement.csv", stored.FileName)
+
+	type expectedLine struct {
+		lineNo    int
+		status    domain.ReconciliationLineStatus
+		folio     string
+		ourAmount int64
+		note      string
+	}
+	expectedLines := []expectedLine{
+		{2, domain.ReconciliationLineStatusMatched, disbursements[0].ReferenceID, 300, ""},
+		{3, domain.ReconciliationLineStatusAmountMismatch, disbursements[1].ReferenceID, 200, ""},
+		{4, domain.ReconciliationLineStatusMissingOnOurSide, "", 0, fmt.Sprintf("disbursement %s is SUBMITTED", disbursements[3].ReferenceID)},
+		{5, domain.ReconciliationLineStatusMatched, topUp.ReferenceID, 500, ""},
+		{6, domain.ReconciliationLineStatusMissingOnOurSide, "", 0, ""},
+		{7, domain.ReconciliationLineStatusMissingOnOurSide, "", 0, "duplicate of line 2"},
+		{0, domain.ReconciliationLineStatusMissingAtBank, disbursements[2].ReferenceID, 100, ""},
+	}
+	if assert.Len(t, stored.Lines, len(expectedLines)) {
+		for i, expected := range expectedLines {
+			line := stored.Lines[i]
+			assert.Equal(t, expected, expectedLine{line.LineNo, line.Status, line.Folio, line.OurAmount, line.Note}, "line %d", i)
+		}
+	}
+
+	t.Run("OtherProvider", func(t *testing.T) {
+		// the disbursements went out with bank1, none is expected in the file of another provider
+		reconciliation, err := reconciliationUsecase.ReconcileSettlement(ctx, &domain.ReconcileSettlementRequest{SettlementDate: settlementDate, Provider: "bank2"}, "bank2.csv", strings.NewReader("reference,amount\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, reconciliation.MissingAtBank)
+		assert.Empty(t, reconciliation.Lines)
+	})
+
+	t.Run("OtherDay", func(t *testing.T) {
+		reconciliation, err := reconciliationUsecase.ReconcileSettlement(ctx, &domain.ReconcileSettlementRequest{SettlementDate: settlementDate.AddDate(0, 0, -1)}, "yesterday.csv", strings.NewReader("reference,amount\n"))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, reconciliation.MissingAtBank)
+	})
+
+	t.Run("InvalidFile", func(t *testing.T) {
+		_, err := reconciliationUsecase.ReconcileSettlement(ctx, &domain.ReconcileSettlementRequest{SettlementDate: settlementDate}, "broken.csv", strings.NewReader("reference,amount\nWLT-1,abc\n"))
+		assert.ErrorIs(t, err, domErr.ErrInvalidSettlementFile)
+	})
+
+	t.Run("List", func(t *testing.T) {
+		reconciliations, err := reconciliationUsecase.GetReconciliations(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, reconciliations, 3)
+		assert.Equal(t, "yesterday.csv", reconciliations[0].FileName)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := reconciliationUsecase.GetReconciliation(ctx, 99)
+		assert.ErrorIs(t, err, domErr.ErrReconciliationNotFound)
+	})
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+const oneDay = 24 * time.Hour
+
+type ReportUsecase struct {
+	journalEntryRepository     domain.JournalEntryRepository
+	accountingPeriodRepository domain.AccountingPeriodRepository
+}
+
+func NewReportUsecase(journalEntryRepository domain.JournalEntryRepository, accountingPeriodRepository domain.AccountingPeriodRepository) domain.ReportUsecase {
+	return &ReportUsecase{
+		journalEntryRepository:     journalEntryRepository,
+		accountingPeriodRepository: accountingPeriodRepository,
+	}
+}
+
+// GetTrialBalance totals the postings in currency valued up to and including asOf, today when
+// it is zero.
+func (u *ReportUsecase) GetTrialBalance(ctx context.Context, asOf time.Time, currency string) (*domain.TrialBalance, error) {
+	asOf = reportDate(asOf)
+
+	accountBalances, err := accountBalancesBefore(ctx, u.journalEntryRepository, u.accountingPeriodRepository, asOf.Add(oneDay))
+	if err != nil {
+		log.Println("[GetTrialBalance] get account balances err:", err)
+		return nil, err
+	}
+
+	return domain.NewTrialBalance(asOf, reportCurrency(currency), accountBalances), nil
+}
+
+// GetBalanceSheet reports the balances in currency valued up to and including asOf, today when
+// it is zero.
+func (u *ReportUsecase) GetBalanceSheet(ctx context.Context, asOf time.Time, currency string) (*domain.BalanceSheet, error) {
+	asOf = reportDate(asOf)
+
+	accountBalances, err := accountBalancesBefore(ctx, u.journalEntryRepository, u.accountingPeriodRepository, asOf.Add(oneDay))
+	if err != nil {
+		log.Println("[GetBalanceSheet] get account balances err:", err)
+		return nil, err
+	}
+
+	return domain.NewBalanceSheet(asOf, reportCurrency(currency), accountBalances), nil
+}
+
+// GetIncomeStatement reports the revenue and expenses in currency valued from the start of from
+// to the end of to. A zero from starts at the first posting, a zero to ends today.
+func (u *ReportUsecase) GetIncomeStatement(ctx context.Context, from, to time.Time, currency string) (*domain.IncomeStatement, error) {
+	if !from.IsZero() {
+		from = from.UTC().Truncate(oneDay)
+	}
+	to = reportDate(to)
+	if from.After(to) {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	accountBalances, err := u.journalEntryRepository.GetAccountBalancesForPeriod(ctx, from, to.Add(oneDay))
+	if err != nil {
+		log.Println("[GetIncomeStatement] get account balances err:", err)
+		return nil, err
+	}
+
+	return domain.NewIncomeStatement(from, to, reportCurrency(currency), accountBalances), nil
+}
+
+// reportDate returns the day of t in UTC, or today when t is zero.
+func reportDate(t time.Time) time.Time {
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	return t.UTC().Truncate(oneDay)
+}
+
+// reportCurrency returns currency, or the default currency when it is empty.
+func reportCurrency(currency string) string {
+	if currency == "" {
+		return domain.DefaultCurrency
+	}
+
+	return currency
+}
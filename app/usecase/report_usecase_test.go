@@ -0,0 +1,115 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/domain/mocks"
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database/migration"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReportUsecase_GetTrialBalance(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("IncludesTheWholeDay", func(t *testing.T) {
+		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+		mockAccountingPeriodRepo := new(mocks.AccountingPeriodRepository)
+		usecase := usecase.NewReportUsecase(mockJournalEntryRepo, mockAccountingPeriodRepo)
+
+		mockAccountingPeriodRepo.On("GetLatestClosed", ctx, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)).Return(nil, sql.ErrNoRows)
+		mockJournalEntryRepo.On("GetAccountBalancesForPeriod", ctx, time.Time{}, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)).
+			Return([]*domain.LedgerAccountBalance{}, nil)
+
+		trialBalance, err := usecase.GetTrialBalance(ctx, time.Date(2026, 3, 31, 15, 0, 0, 0, time.UTC), "")
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), trialBalance.AsOf)
+		assert.Equal(t, domain.DefaultCurrency, trialBalance.Currency)
+
+		mockJournalEntryRepo.AssertExpectations(t)
+	})
+
+	t.Run("QueryError", func(t *testing.T) {
+		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+		mockAccountingPeriodRepo := new(mocks.AccountingPeriodRepository)
+		usecase := usecase.NewReportUsecase(mockJournalEntryRepo, mockAccountingPeriodRepo)
+
+		mockAccountingPeriodRepo.On("GetLatestClosed", ctx, mock.Anything).Return(nil, sql.ErrNoRows)
+		mockJournalEntryRepo.On("GetAccountBalancesForPeriod", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("query error"))
+
+		_, err := usecase.GetTrialBalance(ctx, time.Time{}, domain.DefaultCurrency)
+		assert.Error(t, err)
+	})
+}
+
+func TestReportUsecase_GetIncomeStatement(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Period", func(t *testing.T) {
+		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+		usecase := usecase.NewReportUsecase(mockJournalEntryRepo, new(mocks.AccountingPeriodRepository))
+
+		mockJournalEntryRepo.On("GetAccountBalancesForPeriod", ctx, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)).
+			Return([]*domain.LedgerAccountBalance{}, nil)
+
+		_, err := usecase.GetIncomeStatement(ctx, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), domain.DefaultCurrency)
+		assert.NoError(t, err)
+
+		mockJournalEntryRepo.AssertExpectations(t)
+	})
+
+	t.Run("FromAfterTo", func(t *testing.T) {
+		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+		usecase := usecase.NewReportUsecase(mockJournalEntryRepo, new(mocks.AccountingPeriodRepository))
+
+		_, err := usecase.GetIncomeStatement(ctx, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), domain.DefaultCurrency)
+		assert.ErrorIs(t, err, domErr.ErrInvalidParameter)
+
+		mockJournalEntryRepo.AssertNotCalled(t, "GetAccountBalancesForPeriod", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestReportUsecase_ValueDates(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+
+	// a fee valued at the end of March and one valued at the start of April
+	march := domain.NewJournalTransaction("FEE-1", "Fee").
+		Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 100).
+		Credit(domain.FeeIncomeAccountID, 100)
+	march.ValueDate = time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	migration.InsertJournalTransactionRecord(db, march)
+	april := domain.NewJournalTransaction("FEE-2", "Fee").
+		Debit(domain.WalletAccountID(1, domain.DefaultCurrency), 50).
+		Credit(domain.FeeIncomeAccountID, 50)
+	april.ValueDate = time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	migration.InsertJournalTransactionRecord(db, april)
+
+	usecase := usecase.NewReportUsecase(repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)), repository.NewAccountingPeriodRepository(db))
+
+	incomeStatement, err := usecase.GetIncomeStatement(ctx, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), incomeStatement.NetIncome)
+
+	trialBalance, err := usecase.GetTrialBalance(ctx, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.True(t, trialBalance.Balanced)
+	assert.Equal(t, int64(150), trialBalance.TotalDebitBalance)
+
+	// the opening balance is valued today, after both fees
+	balanceSheet, err := usecase.GetBalanceSheet(ctx, time.Time{}, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.True(t, balanceSheet.Balanced)
+	assert.Equal(t, int64(850), balanceSheet.Liabilities.Total)
+	assert.Equal(t, int64(150), balanceSheet.CurrentEarnings)
+}
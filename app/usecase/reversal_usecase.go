@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type ReversalUsecase struct {
+	transactionManager     domain.TransactionManager
+	userBalanceRepository  domain.UserBalanceRepository
+	journalEntryRepository domain.JournalEntryRepository
+	disbursementRepository domain.DisbursementRepository
+	reversalRepository     domain.ReversalRepository
+	referenceIDGenerator   domain.ReferenceIDGenerator
+}
+
+func NewReversalUsecase(transactionManager domain.TransactionManager, userBalanceRepository domain.UserBalanceRepository, journalEntryRepository domain.JournalEntryRepository, disbursementRepository domain.DisbursementRepository, reversalRepository domain.ReversalRepository, referenceIDGenerator domain.ReferenceIDGenerator) domain.ReversalUsecase {
+	return &ReversalUsecase{
+		transactionManager:     transactionManager,
+		userBalanceRepository:  userBalanceRepository,
+		journalEntryRepository: journalEntryRepository,
+		disbursementRepository: disbursementRepository,
+		reversalRepository:     reversalRepository,
+		referenceIDGenerator:   referenceIDGenerator,
+	}
+}
+
+// ReverseJournal posts the mirror image of the journal with folio and undoes its effect on
+// the wallets it touched. A reversed disbursement is marked REVERSED as well. The reversal
+// record, the wallets and the journal commit or roll back together, and the unique original
+// folio of the record stops a second reversal of the same journal.
+func (u *ReversalUsecase) ReverseJournal(ctx context.Context, folio string, request *domain.ReverseJournalRequest) (*domain.Reversal, error) {
+	if folio == "" || request == nil || request.Reason == "" {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	var reversal *domain.Reversal
+	err := retryOnBalanceConflict(ctx, func() error {
+		return u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+			original, err := u.journalEntryRepository.GetEntries(txCtx, &domain.JournalEntryFilter{Folio: folio})
+			if err != nil {
+				log.Println("[ReverseJournal] GetEntries err:", err)
+				return err
+			}
+			if len(original) == 0 {
+				return errors.ErrJournalNotFound
+			}
+			if _, isReversal := original[0].Metadata[domain.JournalMetadataReversalOf]; isReversal {
+				return errors.ErrJournalNotReversible
+			}
+
+			createdReversal, err := u.reversalRepository.Create(txCtx, &domain.Reversal{
+				ReferenceID:        u.referenceIDGenerator.Generate(),
+				OriginalFolio:      folio,
+				TransactionGroupID: original[0].TransactionGroupID,
+				Reason:             request.Reason,
+			})
+			if err != nil {
+				log.Println("[ReverseJournal] Create reversal record err:", err)
+				return err
+			}
+
+			journal := domain.NewReversalJournal(createdReversal.ReferenceID, original).
+				WithMetadata("reason", request.Reason)
+			if err := u.restoreWallets(txCtx, journal); err != nil {
+				log.Println("[ReverseJournal] restore wallets err:", err)
+				return err
+			}
+
+			if err := u.reverseDisbursement(txCtx, folio); err != nil {
+				log.Println("[ReverseJournal] reverse disbursement err:", err)
+				return err
+			}
+
+			if err := u.journalEntryRepository.CreateTransaction(txCtx, journal); err != nil {
+				log.Println("[ReverseJournal] CreateTransaction journal err:", err)
+				return err
+			}
+			reversal = createdReversal
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+// restoreWallets applies the net postings of the reversing journal to every wallet in it,
+// lowest user id first like transfers do. Wallets are liabilities, so a credit adds to the
+// balance.
+func (u *ReversalUsecase) restoreWallets(ctx context.Context, journal *domain.JournalTransaction) error {
+	type wallet struct {
+		userID   int64
+		currency string
+	}
+
+	netCredits := map[wallet]int64{}
+	for _, entry := range journal.Entries {
+		if userID, isWallet := domain.WalletUserID(entry.AccountID); isWallet {
+			netCredits[wallet{userID, entry.Currency}] += entry.CreditAmount - entry.DebitAmount
+		}
+	}
+
+	lockOrder := make([]wallet, 0, len(netCredits))
+	for w := range netCredits {
+		lockOrder = append(lockOrder, w)
+	}
+	sort.Slice(lockOrder, func(i, j int) bool {
+		if lockOrder[i].userID != lockOrder[j].userID {
+			return lockOrder[i].userID < lockOrder[j].userID
+		}
+
+		return lockOrder[i].currency < lockOrder[j].currency
+	})
+
+	for _, w := range lockOrder {
+		netCredit := netCredits[w]
+		if netCredit == 0 {
+			continue
+		}
+
+		_, err := updateBalance(ctx, u.userBalanceRepository, w.userID, w.currency, func(userBalance *domain.UserBalance) error {
+			if netCredit > 0 {
+				return userBalance.Credit(domain.NewMoney(netCredit, w.currency))
+			}
+
+			return userBalance.Debit(domain.NewMoney(-netCredit, w.currency))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reverseDisbursement marks the disbursement posted under folio as REVERSED. Journals of
+// anything else have no disbursement and are left alone. A disbursement under manual review
+// is not reversed: the review decides first whether it completed at all.
+func (u *ReversalUsecase) reverseDisbursement(ctx context.Context, folio string) error {
+	disbursement, err := u.disbursementRepository.GetByReferenceID(ctx, folio)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+
+		return err
+	}
+
+	if disbursement.Status == domain.DisbursementStatusManualReview {
+		return errors.ErrDisbursementUnderReview
+	}
+
+	previousStatus := disbursement.Status
+	if err := disbursement.TransitionTo(domain.DisbursementStatusReversed, time.Now().UTC()); err != nil {
+		return err
+	}
+
+	return u.disbursementRepository.UpdateStatus(ctx, disbursement, previousStatus)
+}
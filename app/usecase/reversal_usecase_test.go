@@ -0,0 +1,254 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/domain/mocks"
+	extMocks "github.com/krisdioles/ppr-wallet/app/external/mocks"
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database/migration"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/krisdioles/ppr-wallet/pkg/refid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newReversalUsecase(db *sqlx.DB) domain.ReversalUsecase {
+	return usecase.NewReversalUsecase(
+		repository.NewTransactionManager(db),
+		repository.NewUserBalanceRepository(db),
+		repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)),
+		repository.NewDisbursementRepository(db),
+		repository.NewReversalRepository(db),
+		refid.NewGenerator("WLT"),
+	)
+}
+
+func TestReversalUsecase_ReverseJournal_Transfer(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "brandy345", Balance: 1000})
+
+	userBalanceRepo := repository.NewUserBalanceRepository(db)
+	journalEntryRepo := repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db))
+	transfer, err := usecase.NewTransferUsecase(repository.NewTransactionManager(db), userBalanceRepo, journalEntryRepo, repository.NewTransferRepository(db), refid.NewGenerator("WLT")).
+		TransferBalance(ctx, 2, &domain.TransferBalanceRequest{ToUserID: 1, Amount: 300})
+	assert.NoError(t, err)
+
+	reversal, err := newReversalUsecase(db).ReverseJournal(ctx, transfer.ReferenceID, &domain.ReverseJournalRequest{Reason: "sent to the wrong wallet"})
+	assert.NoError(t, err)
+	assert.Equal(t, transfer.ReferenceID, reversal.OriginalFolio)
+	assert.Equal(t, transfer.ReferenceID, reversal.TransactionGroupID)
+	assert.NotEqual(t, transfer.ReferenceID, reversal.ReferenceID)
+
+	andy, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	brandy, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 2, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), andy.Balance)
+	assert.Equal(t, int64(1000), brandy.Balance)
+
+	// the original journal is kept and the mirror image is posted in the same group
+	entries, err := journalEntryRepo.GetEntries(ctx, &domain.JournalEntryFilter{TransactionGroupID: transfer.ReferenceID})
+	assert.NoError(t, err)
+	if assert.Len(t, entries, 4) {
+		for i, original := range entries[:2] {
+			mirror := entries[2+i]
+			assert.Equal(t, reversal.ReferenceID, mirror.Folio)
+			assert.Equal(t, original.AccountID, mirror.AccountID)
+			assert.Equal(t, original.DebitAmount, mirror.CreditAmount)
+			assert.Equal(t, original.CreditAmount, mirror.DebitAmount)
+			assert.Equal(t, transfer.ReferenceID, mirror.Metadata[domain.JournalMetadataReversalOf])
+			assert.Equal(t, "sent to the wrong wallet", mirror.Metadata["reason"])
+		}
+	}
+	assertLedgerMatchesWallets(t, db)
+}
+
+func TestReversalUsecase_ReverseJournal_Disbursement(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{
+		Username:    "andy123",
+		Balance:     1000,
+		BankCode:    "arthagraha",
+		AccountNo:   "083012322138",
+		AccountName: "Andy Garcia",
+	})
+
+	bank1Client := new(extMocks.IBank1Client)
+	bank1Client.On("CreateDisbursement", mock.Anything, mock.Anything).
+		Return(completedResponse, nil)
+
+	userBalanceRepo := repository.NewUserBalanceRepository(db)
+	disbursementRepo := repository.NewDisbursementRepository(db)
+	disbursement, err := usecase.NewUserBalanceUsecase(
+		repository.NewTransactionManager(db),
+		userBalanceRepo,
+		repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)),
+		repository.NewLedgerAccountRepository(db),
+		disbursementRepo,
+		repository.NewTopUpRepository(db),
+		newPayoutRouter(bank1Client),
+		refid.NewGenerator("WLT"),
+	).DisburseBalance(ctx, 1, &domain.DisburseBalanceRequest{Amount: 400})
+	assert.NoError(t, err)
+
+	_, err = newReversalUsecase(db).ReverseJournal(ctx, disbursement.ReferenceID, &domain.ReverseJournalRequest{Reason: "returned by the bank"})
+	assert.NoError(t, err)
+
+	userBalance, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), userBalance.Balance)
+
+	reversed, err := disbursementRepo.GetByReferenceID(ctx, disbursement.ReferenceID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DisbursementStatusReversed, reversed.Status)
+	assert.NotNil(t, reversed.ReversedAt)
+	assertLedgerMatchesWallets(t, db)
+}
+
+func TestReversalUsecase_ReverseJournal_DisbursementUnderReview(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{
+		Username:    "andy123",
+		Balance:     1000,
+		BankCode:    "arthagraha",
+		AccountNo:   "083012322138",
+		AccountName: "Andy Garcia",
+	})
+
+	bank1Client := new(extMocks.IBank1Client)
+	bank1Client.On("CreateDisbursement", mock.Anything, mock.Anything).
+		Return(completedResponse, nil)
+
+	userBalanceRepo := repository.NewUserBalanceRepository(db)
+	disbursementRepo := repository.NewDisbursementRepository(db)
+	disbursement, err := usecase.NewUserBalanceUsecase(
+		repository.NewTransactionManager(db),
+		userBalanceRepo,
+		repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)),
+		repository.NewLedgerAccountRepository(db),
+		disbursementRepo,
+		repository.NewTopUpRepository(db),
+		newPayoutRouter(bank1Client),
+		refid.NewGenerator("WLT"),
+	).DisburseBalance(ctx, 1, &domain.DisburseBalanceRequest{Amount: 400})
+	assert.NoError(t, err)
+
+	// the bank reports a problem with the completed payout
+	disbursement, err = disbursementRepo.GetByReferenceID(ctx, disbursement.ReferenceID)
+	assert.NoError(t, err)
+	assert.NoError(t, disbursement.TransitionTo(domain.DisbursementStatusManualReview, time.Now().UTC()))
+	assert.NoError(t, disbursementRepo.UpdateStatus(ctx, disbursement, domain.DisbursementStatusCompleted))
+
+	reversal, err := newReversalUsecase(db).ReverseJournal(ctx, disbursement.ReferenceID, &domain.ReverseJournalRequest{Reason: "returned by the bank"})
+	assert.ErrorIs(t, err, domErr.ErrDisbursementUnderReview)
+	assert.Nil(t, reversal)
+
+	// nothing was reversed
+	userBalance, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(600), userBalance.Balance)
+
+	underReview, err := disbursementRepo.GetByReferenceID(ctx, disbursement.ReferenceID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DisbursementStatusManualReview, underReview.Status)
+	assertLedgerMatchesWallets(t, db)
+}
+
+func TestReversalUsecase_ReverseJournal_OnlyOnce(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+
+	reversalUsecase := newReversalUsecase(db)
+	reversal, err := reversalUsecase.ReverseJournal(ctx, "OPENING-1", &domain.ReverseJournalRequest{Reason: "seeded by mistake"})
+	assert.NoError(t, err)
+
+	_, err = reversalUsecase.ReverseJournal(ctx, "OPENING-1", &domain.ReverseJournalRequest{Reason: "seeded by mistake"})
+	assert.ErrorIs(t, err, domErr.ErrJournalAlreadyReversed)
+
+	_, err = reversalUsecase.ReverseJournal(ctx, reversal.ReferenceID, &domain.ReverseJournalRequest{Reason: "undo the reversal"})
+	assert.ErrorIs(t, err, domErr.ErrJournalNotReversible)
+
+	userBalance, err := repository.NewUserBalanceRepository(db).GetByUserIDAndCurrency(ctx, 1, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), userBalance.Balance)
+
+	var reversals, journalEntries int
+	assert.NoError(t, db.Get(&reversals, `SELECT COUNT(*) FROM reversals`))
+	assert.NoError(t, db.Get(&journalEntries, `SELECT COUNT(*) FROM journal_entries`))
+	assert.Equal(t, 1, reversals)
+	assert.Equal(t, 4, journalEntries)
+	assertLedgerMatchesWallets(t, db)
+}
+
+func TestReversalUsecase_ReverseJournal_InsufficientBalance(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "brandy345"})
+
+	userBalanceRepo := repository.NewUserBalanceRepository(db)
+	transferUsecase := usecase.NewTransferUsecase(repository.NewTransactionManager(db), userBalanceRepo, repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)), repository.NewTransferRepository(db), refid.NewGenerator("WLT"))
+	transfer, err := transferUsecase.TransferBalance(ctx, 1, &domain.TransferBalanceRequest{ToUserID: 2, Amount: 300})
+	assert.NoError(t, err)
+	_, err = transferUsecase.TransferBalance(ctx, 2, &domain.TransferBalanceRequest{ToUserID: 1, Amount: 100})
+	assert.NoError(t, err)
+
+	// the recipient already spent part of the money, so nothing is reversed
+	_, err = newReversalUsecase(db).ReverseJournal(ctx, transfer.ReferenceID, &domain.ReverseJournalRequest{Reason: "sent to the wrong wallet"})
+	assert.ErrorIs(t, err, domErr.ErrInsufficientBalance)
+
+	var reversals int
+	assert.NoError(t, db.Get(&reversals, `SELECT COUNT(*) FROM reversals`))
+	assert.Equal(t, 0, reversals)
+	assertLedgerMatchesWallets(t, db)
+}
+
+func TestReversalUsecase_ReverseJournal_Invalid(t *testing.T) {
+	ctx := context.Background()
+
+	newUsecase := func() (domain.ReversalUsecase, *mocks.JournalEntryRepository, *mocks.ReversalRepository) {
+		transactionManager := new(mocks.TransactionManager)
+		transactionManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).Maybe()
+		journalEntryRepo := new(mocks.JournalEntryRepository)
+		reversalRepo := new(mocks.ReversalRepository)
+
+		return usecase.NewReversalUsecase(transactionManager, new(mocks.UserBalanceRepository), journalEntryRepo, new(mocks.DisbursementRepository), reversalRepo, new(mocks.ReferenceIDGenerator)), journalEntryRepo, reversalRepo
+	}
+
+	t.Run("MissingReason", func(t *testing.T) {
+		usecase, journalEntryRepo, _ := newUsecase()
+
+		_, err := usecase.ReverseJournal(ctx, "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7", &domain.ReverseJournalRequest{})
+		assert.ErrorIs(t, err, domErr.ErrInvalidParameter)
+
+		journalEntryRepo.AssertNotCalled(t, "GetEntries", mock.Anything, mock.Anything)
+	})
+
+	t.Run("JournalNotFound", func(t *testing.T) {
+		usecase, journalEntryRepo, reversalRepo := newUsecase()
+		journalEntryRepo.On("GetEntries", ctx, &domain.JournalEntryFilter{Folio: "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7"}).Return([]*domain.JournalEntry{}, nil)
+
+		_, err := usecase.ReverseJournal(ctx, "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7", &domain.ReverseJournalRequest{Reason: "sent to the wrong wallet"})
+		assert.ErrorIs(t, err, domErr.ErrJournalNotFound)
+
+		reversalRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	"github.com/krisdioles/ppr-wallet/app/domain/errors"
+)
+
+type TransferUsecase struct {
+	transactionManager     domain.TransactionManager
+	userBalanceRepository  domain.UserBalanceRepository
+	journalEntryRepository domain.JournalEntryRepository
+	transferRepository     domain.TransferRepository
+	referenceIDGenerator   domain.ReferenceIDGenerator
+}
+
+func NewTransferUsecase(transactionManager domain.TransactionManager, userBalanceRepository domain.UserBalanceRepository, journalEntryRepository domain.JournalEntryRepository, transferRepository domain.TransferRepository, referenceIDGenerator domain.ReferenceIDGenerator) domain.TransferUsecase {
+	return &TransferUsecase{
+		transactionManager:     transactionManager,
+		userBalanceRepository:  userBalanceRepository,
+		journalEntryRepository: journalEntryRepository,
+		transferRepository:     transferRepository,
+		referenceIDGenerator:   referenceIDGenerator,
+	}
+}
+
+func (u *TransferUsecase) TransferBalance(ctx context.Context, fromUserID int64, request *domain.TransferBalanceRequest) (*domain.Transfer, error) {
+	if request == nil || request.Amount <= 0 {
+		return nil, errors.ErrInvalidParameter
+	}
+	if request.ToUserID == fromUserID {
+		return nil, errors.ErrSelfTransfer
+	}
+	amount := request.Money()
+
+	// both wallets are in the currency of the transfer and always written lowest user id first,
+	// so two opposite transfers between the same wallets take their row locks in the same order and cannot deadlock
+	mutations := map[int64]func(*domain.UserBalance) error{
+		fromUserID: func(userBalance *domain.UserBalance) error {
+			return userBalance.Debit(amount)
+		},
+		request.ToUserID: func(userBalance *domain.UserBalance) error {
+			return userBalance.Credit(amount)
+		},
+	}
+	lockOrder := []int64{fromUserID, request.ToUserID}
+	if lockOrder[1] < lockOrder[0] {
+		lockOrder[0], lockOrder[1] = lockOrder[1], lockOrder[0]
+	}
+
+	var transfer *domain.Transfer
+	err := retryOnBalanceConflict(ctx, func() error {
+		return u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+			for _, id := range lockOrder {
+				if _, err := updateBalance(txCtx, u.userBalanceRepository, id, amount.Currency, mutations[id]); err != nil {
+					log.Println("[TransferBalance] update balance err:", err)
+					if err == errors.ErrUserNotFound && id == request.ToUserID {
+						return errors.ErrRecipientNotFound
+					}
+
+					return err
+				}
+			}
+
+			createdTransfer, err := u.transferRepository.Create(txCtx, &domain.Transfer{
+				ReferenceID: u.referenceIDGenerator.Generate(),
+				FromUserID:  fromUserID,
+				ToUserID:    request.ToUserID,
+				Amount:      amount.Amount,
+				Currency:    amount.Currency,
+			})
+			if err != nil {
+				log.Println("[TransferBalance] Create transfer record err:", err)
+				return err
+			}
+
+			journal := domain.NewJournalTransaction(createdTransfer.ReferenceID, "Wallet transfer").
+				InCurrency(amount.Currency).
+				Debit(domain.WalletAccountID(fromUserID, amount.Currency), amount.Amount).
+				Credit(domain.WalletAccountID(request.ToUserID, amount.Currency), amount.Amount).
+				WithMetadata("from_user_id", strconv.FormatInt(fromUserID, 10)).
+				WithMetadata("to_user_id", strconv.FormatInt(request.ToUserID, 10))
+			if err := u.journalEntryRepository.CreateTransaction(txCtx, journal); err != nil {
+				log.Println("[TransferBalance] CreateTransaction journal err:", err)
+				return err
+			}
+			transfer = createdTransfer
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
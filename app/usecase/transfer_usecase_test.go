@@ -0,0 +1,227 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/domain/mocks"
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database/migration"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/krisdioles/ppr-wallet/pkg/refid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTransferUsecase_TransferBalance(t *testing.T) {
+	ctx := context.Background()
+	senderID := int64(2)
+	recipientID := int64(1)
+	balances := map[int64]domain.UserBalance{
+		senderID:    {ID: senderID, UserID: senderID, Currency: domain.DefaultCurrency, Balance: 1000, HeldBalance: 300},
+		recipientID: {ID: recipientID, UserID: recipientID, Currency: domain.DefaultCurrency, Balance: 50},
+	}
+	request := &domain.TransferBalanceRequest{
+		ToUserID: recipientID,
+		Amount:   600,
+	}
+	referenceID := "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7"
+	currentUserBalance := func(ctx context.Context, id int64, currency string) (*domain.UserBalance, error) {
+		current, ok := balances[id]
+		if !ok {
+			return nil, sql.ErrNoRows
+		}
+		return &current, nil
+	}
+	withBalance := func(id, balance int64) interface{} {
+		return mock.MatchedBy(func(userBalance *domain.UserBalance) bool {
+			return userBalance.ID == id && userBalance.Balance == balance
+		})
+	}
+	inTransaction := func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+
+	type mockSet struct {
+		userBalanceRepo      *mocks.UserBalanceRepository
+		journalEntryRepo     *mocks.JournalEntryRepository
+		transferRepo         *mocks.TransferRepository
+		referenceIDGenerator *mocks.ReferenceIDGenerator
+		transactionManager   *mocks.TransactionManager
+	}
+	newUsecase := func() (domain.TransferUsecase, *mockSet) {
+		m := &mockSet{
+			userBalanceRepo:      new(mocks.UserBalanceRepository),
+			journalEntryRepo:     new(mocks.JournalEntryRepository),
+			transferRepo:         new(mocks.TransferRepository),
+			referenceIDGenerator: new(mocks.ReferenceIDGenerator),
+			transactionManager:   new(mocks.TransactionManager),
+		}
+		m.transactionManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(inTransaction).Maybe()
+		m.referenceIDGenerator.On("Generate").Return(referenceID).Maybe()
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", ctx, mock.Anything, domain.DefaultCurrency).Return(currentUserBalance).Maybe()
+
+		return usecase.NewTransferUsecase(m.transactionManager, m.userBalanceRepo, m.journalEntryRepo, m.transferRepo, m.referenceIDGenerator), m
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("UpdateBalance", ctx, withBalance(recipientID, 650)).Return(nil)
+		m.userBalanceRepo.On("UpdateBalance", ctx, withBalance(senderID, 400)).Return(nil)
+		m.transferRepo.On("Create", ctx, &domain.Transfer{
+			ReferenceID: referenceID,
+			FromUserID:  senderID,
+			ToUserID:    recipientID,
+			Amount:      request.Amount,
+			Currency:    domain.DefaultCurrency,
+		}).Return(&domain.Transfer{ID: 5, ReferenceID: referenceID}, nil)
+		m.journalEntryRepo.On("CreateTransaction", ctx, domain.NewJournalTransaction(referenceID, "Wallet transfer").
+			Debit(domain.WalletAccountID(senderID, domain.DefaultCurrency), request.Amount).
+			Credit(domain.WalletAccountID(recipientID, domain.DefaultCurrency), request.Amount).
+			WithMetadata("from_user_id", "2").
+			WithMetadata("to_user_id", "1")).
+			Return(nil)
+
+		result, err := usecase.TransferBalance(ctx, senderID, request)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), result.ID)
+
+		m.userBalanceRepo.AssertExpectations(t)
+		m.transferRepo.AssertExpectations(t)
+		m.journalEntryRepo.AssertExpectations(t)
+		m.transactionManager.AssertNumberOfCalls(t, "WithinTransaction", 1)
+
+		// the wallet with the lower id is written first, whichever side of the transfer it is
+		var updatedIDs []int64
+		for _, call := range m.userBalanceRepo.Calls {
+			if call.Method == "UpdateBalance" {
+				updatedIDs = append(updatedIDs, call.Arguments.Get(1).(*domain.UserBalance).ID)
+			}
+		}
+		assert.Equal(t, []int64{recipientID, senderID}, updatedIDs)
+	})
+
+	t.Run("SelfTransfer", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		_, err := usecase.TransferBalance(ctx, senderID, &domain.TransferBalanceRequest{ToUserID: senderID, Amount: 100})
+		assert.ErrorIs(t, err, domErr.ErrSelfTransfer)
+
+		m.transactionManager.AssertNotCalled(t, "WithinTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("InvalidAmount", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		_, err := usecase.TransferBalance(ctx, senderID, &domain.TransferBalanceRequest{ToUserID: recipientID})
+		assert.ErrorIs(t, err, domErr.ErrInvalidParameter)
+
+		m.transactionManager.AssertNotCalled(t, "WithinTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("InsufficientBalance", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("UpdateBalance", ctx, withBalance(recipientID, 850)).Return(nil)
+
+		// 800 is covered by the balance but not by what is left after the hold
+		_, err := usecase.TransferBalance(ctx, senderID, &domain.TransferBalanceRequest{ToUserID: recipientID, Amount: 800})
+		assert.ErrorIs(t, err, domErr.ErrInsufficientBalance)
+
+		m.transferRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		m.journalEntryRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("RecipientNotFound", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("UpdateBalance", ctx, withBalance(senderID, 400)).Return(nil)
+
+		_, err := usecase.TransferBalance(ctx, senderID, &domain.TransferBalanceRequest{ToUserID: 99, Amount: 600})
+		assert.ErrorIs(t, err, domErr.ErrRecipientNotFound)
+
+		m.transferRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("SenderNotFound", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("UpdateBalance", ctx, withBalance(recipientID, 650)).Return(nil)
+
+		_, err := usecase.TransferBalance(ctx, 99, request)
+		assert.ErrorIs(t, err, domErr.ErrUserNotFound)
+
+		m.transferRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("JournalEntryError", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("UpdateBalance", ctx, mock.Anything).Return(nil)
+		m.transferRepo.On("Create", ctx, mock.Anything).Return(&domain.Transfer{ID: 5, ReferenceID: referenceID}, nil)
+		m.journalEntryRepo.On("CreateTransaction", ctx, mock.Anything).Return(errors.New("journal entry error"))
+
+		_, err := usecase.TransferBalance(ctx, senderID, request)
+		assert.Error(t, err)
+
+		m.journalEntryRepo.AssertNumberOfCalls(t, "CreateTransaction", 1)
+	})
+}
+
+func TestTransferUsecase_TransferBalance_Concurrent(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "brandy345", Balance: 1000})
+
+	userBalanceRepo := repository.NewUserBalanceRepository(db)
+	usecase := usecase.NewTransferUsecase(
+		repository.NewTransactionManager(db),
+		userBalanceRepo,
+		repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)),
+		repository.NewTransferRepository(db),
+		refid.NewGenerator("WLT"),
+	)
+
+	// both wallets send money to each other at the same time
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := usecase.TransferBalance(ctx, 1, &domain.TransferBalanceRequest{ToUserID: 2, Amount: 30})
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := usecase.TransferBalance(ctx, 2, &domain.TransferBalanceRequest{ToUserID: 1, Amount: 10})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	andy, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	brandy, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 2, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000-20*30+20*10), andy.Balance)
+	assert.Equal(t, int64(1000+20*30-20*10), brandy.Balance)
+
+	var journalEntries int
+	assert.NoError(t, db.Get(&journalEntries, `SELECT COUNT(*) FROM journal_entries WHERE transaction_name = 'Wallet transfer'`))
+	assert.Equal(t, 80, journalEntries)
+	assertLedgerMatchesWallets(t, db)
+}
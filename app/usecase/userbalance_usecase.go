@@ -3,31 +3,50 @@ package usecase
 import (
 	"context"
 	"database/sql"
+	stderrors "errors"
 	"log"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/krisdioles/ppr-wallet/app/domain"
 	"github.com/krisdioles/ppr-wallet/app/domain/errors"
 	"github.com/krisdioles/ppr-wallet/app/external"
-	"golang.org/x/sync/errgroup"
+	"github.com/krisdioles/ppr-wallet/pkg/httpclient"
 )
 
 type UserBalanceUsecase struct {
-	userBalanceRepository  domain.UserBalanceRepository
-	journalEntryRepository domain.JournalEntryRepository
-	bank1Client            external.IBank1Client
+	transactionManager      domain.TransactionManager
+	userBalanceRepository   domain.UserBalanceRepository
+	journalEntryRepository  domain.JournalEntryRepository
+	ledgerAccountRepository domain.LedgerAccountRepository
+	disbursementRepository  domain.DisbursementRepository
+	topUpRepository         domain.TopUpRepository
+	payoutRouter            external.PayoutRouter
+	referenceIDGenerator    domain.ReferenceIDGenerator
 }
 
-func NewUserBalanceUsecase(userBalanceRepository domain.UserBalanceRepository, journalEntryRepository domain.JournalEntryRepository, bank1Client external.IBank1Client) domain.UserBalanceUsecase {
+func NewUserBalanceUsecase(transactionManager domain.TransactionManager, userBalanceRepository domain.UserBalanceRepository, journalEntryRepository domain.JournalEntryRepository, ledgerAccountRepository domain.LedgerAccountRepository, disbursementRepository domain.DisbursementRepository, topUpRepository domain.TopUpRepository, payoutRouter external.PayoutRouter, referenceIDGenerator domain.ReferenceIDGenerator) domain.UserBalanceUsecase {
 	return &UserBalanceUsecase{
-		userBalanceRepository:  userBalanceRepository,
-		journalEntryRepository: journalEntryRepository,
-		bank1Client:            bank1Client,
+		transactionManager:      transactionManager,
+		userBalanceRepository:   userBalanceRepository,
+		journalEntryRepository:  journalEntryRepository,
+		ledgerAccountRepository: ledgerAccountRepository,
+		disbursementRepository:  disbursementRepository,
+		topUpRepository:         topUpRepository,
+		payoutRouter:            payoutRouter,
+		referenceIDGenerator:    referenceIDGenerator,
 	}
 }
 
-func (u *UserBalanceUsecase) GetUserBalanceByID(ctx context.Context, id int64) (*domain.UserBalance, error) {
-	userBalance, err := u.userBalanceRepository.GetByID(ctx, id)
+// GetUserBalanceByID returns the wallet of a user in currency, the default currency when it
+// is empty.
+func (u *UserBalanceUsecase) GetUserBalanceByID(ctx context.Context, id int64, currency string) (*domain.UserBalance, error) {
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+
+	userBalance, err := u.userBalanceRepository.GetByUserIDAndCurrency(ctx, id, currency)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return userBalance, errors.ErrUserNotFound
@@ -39,78 +58,430 @@ func (u *UserBalanceUsecase) GetUserBalanceByID(ctx context.Context, id int64) (
 	return userBalance, nil
 }
 
-func (u *UserBalanceUsecase) DisburseBalance(ctx context.Context, id int64) error {
-	currentUserBalance, err := u.userBalanceRepository.GetByID(ctx, id)
+func (u *UserBalanceUsecase) GetUserWallets(ctx context.Context, id int64) ([]*domain.UserBalance, error) {
+	userBalances, err := u.userBalanceRepository.GetAllByUserID(ctx, id)
+	if err != nil {
+		log.Println("[GetUserWallets] GetAllByUserID err:", err)
+		return nil, err
+	}
+
+	if len(userBalances) == 0 {
+		return nil, errors.ErrUserNotFound
+	}
+
+	return userBalances, nil
+}
+
+// OpenWallet opens an empty wallet in another currency for an existing user, with the bank
+// account of the user's first wallet. The ledger accounts of the wallet, and the system
+// accounts of the currency when it is the first wallet in it, are registered with it.
+func (u *UserBalanceUsecase) OpenWallet(ctx context.Context, id int64, request *domain.OpenWalletRequest) (*domain.UserBalance, error) {
+	if request == nil || request.Currency == "" {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	userBalances, err := u.GetUserWallets(ctx, id)
 	if err != nil {
-		log.Println("[DisburseBalance] GetByID err:", err)
+		return nil, err
+	}
+	firstWallet := userBalances[0]
+
+	var userBalance *domain.UserBalance
+	err = u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+		createdUserBalance, err := u.userBalanceRepository.Create(txCtx, &domain.UserBalance{
+			UserID:      id,
+			Username:    firstWallet.Username,
+			Currency:    request.Currency,
+			BankCode:    firstWallet.BankCode,
+			AccountNo:   firstWallet.AccountNo,
+			AccountName: firstWallet.AccountName,
+		})
+		if err != nil {
+			log.Println("[OpenWallet] Create wallet err:", err)
+			return err
+		}
+
+		accounts := append(domain.SystemLedgerAccounts(request.Currency), domain.NewWalletLedgerAccount(id, request.Currency, firstWallet.Username))
+		for _, account := range accounts {
+			if err := u.ledgerAccountRepository.Register(txCtx, account); err != nil {
+				log.Println("[OpenWallet] Register ledger account err:", err)
+				return err
+			}
+		}
+		userBalance = createdUserBalance
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return userBalance, nil
+}
+
+func (u *UserBalanceUsecase) DisburseBalance(ctx context.Context, id int64, request *domain.DisburseBalanceRequest) (*domain.Disbursement, error) {
+	if request == nil || request.Amount <= 0 {
+		return nil, errors.ErrInvalidParameter
+	}
+	amount := request.Money()
+
+	currentUserBalance, err := u.userBalanceRepository.GetByUserIDAndCurrency(ctx, id, amount.Currency)
+	if err != nil {
+		log.Println("[DisburseBalance] GetByUserIDAndCurrency err:", err)
 		if err == sql.ErrNoRows {
-			return errors.ErrUserNotFound
+			return nil, errors.ErrUserNotFound
 		}
 
-		return err
+		return nil, err
+	}
+
+	if currentUserBalance.AvailableBalance().Amount < amount.Amount {
+		return nil, errors.ErrInsufficientBalance
+	}
+
+	payoutProvider, err := u.payoutRouter.Route(currentUserBalance.BankCode, amount)
+	if err != nil {
+		log.Println("[DisburseBalance] Route payout provider err:", err)
+		return nil, err
 	}
 
-	if currentUserBalance.Balance <= 0 {
-		return errors.ErrInsufficientBalance
+	// reserve the funds before anything leaves the wallet, the hold is captured once the
+	// bank accepts the disbursement and released when it does not
+	var disbursement *domain.Disbursement
+	err = retryOnBalanceConflict(ctx, func() error {
+		return u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+			userBalance, err := updateBalance(txCtx, u.userBalanceRepository, id, amount.Currency, func(userBalance *domain.UserBalance) error {
+				return userBalance.Hold(amount)
+			})
+			if err != nil {
+				log.Println("[DisburseBalance] hold balance err:", err)
+				return err
+			}
+
+			createdDisbursement, err := u.disbursementRepository.Create(txCtx, &domain.Disbursement{
+				ReferenceID: u.referenceIDGenerator.Generate(),
+				UserID:      userBalance.UserID,
+				Amount:      amount.Amount,
+				Currency:    amount.Currency,
+				BankCode:    userBalance.BankCode,
+				AccountNo:   userBalance.AccountNo,
+				AccountName: userBalance.AccountName,
+				Status:      domain.DisbursementStatusPending,
+				Provider:    payoutProvider.Name(),
+			})
+			if err != nil {
+				log.Println("[DisburseBalance] Create disbursement record err:", err)
+				return err
+			}
+			disbursement = createdDisbursement
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
+	referenceID := disbursement.ReferenceID
 
 	// disburse to user's account
 	// call external api (bank/3rd party)
-	createDisbursementResp, err := u.bank1Client.CreateDisbursement(ctx, &external.Bank1CreateDisbursementRequest{
-		ReferenceID: "test-transaction-010121",
-		Amount: external.AmountObj{
-			Total:    currentUserBalance.Balance,
-			Currency: "IDR",
-		},
+	payoutResp, err := payoutProvider.CreatePayout(ctx, &external.PayoutRequest{
+		ReferenceID: referenceID,
+		Amount:      domain.Money{Amount: disbursement.Amount, Currency: disbursement.Currency},
 		Account: external.AccountObj{
-			AccountHolderName: currentUserBalance.AccountName,
-			AccountBankCode:   currentUserBalance.BankCode,
-			AccountNo:         currentUserBalance.AccountNo,
+			AccountHolderName: disbursement.AccountName,
+			AccountBankCode:   disbursement.BankCode,
+			AccountNo:         disbursement.AccountNo,
 		},
 	})
+	if err != nil && payoutNotSent(err) {
+		// the request never left, so nothing can have been paid out
+		log.Println("[DisburseBalance] Create Disbursement not sent err:", err)
+		if releaseErr := u.failDisbursement(ctx, disbursement, err.Error()); releaseErr != nil {
+			log.Println("[DisburseBalance] release hold of unsent disbursement err:", releaseErr)
+			return nil, releaseErr
+		}
+		if stderrors.Is(err, httpclient.ErrCircuitOpen) {
+			return nil, errors.ErrNoPayoutProvider
+		}
+
+		return nil, err
+	}
 	if err != nil {
+		// a timeout, a lost connection or a 5xx answer does not tell whether the provider took
+		// the payout, so the hold stays until a callback, polling or a person settles it
 		log.Println("[DisburseBalance] Create Disbursement err:", err)
-		return err
+		if err = u.transitionDisbursement(context.WithoutCancel(ctx), disbursement, domain.DisbursementStatusSubmitted); err != nil {
+			log.Println("[DisburseBalance] submit disbursement with unknown outcome err:", err)
+			return nil, err
+		}
+
+		return disbursement, nil
 	}
 
-	if createDisbursementResp.Status != "ok" {
-		return errors.ErrPartnerError
+	if !payoutResp.Accepted {
+		// a failed release leaves the disbursement pending, polling sends it to manual review
+		if err = u.failDisbursement(ctx, disbursement, payoutResp.Message); err != nil {
+			log.Println("[DisburseBalance] release hold of rejected disbursement err:", err)
+		}
+		return nil, errors.ErrPartnerError
 	}
 
-	if err = u.userBalanceRepository.UpdateBalanceByID(ctx, 0, id); err != nil {
-		log.Println("[DisburseBalance] UpdateBalanceByID err:", err)
-		return err
+	disbursement.PartnerDisbursementID = payoutResp.PartnerID
+	if err = u.transitionDisbursement(ctx, disbursement, domain.DisbursementStatusSubmitted); err != nil {
+		log.Println("[DisburseBalance] submit disbursement err:", err)
+		return nil, err
 	}
 
-	eg, egCtx := errgroup.WithContext(ctx)
+	// most payouts settle later, the provider reports the outcome through SettleDisbursement
+	if !payoutResp.Completed {
+		return disbursement, nil
+	}
 
-	eg.Go(func() error {
-		if _, err = u.journalEntryRepository.Create(egCtx, &domain.JournalEntry{
-			AccountID:       strconv.Itoa(int(currentUserBalance.ID)),
-			TransactionName: "Balance disbursement",
-			DebitAmount:     currentUserBalance.Balance,
-			Folio:           createDisbursementResp.Data.ID,
-		}); err != nil {
-			log.Println("[DisburseBalance] Create journalentry debit err:", err)
-			return err
-		}
+	if err = u.completeDisbursement(ctx, disbursement); err != nil {
+		log.Println("[DisburseBalance] complete disbursement err:", err)
+		return nil, err
+	}
 
-		if _, err = u.journalEntryRepository.Create(egCtx, &domain.JournalEntry{
-			AccountID:       currentUserBalance.AccountNo,
-			TransactionName: "Balance disbursement",
-			CreditAmount:    currentUserBalance.Balance,
-			Folio:           createDisbursementResp.Data.ID,
-		}); err != nil {
-			log.Println("[DisburseBalance] Create journalentry credit err:", err)
-			return err
+	return disbursement, nil
+}
+
+// payoutNotSent reports whether err from CreatePayout means the request never reached the
+// provider: the circuit of its host was open or the request could not even be built.
+func payoutNotSent(err error) bool {
+	return stderrors.Is(err, httpclient.ErrCircuitOpen) ||
+		stderrors.Is(err, errors.ErrMissingCurrency) ||
+		stderrors.Is(err, errors.ErrPayoutNotSent)
+}
+
+// SettleDisbursement applies the final outcome a provider reported for a submitted
+// disbursement: a completed one is deducted from the wallet and posted to the ledger, a
+// failed one has its hold released. Reporting the outcome the disbursement already has
+// again changes nothing, a provider reporting the opposite one of a settled disbursement
+// sends it to manual review.
+func (u *UserBalanceUsecase) SettleDisbursement(ctx context.Context, settlement *domain.DisbursementSettlement) (*domain.Disbursement, error) {
+	if settlement == nil || settlement.ReferenceID == "" ||
+		(settlement.Status != domain.DisbursementStatusCompleted && settlement.Status != domain.DisbursementStatusFailed) {
+		return nil, errors.ErrInvalidParameter
+	}
+
+	disbursement, err := u.disbursementRepository.GetByReferenceID(ctx, settlement.ReferenceID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrDisbursementNotFound
 		}
 
-		return nil
+		log.Println("[SettleDisbursement] GetByReferenceID err:", err)
+		return nil, err
+	}
+
+	if !settlement.ByOperator && !slices.Contains(settlement.Providers, disbursement.Provider) {
+		return nil, errors.ErrDisbursementProviderMismatch
+	}
+	if settlement.PartnerDisbursementID != "" && disbursement.PartnerDisbursementID != "" &&
+		settlement.PartnerDisbursementID != disbursement.PartnerDisbursementID {
+		return nil, errors.ErrDisbursementPartnerMismatch
+	}
+	if disbursement.Status == settlement.Status {
+		return disbursement, nil
+	}
+	if settledStatus := disbursement.SettledStatus(); settledStatus != "" {
+		return u.resettleDisbursement(ctx, disbursement, settlement, settledStatus)
+	}
+	if !disbursement.Status.CanTransitionTo(settlement.Status) {
+		return nil, errors.ErrInvalidDisbursementStatusTransition
+	}
+	if disbursement.PartnerDisbursementID == "" {
+		disbursement.PartnerDisbursementID = settlement.PartnerDisbursementID
+	}
+
+	if settlement.Status == domain.DisbursementStatusCompleted {
+		err = u.completeDisbursement(ctx, disbursement)
+	} else {
+		err = u.failDisbursement(ctx, disbursement, settlement.FailureReason)
+	}
+	if err != nil {
+		log.Println("[SettleDisbursement] settle disbursement err:", err)
+		return nil, err
+	}
+
+	return disbursement, nil
+}
+
+// TopUpBalance credits the wallet with a deposit. Repeating a top-up with the same source
+// reference returns the recorded top-up instead of crediting the wallet again.
+func (u *UserBalanceUsecase) TopUpBalance(ctx context.Context, id int64, request *domain.TopUpBalanceRequest) (*domain.TopUp, error) {
+	if request == nil || request.Amount <= 0 || request.SourceReference == "" {
+		return nil, errors.ErrInvalidParameter
+	}
+	amount := request.Money()
+
+	var topUp *domain.TopUp
+	err := retryOnBalanceConflict(ctx, func() error {
+		return u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+			createdTopUp, err := u.topUpRepository.Create(txCtx, &domain.TopUp{
+				ReferenceID:     u.referenceIDGenerator.Generate(),
+				UserID:          id,
+				Amount:          amount.Amount,
+				Currency:        amount.Currency,
+				SourceReference: request.SourceReference,
+			})
+			if err != nil {
+				log.Println("[TopUpBalance] Create top-up record err:", err)
+				return err
+			}
+
+			if _, err := updateBalance(txCtx, u.userBalanceRepository, id, amount.Currency, func(userBalance *domain.UserBalance) error {
+				return userBalance.Credit(amount)
+			}); err != nil {
+				log.Println("[TopUpBalance] credit balance err:", err)
+				return err
+			}
+
+			journal := domain.NewJournalTransaction(createdTopUp.ReferenceID, "Balance top-up").
+				InCurrency(amount.Currency).
+				Debit(domain.CurrencyAccountID(domain.FundingClearingAccountID, amount.Currency), amount.Amount).
+				Credit(domain.WalletAccountID(id, amount.Currency), amount.Amount).
+				WithMetadata("user_id", strconv.FormatInt(id, 10)).
+				WithMetadata("source_reference", request.SourceReference)
+			if err := u.journalEntryRepository.CreateTransaction(txCtx, journal); err != nil {
+				log.Println("[TopUpBalance] CreateTransaction journal err:", err)
+				return err
+			}
+			topUp = createdTopUp
+
+			return nil
+		})
 	})
+	if err == errors.ErrDuplicateTopUpSourceReference {
+		return u.recordedTopUp(ctx, id, request)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return topUp, nil
+}
+
+// recordedTopUp returns the top-up already recorded for the source reference of request, as
+// long as it is the same deposit into the same wallet.
+func (u *UserBalanceUsecase) recordedTopUp(ctx context.Context, id int64, request *domain.TopUpBalanceRequest) (*domain.TopUp, error) {
+	topUp, err := u.topUpRepository.GetBySourceReference(ctx, request.SourceReference)
+	if err != nil {
+		log.Println("[TopUpBalance] GetBySourceReference err:", err)
+		return nil, err
+	}
+
+	if topUp.UserID != id || topUp.Money() != request.Money() {
+		return nil, errors.ErrTopUpSourceReferenceConflict
+	}
 
-	if err := eg.Wait(); err != nil {
+	return topUp, nil
+}
+
+// resettleDisbursement handles an outcome for a disbursement whose amount was already
+// deducted or released with settledStatus. The amount is not moved again: a provider
+// contradicting settledStatus sends the disbursement to manual review for a person to sort
+// out, and that person can only give it settledStatus back.
+func (u *UserBalanceUsecase) resettleDisbursement(ctx context.Context, disbursement *domain.Disbursement, settlement *domain.DisbursementSettlement, settledStatus domain.DisbursementStatus) (*domain.Disbursement, error) {
+	if settlement.ByOperator {
+		if disbursement.Status != domain.DisbursementStatusManualReview || settlement.Status != settledStatus {
+			return nil, errors.ErrInvalidDisbursementStatusTransition
+		}
+
+		if err := u.transitionDisbursement(ctx, disbursement, settledStatus); err != nil {
+			log.Println("[SettleDisbursement] restore settled status err:", err)
+			return nil, err
+		}
+
+		return disbursement, nil
+	}
+
+	// already in manual review, or reversed by a person who knew better
+	if !disbursement.Status.CanTransitionTo(domain.DisbursementStatusManualReview) {
+		return disbursement, nil
+	}
+
+	if err := u.transitionDisbursement(ctx, disbursement, domain.DisbursementStatusManualReview); err != nil {
+		log.Println("[SettleDisbursement] escalate to manual review err:", err)
+		return nil, err
+	}
+	log.Printf("[SettleDisbursement] disbursement %s was %s but %s reported %s, escalated to manual review",
+		disbursement.ReferenceID, settledStatus, disbursement.Provider, settlement.Status)
+
+	return disbursement, nil
+}
+
+// transitionDisbursement only applies the new status to disbursement once it is stored, so a
+// transaction that rolls back and is retried starts again from the previous status.
+func (u *UserBalanceUsecase) transitionDisbursement(ctx context.Context, disbursement *domain.Disbursement, status domain.DisbursementStatus) error {
+	updated := *disbursement
+	if err := updated.TransitionTo(status, time.Now().UTC()); err != nil {
 		return err
 	}
 
+	if err := u.disbursementRepository.UpdateStatus(ctx, &updated, disbursement.Status); err != nil {
+		return err
+	}
+	*disbursement = updated
+
 	return nil
 }
+
+// completeDisbursement deducts the held amount of a submitted disbursement from the wallet.
+// Capturing the hold, both journal legs and the final status commit or roll back together.
+func (u *UserBalanceUsecase) completeDisbursement(ctx context.Context, disbursement *domain.Disbursement) error {
+	amount := disbursement.Money()
+
+	return retryOnBalanceConflict(ctx, func() error {
+		return u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+			if _, err := updateBalance(txCtx, u.userBalanceRepository, disbursement.UserID, amount.Currency, func(userBalance *domain.UserBalance) error {
+				return userBalance.CaptureHold(amount)
+			}); err != nil {
+				log.Println("[completeDisbursement] capture held balance err:", err)
+				return err
+			}
+
+			journal := domain.NewJournalTransaction(disbursement.ReferenceID, "Balance disbursement").
+				InCurrency(amount.Currency).
+				Debit(domain.WalletAccountID(disbursement.UserID, amount.Currency), amount.Amount).
+				Credit(domain.CurrencyAccountID(domain.BankClearingAccountID, amount.Currency), amount.Amount).
+				WithMetadata("user_id", strconv.FormatInt(disbursement.UserID, 10)).
+				WithMetadata("bank_code", disbursement.BankCode).
+				WithMetadata("account_no", disbursement.AccountNo).
+				WithMetadata("provider", disbursement.Provider).
+				WithMetadata("partner_disbursement_id", disbursement.PartnerDisbursementID)
+			if err := u.journalEntryRepository.CreateTransaction(txCtx, journal); err != nil {
+				log.Println("[completeDisbursement] CreateTransaction journal err:", err)
+				return err
+			}
+
+			return u.transitionDisbursement(txCtx, disbursement, domain.DisbursementStatusCompleted)
+		})
+	})
+}
+
+// failDisbursement releases the hold of a disbursement the partner rejected or reported as
+// failed, which gives the amount back to the wallet.
+func (u *UserBalanceUsecase) failDisbursement(ctx context.Context, disbursement *domain.Disbursement, reason string) error {
+	// the request context may already be cancelled or past its deadline
+	ctx = context.WithoutCancel(ctx)
+
+	disbursement.FailureReason = reason
+	err := retryOnBalanceConflict(ctx, func() error {
+		return u.transactionManager.WithinTransaction(ctx, func(txCtx context.Context) error {
+			if _, err := updateBalance(txCtx, u.userBalanceRepository, disbursement.UserID, disbursement.Currency, func(userBalance *domain.UserBalance) error {
+				return userBalance.ReleaseHold(disbursement.Money())
+			}); err != nil {
+				return err
+			}
+
+			return u.transitionDisbursement(txCtx, disbursement, domain.DisbursementStatusFailed)
+		})
+	})
+	if err != nil {
+		log.Println("[failDisbursement] fail disbursement err:", err)
+	}
+
+	return err
+}
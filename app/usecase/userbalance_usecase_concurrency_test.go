@@ -0,0 +1,240 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/krisdioles/ppr-wallet/app/domain"
+	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
+	"github.com/krisdioles/ppr-wallet/app/domain/mocks"
+	extMocks "github.com/krisdioles/ppr-wallet/app/external/mocks"
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database/migration"
+	"github.com/krisdioles/ppr-wallet/app/repository"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/krisdioles/ppr-wallet/pkg/refid"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestDB creates a migrated sqlite database that is opened the same way as the one of
+// the service and removed after the test.
+func newTestDB(t *testing.T) *sqlx.DB {
+	db := sqlx.MustConnect("sqlite3", filepath.Join(t.TempDir(), "wallet.db")+"?_busy_timeout=5000&_txlock=immediate&_foreign_keys=on")
+	t.Cleanup(func() { db.Close() })
+
+	migration.CreateLedgerAccountsTable(db)
+	for _, account := range domain.SystemLedgerAccounts(domain.DefaultCurrency) {
+		migration.InsertLedgerAccountRecord(db, *account)
+	}
+	migration.CreateUserBalancesTable(db)
+	migration.CreateJournalEntriesTable(db)
+	migration.CreateAccountingPeriodsTable(db)
+	migration.CreateDisbursementsTable(db)
+	migration.CreateTopUpsTable(db)
+	migration.CreateTransfersTable(db)
+	migration.CreateReversalsTable(db)
+	migration.CreateFXRatesTable(db)
+	migration.CreateFXQuotesTable(db)
+	migration.CreateConversionsTable(db)
+	migration.CreateReconciliationsTable(db)
+	migration.CreateBalanceVerificationsTable(db)
+	migration.CreateIdempotencyKeysTable(db)
+
+	return db
+}
+
+// assertLedgerMatchesWallets checks that every wallet balance is explained by the journal.
+func assertLedgerMatchesWallets(t *testing.T, db *sqlx.DB) {
+	ledgerUsecase := usecase.NewLedgerUsecase(repository.NewTransactionManager(db), repository.NewUserBalanceRepository(db), repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)), repository.NewBalanceVerificationRepository(db))
+
+	report, err := ledgerUsecase.VerifyWalletBalances(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, report.Mismatches)
+}
+
+func TestUserBalanceUsecase_DisburseBalance_Concurrent(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{
+		Username:    "andy123",
+		Balance:     1000,
+		BankCode:    "arthagraha",
+		AccountNo:   "083012322138",
+		AccountName: "Andy Garcia",
+	})
+	userID := int64(1)
+
+	// the bank is slow enough for the requests to overlap while the funds are held
+	bank1Client := new(extMocks.IBank1Client)
+	bank1Client.On("CreateDisbursement", mock.Anything, mock.Anything).
+		After(5*time.Millisecond).
+		Return(completedResponse, nil)
+
+	userBalanceRepo := repository.NewUserBalanceRepository(db)
+	usecase := usecase.NewUserBalanceUsecase(
+		repository.NewTransactionManager(db),
+		userBalanceRepo,
+		repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)),
+		repository.NewLedgerAccountRepository(db),
+		repository.NewDisbursementRepository(db),
+		repository.NewTopUpRepository(db),
+		newPayoutRouter(bank1Client),
+		refid.NewGenerator("WLT"),
+	)
+
+	// twice as many requests as the wallet can pay for
+	requests := 20
+	errs := make([]error, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = usecase.DisburseBalance(ctx, userID, &domain.DisburseBalanceRequest{Amount: 100})
+		}(i)
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.ErrorIs(t, err, domErr.ErrInsufficientBalance)
+	}
+	assert.Equal(t, 10, succeeded)
+
+	userBalance, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, userID, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), userBalance.Balance)
+	assert.Equal(t, int64(0), userBalance.HeldBalance)
+
+	var completed, journalEntries int
+	assert.NoError(t, db.Get(&completed, `SELECT COUNT(*) FROM disbursements WHERE status = ?`, domain.DisbursementStatusCompleted))
+	assert.NoError(t, db.Get(&journalEntries, `SELECT COUNT(*) FROM journal_entries WHERE transaction_name = 'Balance disbursement'`))
+	assert.Equal(t, 10, completed)
+	assert.Equal(t, 20, journalEntries)
+	assertLedgerMatchesWallets(t, db)
+}
+
+func TestUserBalanceUsecase_StaleBalanceWriteIsRejected(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+	userBalanceRepo := repository.NewUserBalanceRepository(db)
+
+	// two requests read the same version of the wallet
+	first, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	second, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, domain.DefaultCurrency)
+	assert.NoError(t, err)
+
+	assert.NoError(t, first.Debit(domain.NewMoney(300, domain.DefaultCurrency)))
+	assert.NoError(t, userBalanceRepo.UpdateBalance(ctx, first))
+
+	// the second write would overwrite the first debit
+	assert.NoError(t, second.Debit(domain.NewMoney(500, domain.DefaultCurrency)))
+	assert.ErrorIs(t, userBalanceRepo.UpdateBalance(ctx, second), domErr.ErrBalanceVersionConflict)
+
+	current, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(700), current.Balance)
+	assert.Equal(t, int64(1), current.Version)
+}
+
+// staleReadsUserBalanceRepository holds the first readers of a wallet back until all of
+// them have read it, so they all write on top of the same version and all but one lose.
+type staleReadsUserBalanceRepository struct {
+	domain.UserBalanceRepository
+
+	mu        sync.Mutex
+	readers   int
+	read      sync.WaitGroup
+	conflicts int
+}
+
+func newStaleReadsUserBalanceRepository(userBalanceRepository domain.UserBalanceRepository, readers int) *staleReadsUserBalanceRepository {
+	r := &staleReadsUserBalanceRepository{UserBalanceRepository: userBalanceRepository, readers: readers}
+	r.read.Add(readers)
+
+	return r
+}
+
+func (r *staleReadsUserBalanceRepository) GetByUserIDAndCurrency(ctx context.Context, userID int64, currency string) (*domain.UserBalance, error) {
+	userBalance, err := r.UserBalanceRepository.GetByUserIDAndCurrency(ctx, userID, currency)
+
+	r.mu.Lock()
+	held := r.readers > 0
+	r.readers--
+	r.mu.Unlock()
+	if held {
+		r.read.Done()
+		r.read.Wait()
+	}
+
+	return userBalance, err
+}
+
+func (r *staleReadsUserBalanceRepository) UpdateBalance(ctx context.Context, userBalance *domain.UserBalance) error {
+	err := r.UserBalanceRepository.UpdateBalance(ctx, userBalance)
+	if err == domErr.ErrBalanceVersionConflict {
+		r.mu.Lock()
+		r.conflicts++
+		r.mu.Unlock()
+	}
+
+	return err
+}
+
+func TestUserBalanceUsecase_TopUpBalance_RetriedOnStaleVersion(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+
+	// without a transaction around them both requests read version 0 before either writes
+	transactionManager := new(mocks.TransactionManager)
+	transactionManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	})
+	topUpRepo := new(mocks.TopUpRepository)
+	topUpRepo.On("Create", mock.Anything, mock.Anything).Return(func(ctx context.Context, topUp *domain.TopUp) (*domain.TopUp, error) {
+		return topUp, nil
+	})
+	journalEntryRepo := new(mocks.JournalEntryRepository)
+	journalEntryRepo.On("CreateTransaction", mock.Anything, mock.Anything).Return(nil)
+
+	userBalanceRepo := newStaleReadsUserBalanceRepository(repository.NewUserBalanceRepository(db), 2)
+	usecase := usecase.NewUserBalanceUsecase(transactionManager, userBalanceRepo, journalEntryRepo, nil, nil, topUpRepo, nil, refid.NewGenerator("WLT"))
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, amount := range []int64{100, 200} {
+		wg.Add(1)
+		go func(i int, amount int64) {
+			defer wg.Done()
+			_, errs[i] = usecase.TopUpBalance(ctx, 1, &domain.TopUpBalanceRequest{Amount: amount, SourceReference: fmt.Sprintf("VA-%d", i)})
+		}(i, amount)
+	}
+	wg.Wait()
+
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	// the loser wrote on a stale version, was rejected and credited the wallet on a retry
+	assert.Equal(t, 1, userBalanceRepo.conflicts)
+
+	current, err := userBalanceRepo.GetByUserIDAndCurrency(ctx, 1, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1300), current.Balance)
+	assert.Equal(t, int64(2), current.Version)
+	journalEntryRepo.AssertNumberOfCalls(t, "CreateTransaction", 2)
+}
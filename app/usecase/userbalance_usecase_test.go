@@ -4,15 +4,24 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"strconv"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/krisdioles/ppr-wallet/app/domain"
 	domErr "github.com/krisdioles/ppr-wallet/app/domain/errors"
 	"github.com/krisdioles/ppr-wallet/app/domain/mocks"
 	"github.com/krisdioles/ppr-wallet/app/external"
 	extMocks "github.com/krisdioles/ppr-wallet/app/external/mocks"
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database/migration"
+	"github.com/krisdioles/ppr-wallet/app/repository"
 	"github.com/krisdioles/ppr-wallet/app/usecase"
+	"github.com/krisdioles/ppr-wallet/config"
+	"github.com/krisdioles/ppr-wallet/pkg/httpclient"
+	"github.com/krisdioles/ppr-wallet/pkg/refid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -27,11 +36,11 @@ func TestUserBalanceUsecase_GetUserBalanceByID(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockUserBalanceRepo := new(mocks.UserBalanceRepository)
-		usecase := usecase.NewUserBalanceUsecase(mockUserBalanceRepo, nil, nil)
+		usecase := usecase.NewUserBalanceUsecase(nil, mockUserBalanceRepo, nil, nil, nil, nil, nil, nil)
 
-		mockUserBalanceRepo.On("GetByID", ctx, userID).Return(expectedUserBalance, nil)
+		mockUserBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(expectedUserBalance, nil)
 
-		result, err := usecase.GetUserBalanceByID(ctx, userID)
+		result, err := usecase.GetUserBalanceByID(ctx, userID, "")
 		assert.NoError(t, err)
 		assert.Equal(t, expectedUserBalance, result)
 
@@ -40,11 +49,11 @@ func TestUserBalanceUsecase_GetUserBalanceByID(t *testing.T) {
 
 	t.Run("UserNotFound", func(t *testing.T) {
 		mockUserBalanceRepo := new(mocks.UserBalanceRepository)
-		usecase := usecase.NewUserBalanceUsecase(mockUserBalanceRepo, nil, nil)
+		usecase := usecase.NewUserBalanceUsecase(nil, mockUserBalanceRepo, nil, nil, nil, nil, nil, nil)
 
-		mockUserBalanceRepo.On("GetByID", ctx, userID).Return(nil, sql.ErrNoRows)
+		mockUserBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(nil, sql.ErrNoRows)
 
-		result, err := usecase.GetUserBalanceByID(ctx, userID)
+		result, err := usecase.GetUserBalanceByID(ctx, userID, "")
 		assert.ErrorIs(t, err, domErr.ErrUserNotFound)
 		assert.Equal(t, (*domain.UserBalance)(nil), result)
 
@@ -53,11 +62,11 @@ func TestUserBalanceUsecase_GetUserBalanceByID(t *testing.T) {
 
 	t.Run("OtherError", func(t *testing.T) {
 		mockUserBalanceRepo := new(mocks.UserBalanceRepository)
-		usecase := usecase.NewUserBalanceUsecase(mockUserBalanceRepo, nil, nil)
+		usecase := usecase.NewUserBalanceUsecase(nil, mockUserBalanceRepo, nil, nil, nil, nil, nil, nil)
 
-		mockUserBalanceRepo.On("GetByID", ctx, userID).Return(nil, errors.New("some error"))
+		mockUserBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(nil, errors.New("some error"))
 
-		result, err := usecase.GetUserBalanceByID(ctx, userID)
+		result, err := usecase.GetUserBalanceByID(ctx, userID, "")
 		assert.Error(t, err)
 		assert.Nil(t, result)
 
@@ -70,157 +79,921 @@ func TestUserBalanceUsecase_DisburseBalance(t *testing.T) {
 	userID := int64(1)
 	userBalance := &domain.UserBalance{
 		ID:          userID,
+		UserID:      userID,
+		Currency:    domain.DefaultCurrency,
 		Balance:     1000,
+		HeldBalance: 500,
+		Version:     7,
 		AccountName: "Test User",
 		BankCode:    "BANK001",
 		AccountNo:   "1234567890",
 	}
+	request := &domain.DisburseBalanceRequest{
+		Amount: 400,
+	}
+	referenceID := "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7"
+	newDisbursement := &domain.Disbursement{
+		ReferenceID: referenceID,
+		UserID:      userID,
+		Amount:      request.Amount,
+		Currency:    domain.DefaultCurrency,
+		BankCode:    userBalance.BankCode,
+		AccountNo:   userBalance.AccountNo,
+		AccountName: userBalance.AccountName,
+		Status:      domain.DisbursementStatusPending,
+		Provider:    "bank1",
+	}
+	// every read gets its own copy, the usecase changes the wallet it reads before saving it
+	currentUserBalance := func(ctx context.Context, id int64, currency string) (*domain.UserBalance, error) {
+		current := *userBalance
+		return &current, nil
+	}
+	withBalances := func(balance, heldBalance int64) interface{} {
+		return mock.MatchedBy(func(userBalance *domain.UserBalance) bool {
+			return userBalance.Balance == balance && userBalance.HeldBalance == heldBalance && userBalance.Version == 7
+		})
+	}
+	held := withBalances(1000, 900)
+	captured := withBalances(600, 100)
+	released := withBalances(1000, 100)
+	// every call gets its own copy, the usecase moves the record through its statuses
+	createdDisbursement := func(ctx context.Context, disbursement *domain.Disbursement) (*domain.Disbursement, error) {
+		created := *disbursement
+		created.ID = 10
+		return &created, nil
+	}
+	withStatus := func(status domain.DisbursementStatus) interface{} {
+		return mock.MatchedBy(func(disbursement *domain.Disbursement) bool {
+			return disbursement.Status == status
+		})
+	}
+	failedWithReason := func(reason string) interface{} {
+		return mock.MatchedBy(func(disbursement *domain.Disbursement) bool {
+			return disbursement.Status == domain.DisbursementStatusFailed && disbursement.FailureReason == reason
+		})
+	}
+	inTransaction := func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+	okResponse := &external.Bank1CreateDisbursementResponse{
+		Status: "ok",
+		Data: external.Bank1CreateDisbursementResponseData{
+			ID:     "bank-disbursement-1",
+			Status: external.Bank1DisbursementStatusCompleted,
+		},
+	}
 
-	t.Run("Success", func(t *testing.T) {
-		mockUserBalanceRepo := new(mocks.UserBalanceRepository)
-		mockBank1Client := new(extMocks.IBank1Client)
-		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+	type mockSet struct {
+		userBalanceRepo      *mocks.UserBalanceRepository
+		bank1Client          *extMocks.IBank1Client
+		journalEntryRepo     *mocks.JournalEntryRepository
+		disbursementRepo     *mocks.DisbursementRepository
+		referenceIDGenerator *mocks.ReferenceIDGenerator
+		transactionManager   *mocks.TransactionManager
+	}
+	newUsecase := func() (domain.UserBalanceUsecase, *mockSet) {
+		m := &mockSet{
+			userBalanceRepo:      new(mocks.UserBalanceRepository),
+			bank1Client:          new(extMocks.IBank1Client),
+			journalEntryRepo:     new(mocks.JournalEntryRepository),
+			disbursementRepo:     new(mocks.DisbursementRepository),
+			referenceIDGenerator: new(mocks.ReferenceIDGenerator),
+			transactionManager:   new(mocks.TransactionManager),
+		}
+		m.transactionManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(inTransaction).Maybe()
+
+		return usecase.NewUserBalanceUsecase(m.transactionManager, m.userBalanceRepo, m.journalEntryRepo, nil, m.disbursementRepo, nil, newPayoutRouter(m.bank1Client), m.referenceIDGenerator), m
+	}
+	// expectHold sets up the calls up to the point where the bank is asked to pay out
+	expectHold := func(m *mockSet) {
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", mock.Anything, userID, domain.DefaultCurrency).Return(currentUserBalance)
+		m.userBalanceRepo.On("UpdateBalance", ctx, held).Return(nil).Once()
+		m.referenceIDGenerator.On("Generate").Return(referenceID)
+		m.disbursementRepo.On("Create", ctx, newDisbursement).Return(createdDisbursement)
+	}
 
-		usecase := usecase.NewUserBalanceUsecase(mockUserBalanceRepo, mockJournalEntryRepo, mockBank1Client)
+	t.Run("Success", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		expectHold(m)
+		m.bank1Client.On("CreateDisbursement", ctx, &external.Bank1CreateDisbursementRequest{
+			ReferenceID: referenceID,
+			Account: external.AccountObj{
+				AccountBankCode:   userBalance.BankCode,
+				AccountNo:         userBalance.AccountNo,
+				AccountHolderName: userBalance.AccountName,
+			},
+			Amount: external.AmountObj{
+				Total:    request.Amount,
+				Currency: "IDR",
+			},
+		}).Return(okResponse, nil)
+		m.disbursementRepo.On("UpdateStatus", ctx, withStatus(domain.DisbursementStatusSubmitted), domain.DisbursementStatusPending).Return(nil)
+		m.userBalanceRepo.On("UpdateBalance", ctx, captured).Return(nil).Once()
+		m.journalEntryRepo.On("CreateTransaction", ctx, domain.NewJournalTransaction(referenceID, "Balance disbursement").
+			Debit(domain.WalletAccountID(userID, domain.DefaultCurrency), request.Amount).
+			Credit(domain.BankClearingAccountID, request.Amount).
+			WithMetadata("user_id", "1").
+			WithMetadata("bank_code", userBalance.BankCode).
+			WithMetadata("account_no", userBalance.AccountNo).
+			WithMetadata("provider", "bank1").
+			WithMetadata("partner_disbursement_id", "bank-disbursement-1")).
+			Return(nil)
+		m.disbursementRepo.On("UpdateStatus", ctx, withStatus(domain.DisbursementStatusCompleted), domain.DisbursementStatusSubmitted).Return(nil)
+
+		result, err := usecase.DisburseBalance(ctx, userID, request)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10), result.ID)
+		assert.Equal(t, referenceID, result.ReferenceID)
+		assert.Equal(t, domain.DisbursementStatusCompleted, result.Status)
+		assert.Equal(t, "bank-disbursement-1", result.PartnerDisbursementID)
+		assert.NotNil(t, result.SubmittedAt)
+		assert.NotNil(t, result.CompletedAt)
+
+		m.userBalanceRepo.AssertExpectations(t)
+		m.bank1Client.AssertExpectations(t)
+		m.journalEntryRepo.AssertExpectations(t)
+		m.disbursementRepo.AssertExpectations(t)
+		m.transactionManager.AssertNumberOfCalls(t, "WithinTransaction", 2)
+		m.userBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, released)
+	})
 
-		mockUserBalanceRepo.On("GetByID", ctx, userID).Return(userBalance, nil)
-		mockBank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(&external.Bank1CreateDisbursementResponse{Status: "ok"}, nil)
-		mockUserBalanceRepo.On("UpdateBalanceByID", ctx, int64(0), userID).Return(nil)
-		mockJournalEntryRepo.On("Create", mock.Anything, &domain.JournalEntry{
-			AccountID:       strconv.Itoa(int(userBalance.ID)),
-			TransactionName: "Balance disbursement",
-			DebitAmount:     userBalance.Balance}).
-			Return(&domain.JournalEntry{}, nil)
+	t.Run("AwaitsSettlement", func(t *testing.T) {
+		usecase, m := newUsecase()
 
-		mockJournalEntryRepo.On("Create", mock.Anything, &domain.JournalEntry{
-			AccountID:       "1234567890",
-			TransactionName: "Balance disbursement",
-			CreditAmount:    userBalance.Balance}).
-			Return(&domain.JournalEntry{}, nil)
+		expectHold(m)
+		m.bank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(&external.Bank1CreateDisbursementResponse{
+			Status: "ok",
+			Data:   external.Bank1CreateDisbursementResponseData{ID: "bank-disbursement-1", Status: external.Bank1DisbursementStatusPending},
+		}, nil)
+		m.disbursementRepo.On("UpdateStatus", ctx, withStatus(domain.DisbursementStatusSubmitted), domain.DisbursementStatusPending).Return(nil)
 
-		err := usecase.DisburseBalance(ctx, userID)
+		result, err := usecase.DisburseBalance(ctx, userID, request)
 		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusSubmitted, result.Status)
+		assert.Equal(t, "bank-disbursement-1", result.PartnerDisbursementID)
 
-		mockUserBalanceRepo.AssertExpectations(t)
-		mockBank1Client.AssertExpectations(t)
-		mockJournalEntryRepo.AssertExpectations(t)
+		// the amount stays held until the bank reports the outcome
+		m.userBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, captured)
+		m.journalEntryRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+		m.transactionManager.AssertNumberOfCalls(t, "WithinTransaction", 1)
 	})
 
 	t.Run("UserNotFound", func(t *testing.T) {
-		mockUserBalanceRepo := new(mocks.UserBalanceRepository)
-		mockBank1Client := new(extMocks.IBank1Client)
-		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+		usecase, m := newUsecase()
 
-		usecase := usecase.NewUserBalanceUsecase(mockUserBalanceRepo, mockJournalEntryRepo, mockBank1Client)
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(nil, sql.ErrNoRows)
 
-		mockUserBalanceRepo.ExpectedCalls = nil
-
-		mockUserBalanceRepo.On("GetByID", ctx, userID).Return(nil, sql.ErrNoRows)
-
-		err := usecase.DisburseBalance(ctx, userID)
+		_, err := usecase.DisburseBalance(ctx, userID, request)
 		assert.ErrorIs(t, err, domErr.ErrUserNotFound)
 
-		mockUserBalanceRepo.AssertExpectations(t)
+		m.userBalanceRepo.AssertExpectations(t)
 	})
 
 	t.Run("InsufficientBalance", func(t *testing.T) {
-		mockUserBalanceRepo := new(mocks.UserBalanceRepository)
-		mockBank1Client := new(extMocks.IBank1Client)
-		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
-
-		usecase := usecase.NewUserBalanceUsecase(mockUserBalanceRepo, mockJournalEntryRepo, mockBank1Client)
-
-		mockUserBalanceRepo.ExpectedCalls = nil
+		usecase, m := newUsecase()
 
 		lowBalance := &domain.UserBalance{
-			ID:      userID,
-			Balance: 0,
+			ID:       userID,
+			UserID:   userID,
+			Currency: domain.DefaultCurrency,
+			Balance:  0,
 		}
 
-		mockUserBalanceRepo.On("GetByID", ctx, userID).Return(lowBalance, nil)
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(lowBalance, nil)
 
-		err := usecase.DisburseBalance(ctx, userID)
+		_, err := usecase.DisburseBalance(ctx, userID, request)
 		assert.ErrorIs(t, err, domErr.ErrInsufficientBalance)
 
-		mockUserBalanceRepo.AssertExpectations(t)
+		m.userBalanceRepo.AssertExpectations(t)
+		m.disbursementRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
 	})
 
-	t.Run("CreateDisbursementError", func(t *testing.T) {
-		mockUserBalanceRepo := new(mocks.UserBalanceRepository)
-		mockBank1Client := new(extMocks.IBank1Client)
-		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+	t.Run("AmountExceedsAvailableBalance", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(currentUserBalance)
+
+		// the balance covers the amount, but part of it is held by another disbursement
+		_, err := usecase.DisburseBalance(ctx, userID, &domain.DisburseBalanceRequest{Amount: userBalance.Balance})
+		assert.ErrorIs(t, err, domErr.ErrInsufficientBalance)
+
+		m.userBalanceRepo.AssertExpectations(t)
+		m.bank1Client.AssertNotCalled(t, "CreateDisbursement", mock.Anything, mock.Anything)
+	})
 
-		usecase := usecase.NewUserBalanceUsecase(mockUserBalanceRepo, mockJournalEntryRepo, mockBank1Client)
+	t.Run("InvalidAmount", func(t *testing.T) {
+		usecase, m := newUsecase()
 
-		mockUserBalanceRepo.On("GetByID", ctx, userID).Return(userBalance, nil)
-		mockBank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(nil, errors.New("disbursement error"))
+		_, err := usecase.DisburseBalance(ctx, userID, &domain.DisburseBalanceRequest{Amount: 0})
+		assert.ErrorIs(t, err, domErr.ErrInvalidParameter)
 
-		err := usecase.DisburseBalance(ctx, userID)
+		m.userBalanceRepo.AssertNotCalled(t, "GetByUserIDAndCurrency", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("HoldError", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(currentUserBalance)
+		m.userBalanceRepo.On("UpdateBalance", ctx, held).Return(errors.New("update error"))
+
+		_, err := usecase.DisburseBalance(ctx, userID, request)
 		assert.Error(t, err)
 
-		mockUserBalanceRepo.AssertExpectations(t)
-		mockBank1Client.AssertExpectations(t)
+		m.userBalanceRepo.AssertExpectations(t)
+		m.disbursementRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		m.bank1Client.AssertNotCalled(t, "CreateDisbursement", mock.Anything, mock.Anything)
 	})
 
-	t.Run("PartnerError", func(t *testing.T) {
-		mockUserBalanceRepo := new(mocks.UserBalanceRepository)
-		mockBank1Client := new(extMocks.IBank1Client)
-		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+	t.Run("NoPayoutProvider", func(t *testing.T) {
+		m := &mockSet{userBalanceRepo: new(mocks.UserBalanceRepository)}
+		usecase := usecase.NewUserBalanceUsecase(nil, m.userBalanceRepo, nil, nil, nil, nil, external.NewPayoutProviderRegistry(0, 0), nil)
 
-		usecase := usecase.NewUserBalanceUsecase(mockUserBalanceRepo, mockJournalEntryRepo, mockBank1Client)
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(currentUserBalance)
 
-		mockUserBalanceRepo.ExpectedCalls = nil
+		_, err := usecase.DisburseBalance(ctx, userID, request)
+		assert.ErrorIs(t, err, domErr.ErrNoPayoutProvider)
+
+		// nothing is held when there is no one to pay out with
+		m.userBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything)
+	})
+
+	t.Run("CreateDisbursementRecordError", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(currentUserBalance)
+		m.userBalanceRepo.On("UpdateBalance", ctx, held).Return(nil)
+		m.referenceIDGenerator.On("Generate").Return(referenceID)
+		m.disbursementRepo.On("Create", ctx, newDisbursement).Return(nil, errors.New("insert error"))
+
+		_, err := usecase.DisburseBalance(ctx, userID, request)
+		assert.Error(t, err)
+
+		m.disbursementRepo.AssertExpectations(t)
+		m.bank1Client.AssertNotCalled(t, "CreateDisbursement", mock.Anything, mock.Anything)
+	})
+
+	t.Run("CreateDisbursementError", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		expectHold(m)
+		m.bank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(nil, errors.New("connection reset"))
+		m.disbursementRepo.On("UpdateStatus", mock.Anything, withStatus(domain.DisbursementStatusSubmitted), domain.DisbursementStatusPending).Return(nil)
+
+		// the bank may have taken the payout, the amount stays held until its outcome is known
+		result, err := usecase.DisburseBalance(ctx, userID, request)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusSubmitted, result.Status)
+		assert.Empty(t, result.PartnerDisbursementID)
+
+		m.userBalanceRepo.AssertExpectations(t)
+		m.bank1Client.AssertExpectations(t)
+		m.disbursementRepo.AssertExpectations(t)
+		m.userBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, released)
+		m.userBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, captured)
+	})
 
-		mockUserBalanceRepo.On("GetByID", ctx, userID).Return(userBalance, nil)
-		mockBank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(&external.Bank1CreateDisbursementResponse{Status: "failed"}, nil)
+	t.Run("CircuitOpen", func(t *testing.T) {
+		usecase, m := newUsecase()
 
-		err := usecase.DisburseBalance(ctx, userID)
+		expectHold(m)
+		m.bank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(nil, fmt.Errorf("%w: bank1.example", httpclient.ErrCircuitOpen))
+		m.userBalanceRepo.On("UpdateBalance", mock.Anything, released).Return(nil).Once()
+		m.disbursementRepo.On("UpdateStatus", mock.Anything, withStatus(domain.DisbursementStatusFailed), domain.DisbursementStatusPending).Return(nil)
+
+		// the request never left, so the amount is given back right away
+		_, err := usecase.DisburseBalance(ctx, userID, request)
+		assert.ErrorIs(t, err, domErr.ErrNoPayoutProvider)
+
+		m.userBalanceRepo.AssertExpectations(t)
+		m.disbursementRepo.AssertExpectations(t)
+		m.disbursementRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, withStatus(domain.DisbursementStatusSubmitted), mock.Anything)
+	})
+
+	t.Run("RequestNotSent", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		expectHold(m)
+		m.bank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(nil, fmt.Errorf("%w: invalid URL", domErr.ErrPayoutNotSent))
+		m.userBalanceRepo.On("UpdateBalance", mock.Anything, released).Return(nil).Once()
+		m.disbursementRepo.On("UpdateStatus", mock.Anything, withStatus(domain.DisbursementStatusFailed), domain.DisbursementStatusPending).Return(nil)
+
+		_, err := usecase.DisburseBalance(ctx, userID, request)
+		assert.ErrorIs(t, err, domErr.ErrPayoutNotSent)
+
+		m.userBalanceRepo.AssertExpectations(t)
+		m.disbursementRepo.AssertExpectations(t)
+	})
+
+	t.Run("PartnerTimeout", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		timeoutCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", mock.Anything, userID, domain.DefaultCurrency).Return(currentUserBalance)
+		m.userBalanceRepo.On("UpdateBalance", timeoutCtx, held).Return(nil).Once()
+		m.referenceIDGenerator.On("Generate").Return(referenceID)
+		m.disbursementRepo.On("Create", timeoutCtx, newDisbursement).Return(createdDisbursement)
+		m.bank1Client.On("CreateDisbursement", timeoutCtx, mock.Anything).Return(nil, context.DeadlineExceeded)
+		m.disbursementRepo.On("UpdateStatus", mock.MatchedBy(func(ctx context.Context) bool {
+			return ctx.Err() == nil
+		}), withStatus(domain.DisbursementStatusSubmitted), domain.DisbursementStatusPending).Return(nil)
+
+		result, err := usecase.DisburseBalance(timeoutCtx, userID, request)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusSubmitted, result.Status)
+		assert.NotNil(t, result.SubmittedAt)
+
+		m.userBalanceRepo.AssertExpectations(t)
+		m.disbursementRepo.AssertExpectations(t)
+		m.userBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, released)
+	})
+
+	t.Run("PartnerError", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		expectHold(m)
+		m.bank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(&external.Bank1CreateDisbursementResponse{Status: "failed", Message: "account closed"}, nil)
+		m.userBalanceRepo.On("UpdateBalance", mock.Anything, released).Return(nil).Once()
+		m.disbursementRepo.On("UpdateStatus", mock.Anything, failedWithReason("account closed"), domain.DisbursementStatusPending).Return(nil)
+
+		_, err := usecase.DisburseBalance(ctx, userID, request)
 		assert.ErrorIs(t, err, domErr.ErrPartnerError)
 
-		mockUserBalanceRepo.AssertExpectations(t)
-		mockBank1Client.AssertExpectations(t)
+		m.userBalanceRepo.AssertExpectations(t)
+		m.bank1Client.AssertExpectations(t)
+		m.disbursementRepo.AssertExpectations(t)
 	})
 
-	t.Run("UpdateBalanceError", func(t *testing.T) {
-		mockUserBalanceRepo := new(mocks.UserBalanceRepository)
-		mockBank1Client := new(extMocks.IBank1Client)
-		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+	t.Run("SubmitStatusError", func(t *testing.T) {
+		usecase, m := newUsecase()
 
-		usecase := usecase.NewUserBalanceUsecase(mockUserBalanceRepo, mockJournalEntryRepo, mockBank1Client)
+		expectHold(m)
+		m.bank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(okResponse, nil)
+		m.disbursementRepo.On("UpdateStatus", ctx, withStatus(domain.DisbursementStatusSubmitted), domain.DisbursementStatusPending).Return(domErr.ErrDisbursementStatusConflict)
 
-		mockUserBalanceRepo.ExpectedCalls = nil
+		_, err := usecase.DisburseBalance(ctx, userID, request)
+		assert.ErrorIs(t, err, domErr.ErrDisbursementStatusConflict)
 
-		mockUserBalanceRepo.On("GetByID", ctx, userID).Return(userBalance, nil)
-		mockBank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(&external.Bank1CreateDisbursementResponse{Status: "ok"}, nil)
-		mockUserBalanceRepo.On("UpdateBalanceByID", ctx, int64(0), userID).Return(errors.New("update error"))
+		m.disbursementRepo.AssertExpectations(t)
+		m.userBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, captured)
+	})
+
+	t.Run("CaptureError", func(t *testing.T) {
+		usecase, m := newUsecase()
 
-		err := usecase.DisburseBalance(ctx, userID)
+		expectHold(m)
+		m.bank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(okResponse, nil)
+		m.disbursementRepo.On("UpdateStatus", ctx, withStatus(domain.DisbursementStatusSubmitted), domain.DisbursementStatusPending).Return(nil)
+		m.userBalanceRepo.On("UpdateBalance", ctx, captured).Return(errors.New("update error")).Once()
+
+		_, err := usecase.DisburseBalance(ctx, userID, request)
 		assert.Error(t, err)
 
-		mockUserBalanceRepo.AssertExpectations(t)
-		mockBank1Client.AssertExpectations(t)
+		m.userBalanceRepo.AssertExpectations(t)
+		m.bank1Client.AssertExpectations(t)
+		m.disbursementRepo.AssertExpectations(t)
+		m.journalEntryRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
 	})
 
 	t.Run("JournalEntryError", func(t *testing.T) {
-		mockUserBalanceRepo := new(mocks.UserBalanceRepository)
-		mockBank1Client := new(extMocks.IBank1Client)
-		mockJournalEntryRepo := new(mocks.JournalEntryRepository)
+		usecase, m := newUsecase()
+
+		expectHold(m)
+		m.bank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(okResponse, nil)
+		m.disbursementRepo.On("UpdateStatus", ctx, withStatus(domain.DisbursementStatusSubmitted), domain.DisbursementStatusPending).Return(nil)
+		m.userBalanceRepo.On("UpdateBalance", ctx, captured).Return(nil).Once()
+		m.journalEntryRepo.On("CreateTransaction", mock.Anything, mock.Anything).Return(errors.New("journal entry error"))
+
+		_, err := usecase.DisburseBalance(ctx, userID, request)
+		assert.Error(t, err)
+
+		m.userBalanceRepo.AssertExpectations(t)
+		m.bank1Client.AssertExpectations(t)
+		m.journalEntryRepo.AssertExpectations(t)
+		m.disbursementRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, withStatus(domain.DisbursementStatusCompleted), mock.Anything)
+	})
+
+	t.Run("VersionConflictRetried", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		// another request changed the wallet between the read and the write of the hold
+		m.userBalanceRepo.On("UpdateBalance", ctx, held).Return(domErr.ErrBalanceVersionConflict).Once()
+		expectHold(m)
+		m.bank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(okResponse, nil)
+		m.disbursementRepo.On("UpdateStatus", ctx, withStatus(domain.DisbursementStatusSubmitted), domain.DisbursementStatusPending).Return(nil)
+		m.userBalanceRepo.On("UpdateBalance", ctx, captured).Return(domErr.ErrBalanceVersionConflict).Once()
+		m.userBalanceRepo.On("UpdateBalance", ctx, captured).Return(nil).Once()
+		m.journalEntryRepo.On("CreateTransaction", ctx, mock.Anything).Return(nil)
+		m.disbursementRepo.On("UpdateStatus", ctx, withStatus(domain.DisbursementStatusCompleted), domain.DisbursementStatusSubmitted).Return(nil)
+
+		result, err := usecase.DisburseBalance(ctx, userID, request)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusCompleted, result.Status)
+
+		m.userBalanceRepo.AssertExpectations(t)
+		m.userBalanceRepo.AssertNumberOfCalls(t, "UpdateBalance", 4)
+		m.disbursementRepo.AssertNumberOfCalls(t, "Create", 1)
+		m.bank1Client.AssertNumberOfCalls(t, "CreateDisbursement", 1)
+	})
+
+	t.Run("VersionConflictExhausted", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(currentUserBalance)
+		m.userBalanceRepo.On("UpdateBalance", ctx, held).Return(domErr.ErrBalanceVersionConflict)
+
+		_, err := usecase.DisburseBalance(ctx, userID, request)
+		assert.ErrorIs(t, err, domErr.ErrBalanceVersionConflict)
+
+		m.userBalanceRepo.AssertNumberOfCalls(t, "UpdateBalance", 5)
+		m.disbursementRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		m.bank1Client.AssertNotCalled(t, "CreateDisbursement", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserBalanceUsecase_DisburseBalance_OpenBreaker(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+
+	// the bank is down, one failure opens the circuit of its host
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	bank1Client := external.NewBank1Client(&config.Bank1Config{
+		Hostname:             server.URL,
+		DisbursementEndpoint: "api/v1/disbursement",
+		HTTPClient:           config.HTTPClientConfig{BreakerThreshold: 1, BreakerCooldown: time.Hour},
+	})
+	walletUsecase := newWalletUsecase(db, bank1Client)
+	disbursementRepository := repository.NewDisbursementRepository(db)
+
+	// the bank may have taken the first one before failing, so it stays held
+	unknown, err := walletUsecase.DisburseBalance(ctx, 1, &domain.DisburseBalanceRequest{Amount: 300})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DisbursementStatusSubmitted, unknown.Status)
+
+	// the second one is never sent and released right away
+	_, err = walletUsecase.DisburseBalance(ctx, 1, &domain.DisburseBalanceRequest{Amount: 200})
+	assert.ErrorIs(t, err, domErr.ErrNoPayoutProvider)
+	assert.Equal(t, 1, calls)
+
+	disbursements, err := disbursementRepository.GetByUserID(ctx, 1)
+	assert.NoError(t, err)
+	statuses := map[int64]domain.DisbursementStatus{}
+	for _, disbursement := range disbursements {
+		statuses[disbursement.Amount] = disbursement.Status
+	}
+	assert.Equal(t, map[int64]domain.DisbursementStatus{300: domain.DisbursementStatusSubmitted, 200: domain.DisbursementStatusFailed}, statuses)
+
+	wallet, err := walletUsecase.GetUserBalanceByID(ctx, 1, domain.DefaultCurrency)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), wallet.Balance)
+	assert.Equal(t, int64(300), wallet.HeldBalance)
+	assertLedgerMatchesWallets(t, db)
+}
+
+func TestUserBalanceUsecase_TopUpBalance(t *testing.T) {
+	ctx := context.Background()
+	userID := int64(1)
+	userBalance := &domain.UserBalance{
+		ID:       userID,
+		UserID:   userID,
+		Currency: domain.DefaultCurrency,
+		Balance:  1000,
+		Version:  2,
+	}
+	request := &domain.TopUpBalanceRequest{
+		Amount:          5000,
+		SourceReference: "VA-20240601-0001",
+	}
+	referenceID := "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W7"
+	newTopUp := &domain.TopUp{
+		ReferenceID:     referenceID,
+		UserID:          userID,
+		Amount:          request.Amount,
+		Currency:        domain.DefaultCurrency,
+		SourceReference: request.SourceReference,
+	}
+	recordedTopUp := &domain.TopUp{
+		ID:              3,
+		ReferenceID:     "WLT-01HZX3Q4J8M2N5P7R9S1T3V5W0",
+		UserID:          userID,
+		Amount:          request.Amount,
+		Currency:        domain.DefaultCurrency,
+		SourceReference: request.SourceReference,
+	}
+	currentUserBalance := func(ctx context.Context, id int64, currency string) (*domain.UserBalance, error) {
+		current := *userBalance
+		return &current, nil
+	}
+	credited := mock.MatchedBy(func(userBalance *domain.UserBalance) bool {
+		return userBalance.Balance == 6000 && userBalance.Version == 2
+	})
+	createdTopUp := func(ctx context.Context, topUp *domain.TopUp) (*domain.TopUp, error) {
+		created := *topUp
+		created.ID = 4
+		return &created, nil
+	}
+	inTransaction := func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+
+	type mockSet struct {
+		userBalanceRepo      *mocks.UserBalanceRepository
+		journalEntryRepo     *mocks.JournalEntryRepository
+		topUpRepo            *mocks.TopUpRepository
+		referenceIDGenerator *mocks.ReferenceIDGenerator
+		transactionManager   *mocks.TransactionManager
+	}
+	newUsecase := func() (domain.UserBalanceUsecase, *mockSet) {
+		m := &mockSet{
+			userBalanceRepo:      new(mocks.UserBalanceRepository),
+			journalEntryRepo:     new(mocks.JournalEntryRepository),
+			topUpRepo:            new(mocks.TopUpRepository),
+			referenceIDGenerator: new(mocks.ReferenceIDGenerator),
+			transactionManager:   new(mocks.TransactionManager),
+		}
+		m.transactionManager.On("WithinTransaction", mock.Anything, mock.Anything).Return(inTransaction).Maybe()
+		m.referenceIDGenerator.On("Generate").Return(referenceID).Maybe()
+
+		return usecase.NewUserBalanceUsecase(m.transactionManager, m.userBalanceRepo, m.journalEntryRepo, nil, nil, m.topUpRepo, nil, m.referenceIDGenerator), m
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.topUpRepo.On("Create", ctx, newTopUp).Return(createdTopUp)
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(currentUserBalance)
+		m.userBalanceRepo.On("UpdateBalance", ctx, credited).Return(nil)
+		m.journalEntryRepo.On("CreateTransaction", ctx, domain.NewJournalTransaction(referenceID, "Balance top-up").
+			Debit(domain.FundingClearingAccountID, request.Amount).
+			Credit(domain.WalletAccountID(userID, domain.DefaultCurrency), request.Amount).
+			WithMetadata("user_id", "1").
+			WithMetadata("source_reference", request.SourceReference)).
+			Return(nil)
+
+		result, err := usecase.TopUpBalance(ctx, userID, request)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), result.ID)
+		assert.Equal(t, referenceID, result.ReferenceID)
+
+		m.topUpRepo.AssertExpectations(t)
+		m.userBalanceRepo.AssertExpectations(t)
+		m.journalEntryRepo.AssertExpectations(t)
+	})
+
+	t.Run("SameSourceReferenceReplayed", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.topUpRepo.On("Create", ctx, newTopUp).Return(nil, domErr.ErrDuplicateTopUpSourceReference)
+		m.topUpRepo.On("GetBySourceReference", ctx, request.SourceReference).Return(recordedTopUp, nil)
+
+		result, err := usecase.TopUpBalance(ctx, userID, request)
+		assert.NoError(t, err)
+		assert.Equal(t, recordedTopUp, result)
+
+		m.topUpRepo.AssertExpectations(t)
+		m.userBalanceRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything)
+		m.journalEntryRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("SourceReferenceConflict", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.topUpRepo.On("Create", ctx, mock.Anything).Return(nil, domErr.ErrDuplicateTopUpSourceReference)
+		m.topUpRepo.On("GetBySourceReference", ctx, request.SourceReference).Return(recordedTopUp, nil)
+
+		// the source reference was already used for a different amount
+		_, err := usecase.TopUpBalance(ctx, userID, &domain.TopUpBalanceRequest{Amount: 100, SourceReference: request.SourceReference})
+		assert.ErrorIs(t, err, domErr.ErrTopUpSourceReferenceConflict)
+
+		m.topUpRepo.AssertExpectations(t)
+	})
 
-		usecase := usecase.NewUserBalanceUsecase(mockUserBalanceRepo, mockJournalEntryRepo, mockBank1Client)
+	t.Run("UserNotFound", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.topUpRepo.On("Create", ctx, newTopUp).Return(createdTopUp)
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(nil, sql.ErrNoRows)
+
+		_, err := usecase.TopUpBalance(ctx, userID, request)
+		assert.ErrorIs(t, err, domErr.ErrUserNotFound)
+
+		m.journalEntryRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("InvalidParameter", func(t *testing.T) {
+		usecase, m := newUsecase()
 
-		mockUserBalanceRepo.ExpectedCalls = nil
+		_, err := usecase.TopUpBalance(ctx, userID, &domain.TopUpBalanceRequest{Amount: 100})
+		assert.ErrorIs(t, err, domErr.ErrInvalidParameter)
 
-		mockUserBalanceRepo.On("GetByID", ctx, userID).Return(userBalance, nil)
-		mockBank1Client.On("CreateDisbursement", ctx, mock.Anything).Return(&external.Bank1CreateDisbursementResponse{Status: "ok"}, nil)
-		mockUserBalanceRepo.On("UpdateBalanceByID", ctx, int64(0), userID).Return(nil)
-		mockJournalEntryRepo.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("journal entry error"))
-		mockJournalEntryRepo.On("Create", mock.Anything, mock.Anything).Return(nil, errors.New("journal entry error"))
+		m.topUpRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+
+	t.Run("JournalEntryError", func(t *testing.T) {
+		usecase, m := newUsecase()
+
+		m.topUpRepo.On("Create", ctx, newTopUp).Return(createdTopUp)
+		m.userBalanceRepo.On("GetByUserIDAndCurrency", ctx, userID, domain.DefaultCurrency).Return(currentUserBalance)
+		m.userBalanceRepo.On("UpdateBalance", ctx, credited).Return(nil)
+		m.journalEntryRepo.On("CreateTransaction", ctx, mock.Anything).Return(errors.New("journal entry error"))
 
-		err := usecase.DisburseBalance(ctx, userID)
+		_, err := usecase.TopUpBalance(ctx, userID, request)
 		assert.Error(t, err)
 
-		mockUserBalanceRepo.AssertExpectations(t)
-		mockBank1Client.AssertExpectations(t)
-		mockJournalEntryRepo.AssertExpectations(t)
+		m.journalEntryRepo.AssertNumberOfCalls(t, "CreateTransaction", 1)
+	})
+}
+
+// completedResponse is a disbursement Bank1 paid out right away.
+var completedResponse = &external.Bank1CreateDisbursementResponse{
+	Status: "ok",
+	Data:   external.Bank1CreateDisbursementResponseData{Status: external.Bank1DisbursementStatusCompleted},
+}
+
+// newPayoutRouter routes every disbursement to bank1Client.
+func newPayoutRouter(bank1Client external.IBank1Client) external.PayoutRouter {
+	registry := external.NewPayoutProviderRegistry(0, 0)
+	registry.Register(external.NewBank1PayoutProvider("bank1", bank1Client), external.PayoutRoute{})
+
+	return registry
+}
+
+func newWalletUsecase(db *sqlx.DB, bank1Client external.IBank1Client) domain.UserBalanceUsecase {
+	return usecase.NewUserBalanceUsecase(
+		repository.NewTransactionManager(db),
+		repository.NewUserBalanceRepository(db),
+		repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)),
+		repository.NewLedgerAccountRepository(db),
+		repository.NewDisbursementRepository(db),
+		repository.NewTopUpRepository(db),
+		newPayoutRouter(bank1Client),
+		refid.NewGenerator("WLT"),
+	)
+}
+
+func TestUserBalanceUsecase_OpenWallet(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000, BankCode: "arthagraha", AccountNo: "083012322138", AccountName: "Andy Garcia"})
+	walletUsecase := newWalletUsecase(db, nil)
+
+	wallet, err := walletUsecase.OpenWallet(ctx, 1, &domain.OpenWalletRequest{Currency: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), wallet.UserID)
+	assert.Equal(t, "USD", wallet.Currency)
+	assert.Equal(t, int64(0), wallet.Balance)
+	assert.Equal(t, "083012322138", wallet.AccountNo)
+
+	wallets, err := walletUsecase.GetUserWallets(ctx, 1)
+	assert.NoError(t, err)
+	assert.Len(t, wallets, 2)
+
+	// the ledger accounts of the new currency are registered with the wallet
+	accountBalance, err := repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)).GetAccountBalance(ctx, "wallet:1:USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", accountBalance.Currency)
+	_, err = repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)).GetAccountBalance(ctx, "bank-clearing:USD")
+	assert.NoError(t, err)
+
+	_, err = walletUsecase.OpenWallet(ctx, 1, &domain.OpenWalletRequest{Currency: "USD"})
+	assert.ErrorIs(t, err, domErr.ErrWalletAlreadyExists)
+
+	_, err = walletUsecase.OpenWallet(ctx, 99, &domain.OpenWalletRequest{Currency: "USD"})
+	assert.ErrorIs(t, err, domErr.ErrUserNotFound)
+}
+
+func TestUserBalanceUsecase_ForeignCurrencyWallet(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "brandy345", Balance: 1000})
+
+	bank1Client := new(extMocks.IBank1Client)
+	bank1Client.On("CreateDisbursement", mock.Anything, mock.MatchedBy(func(request *external.Bank1CreateDisbursementRequest) bool {
+		return request.Amount == external.AmountObj{Total: 200, Currency: "USD"}
+	})).Return(completedResponse, nil)
+	walletUsecase := newWalletUsecase(db, bank1Client)
+
+	for _, userID := range []int64{1, 2} {
+		_, err := walletUsecase.OpenWallet(ctx, userID, &domain.OpenWalletRequest{Currency: "USD"})
+		assert.NoError(t, err)
+	}
+
+	topUp, err := walletUsecase.TopUpBalance(ctx, 1, &domain.TopUpBalanceRequest{Amount: 1000, Currency: "USD", SourceReference: "VA-USD-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", topUp.Currency)
+
+	transfer, err := usecase.NewTransferUsecase(repository.NewTransactionManager(db), repository.NewUserBalanceRepository(db), repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)), repository.NewTransferRepository(db), refid.NewGenerator("WLT")).
+		TransferBalance(ctx, 1, &domain.TransferBalanceRequest{ToUserID: 2, Amount: 300, Currency: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", transfer.Currency)
+
+	disbursement, err := walletUsecase.DisburseBalance(ctx, 2, &domain.DisburseBalanceRequest{Amount: 200, Currency: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", disbursement.Currency)
+
+	// a wallet only pays out in its own currency
+	_, err = walletUsecase.DisburseBalance(ctx, 2, &domain.DisburseBalanceRequest{Amount: 200, Currency: "EUR"})
+	assert.ErrorIs(t, err, domErr.ErrUserNotFound)
+
+	expected := map[int64]map[string]int64{
+		1: {"IDR": 1000, "USD": 700},
+		2: {"IDR": 1000, "USD": 100},
+	}
+	for userID, balances := range expected {
+		for currency, balance := range balances {
+			wallet, err := walletUsecase.GetUserBalanceByID(ctx, userID, currency)
+			assert.NoError(t, err)
+			assert.Equal(t, balance, wallet.Balance, "user %d %s", userID, currency)
+		}
+	}
+	assertLedgerMatchesWallets(t, db)
+}
+
+func TestUserBalanceUsecase_SettleDisbursement(t *testing.T) {
+	ctx := context.Background()
+
+	db := newTestDB(t)
+	migration.InsertUserBalancesRecord(db, domain.UserBalance{Username: "andy123", Balance: 1000})
+
+	// the bank accepts the payouts and reports their outcome later
+	pendingResponse := func(partnerID string) *external.Bank1CreateDisbursementResponse {
+		return &external.Bank1CreateDisbursementResponse{
+			Status: "ok",
+			Data:   external.Bank1CreateDisbursementResponseData{ID: partnerID, Status: external.Bank1DisbursementStatusPending},
+		}
+	}
+	bank1Client := new(extMocks.IBank1Client)
+	bank1Client.On("CreateDisbursement", mock.Anything, mock.Anything).Return(pendingResponse("bank-1"), nil).Once()
+	bank1Client.On("CreateDisbursement", mock.Anything, mock.Anything).Return(pendingResponse("bank-2"), nil).Once()
+	walletUsecase := newWalletUsecase(db, bank1Client)
+
+	assertWallet := func(balance, heldBalance int64) {
+		wallet, err := walletUsecase.GetUserBalanceByID(ctx, 1, domain.DefaultCurrency)
+		assert.NoError(t, err)
+		assert.Equal(t, balance, wallet.Balance)
+		assert.Equal(t, heldBalance, wallet.HeldBalance)
+	}
+
+	completed, err := walletUsecase.DisburseBalance(ctx, 1, &domain.DisburseBalanceRequest{Amount: 300})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.DisbursementStatusSubmitted, completed.Status)
+	failed, err := walletUsecase.DisburseBalance(ctx, 1, &domain.DisburseBalanceRequest{Amount: 200})
+	assert.NoError(t, err)
+
+	// nothing leaves the wallet until the bank confirms
+	assertWallet(1000, 500)
+	assertLedgerMatchesWallets(t, db)
+
+	t.Run("Completed", func(t *testing.T) {
+		result, err := walletUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+			ReferenceID:           completed.ReferenceID,
+			PartnerDisbursementID: "bank-1",
+			Status:                domain.DisbursementStatusCompleted,
+			Providers:             []string{"bank1"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusCompleted, result.Status)
+		assert.NotNil(t, result.CompletedAt)
+
+		assertWallet(700, 200)
+		assertLedgerMatchesWallets(t, db)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		result, err := walletUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+			ReferenceID:   failed.ReferenceID,
+			Status:        domain.DisbursementStatusFailed,
+			FailureReason: "account closed",
+			Providers:     []string{"bank1"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusFailed, result.Status)
+		assert.Equal(t, "account closed", result.FailureReason)
+
+		// the held amount is back in the wallet
+		assertWallet(700, 0)
+		assertLedgerMatchesWallets(t, db)
+	})
+
+	t.Run("Repeated", func(t *testing.T) {
+		result, err := walletUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+			ReferenceID: completed.ReferenceID,
+			Status:      domain.DisbursementStatusCompleted,
+			Providers:   []string{"bank1"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusCompleted, result.Status)
+
+		assertWallet(700, 0)
+		entries, err := repository.NewJournalEntryRepository(db, repository.NewAccountingPeriodRepository(db)).GetEntries(ctx, &domain.JournalEntryFilter{TransactionGroupID: completed.ReferenceID})
+		assert.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("FailedAfterCompleted", func(t *testing.T) {
+		_, err := walletUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+			ReferenceID: completed.ReferenceID,
+			Status:      domain.DisbursementStatusFailed,
+			ByOperator:  true,
+		})
+		assert.ErrorIs(t, err, domErr.ErrInvalidDisbursementStatusTransition)
+	})
+
+	t.Run("ReportedFailedAfterCompleted", func(t *testing.T) {
+		settlement := &domain.DisbursementSettlement{
+			ReferenceID: completed.ReferenceID,
+			Status:      domain.DisbursementStatusFailed,
+			Providers:   []string{"bank1"},
+		}
+		result, err := walletUsecase.SettleDisbursement(ctx, settlement)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusManualReview, result.Status)
+		completedAt := result.CompletedAt
+
+		// the bank reporting it again leaves it in review
+		result, err = walletUsecase.SettleDisbursement(ctx, settlement)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusManualReview, result.Status)
+
+		// nothing is refunded until a person sorted it out
+		assertWallet(700, 0)
+		assertLedgerMatchesWallets(t, db)
+
+		_, err = walletUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+			ReferenceID: completed.ReferenceID,
+			Status:      domain.DisbursementStatusFailed,
+			ByOperator:  true,
+		})
+		assert.ErrorIs(t, err, domErr.ErrInvalidDisbursementStatusTransition)
+
+		result, err = walletUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+			ReferenceID: completed.ReferenceID,
+			Status:      domain.DisbursementStatusCompleted,
+			ByOperator:  true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusCompleted, result.Status)
+		assert.Equal(t, completedAt, result.CompletedAt)
+		assertWallet(700, 0)
+	})
+
+	t.Run("ReportedCompletedAfterFailed", func(t *testing.T) {
+		result, err := walletUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+			ReferenceID: failed.ReferenceID,
+			Status:      domain.DisbursementStatusCompleted,
+			Providers:   []string{"bank1"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DisbursementStatusManualReview, result.Status)
+
+		assertWallet(700, 0)
+		assertLedgerMatchesWallets(t, db)
+	})
+
+	t.Run("ProviderMismatch", func(t *testing.T) {
+		_, err := walletUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+			ReferenceID: completed.ReferenceID,
+			Status:      domain.DisbursementStatusCompleted,
+			Providers:   []string{"bank2"},
+		})
+		assert.ErrorIs(t, err, domErr.ErrDisbursementProviderMismatch)
+	})
+
+	t.Run("PartnerMismatch", func(t *testing.T) {
+		_, err := walletUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+			ReferenceID:           completed.ReferenceID,
+			PartnerDisbursementID: "bank-2",
+			Status:                domain.DisbursementStatusCompleted,
+			Providers:             []string{"bank1"},
+		})
+		assert.ErrorIs(t, err, domErr.ErrDisbursementPartnerMismatch)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := walletUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+			ReferenceID: "WLT-UNKNOWN",
+			Status:      domain.DisbursementStatusCompleted,
+		})
+		assert.ErrorIs(t, err, domErr.ErrDisbursementNotFound)
+	})
+
+	t.Run("NotFinal", func(t *testing.T) {
+		_, err := walletUsecase.SettleDisbursement(ctx, &domain.DisbursementSettlement{
+			ReferenceID: completed.ReferenceID,
+			Status:      domain.DisbursementStatusSubmitted,
+		})
+		assert.ErrorIs(t, err, domErr.ErrInvalidParameter)
 	})
 }
@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+)
+
+// BalanceVerificationWorker periodically checks that every wallet balance matches the ledger.
+type BalanceVerificationWorker struct {
+	ledgerUsecase domain.LedgerUsecase
+	interval      time.Duration
+}
+
+func NewBalanceVerificationWorker(ledgerUsecase domain.LedgerUsecase, interval time.Duration) *BalanceVerificationWorker {
+	return &BalanceVerificationWorker{
+		ledgerUsecase: ledgerUsecase,
+		interval:      interval,
+	}
+}
+
+// Start runs a verification every interval until ctx is done.
+func (w *BalanceVerificationWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Run(ctx)
+		}
+	}
+}
+
+// Run verifies the wallet balances once. The usecase logs every mismatch it finds and stores
+// the report when there is one.
+func (w *BalanceVerificationWorker) Run(ctx context.Context) {
+	report, err := w.ledgerUsecase.VerifyWalletBalances(ctx)
+	if err != nil {
+		log.Println("[BalanceVerificationWorker] VerifyWalletBalances err:", err)
+		return
+	}
+
+	log.Printf("[BalanceVerificationWorker] checked %d wallets, %d mismatches", report.WalletsChecked, len(report.Mismatches))
+}
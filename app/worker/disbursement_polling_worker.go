@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/krisdioles/ppr-wallet/app/domain"
+)
+
+// DisbursementPollingWorker periodically asks the providers for the outcome of submitted disbursements.
+type DisbursementPollingWorker struct {
+	disbursementPollingUsecase domain.DisbursementPollingUsecase
+	interval                   time.Duration
+}
+
+func NewDisbursementPollingWorker(disbursementPollingUsecase domain.DisbursementPollingUsecase, interval time.Duration) *DisbursementPollingWorker {
+	return &DisbursementPollingWorker{
+		disbursementPollingUsecase: disbursementPollingUsecase,
+		interval:                   interval,
+	}
+}
+
+// Start polls every interval until ctx is done.
+func (w *DisbursementPollingWorker) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Run(ctx)
+		}
+	}
+}
+
+// Run polls the disbursements that are due once. The usecase logs every disbursement it
+// could not poll or escalated to manual review.
+func (w *DisbursementPollingWorker) Run(ctx context.Context) {
+	report, err := w.disbursementPollingUsecase.PollSubmittedDisbursements(ctx)
+	if err != nil {
+		log.Println("[DisbursementPollingWorker] PollSubmittedDisbursements err:", err)
+		return
+	}
+
+	if report.Checked > 0 {
+		log.Printf("[DisbursementPollingWorker] checked %d disbursements: %d completed, %d failed, %d pending, %d escalated, %d errors",
+			report.Checked, report.Completed, report.Failed, report.Pending, report.Escalated, report.Errors)
+	}
+}
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
+	"log"
+
 	"github.com/krisdioles/ppr-wallet/app/infrastructure/database"
 	"github.com/krisdioles/ppr-wallet/app/provider"
 	"github.com/krisdioles/ppr-wallet/app/server"
+	"github.com/krisdioles/ppr-wallet/app/worker"
 	"github.com/krisdioles/ppr-wallet/config"
 )
 
@@ -14,5 +18,19 @@ func main() {
 	repo := provider.InitRepositories(db)
 	usecase := provider.InitUsecases(cfg, repo)
 
-	server.InitHttpServer(&cfg.Server, usecase)
+	if cfg.FX.RatesFile != "" {
+		if err := usecase.FXUsecase.LoadRatesFile(context.Background(), cfg.FX.RatesFile); err != nil {
+			log.Fatal("load fx rates err: ", err)
+		}
+	}
+
+	if cfg.Worker.BalanceVerificationInterval > 0 {
+		go worker.NewBalanceVerificationWorker(usecase.LedgerUsecase, cfg.Worker.BalanceVerificationInterval).Start(context.Background())
+	}
+
+	if cfg.Worker.DisbursementPollInterval > 0 {
+		go worker.NewDisbursementPollingWorker(usecase.DisbursementPollingUsecase, cfg.Worker.DisbursementPollInterval).Start(context.Background())
+	}
+
+	server.InitHttpServer(cfg, usecase)
 }
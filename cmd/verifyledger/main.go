@@ -0,0 +1,41 @@
+// Command verifyledger walks the hash chain of the journal in database.db and reports the
+// first entry that was changed or removed. Entries removed from the end of the chain are only
+// found with -checkpoint, the last_hash of an earlier run. It exits with status 1 when the
+// chain is broken.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/krisdioles/ppr-wallet/app/infrastructure/database"
+	"github.com/krisdioles/ppr-wallet/app/provider"
+	"github.com/krisdioles/ppr-wallet/app/usecase"
+)
+
+func main() {
+	checkpoint := flag.String("checkpoint", "", "last_hash reported by an earlier run, it must still be in the chain")
+	flag.Parse()
+
+	db := database.Open()
+	defer db.Close()
+
+	repo := provider.InitRepositories(db)
+	ledgerUsecase := usecase.NewLedgerUsecase(repo.TransactionManager, repo.UserBalanceRepository, repo.JournalEntryRepository, repo.BalanceVerificationRepository)
+
+	report, err := ledgerUsecase.VerifyHashChain(context.Background(), *checkpoint)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(report)
+
+	if !report.Valid {
+		os.Exit(1)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -11,16 +12,91 @@ import (
 type Config struct {
 	Server ServerConfig
 	Bank1  Bank1Config
+	Worker WorkerConfig
+	FX     FXConfig
+	Payout PayoutConfig
+	Admin  AdminConfig
 }
 
 type ServerConfig struct {
 	Port int64
+	// IdempotencyKeyLockTimeout is how long a request holds its idempotency key, a key still in
+	// progress after that is taken over by the next request with it.
+	IdempotencyKeyLockTimeout time.Duration
+}
+
+type AdminConfig struct {
+	// APIKey has to be sent in the X-Admin-Key header of every admin request, empty rejects
+	// all of them.
+	APIKey string
 }
 
 type Bank1Config struct {
 	Hostname             string
 	APIKey               string
 	DisbursementEndpoint string
+	// WebhookSecret signs the disbursement callbacks of Bank1, empty rejects all of them.
+	WebhookSecret string
+	HTTPClient    HTTPClientConfig
+}
+
+// HTTPClientConfig tunes the retries and the circuit breaker of a client of an external
+// service, zero values take the defaults of pkg/httpclient.
+type HTTPClientConfig struct {
+	// Timeout bounds every attempt of a call.
+	Timeout time.Duration
+	// MaxRetries is how often an idempotent call is retried, negative disables retries.
+	MaxRetries  int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// BreakerThreshold failed calls in a row to a host stop all calls to it for BreakerCooldown.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+type WorkerConfig struct {
+	// BalanceVerificationInterval is how often wallet balances are checked against the ledger, zero disables the check.
+	BalanceVerificationInterval time.Duration
+	// DisbursementPollInterval is how often submitted disbursements are checked with their provider, zero disables polling.
+	DisbursementPollInterval time.Duration
+	// DisbursementPollBackoff is the wait after the first poll without an outcome, doubling up to DisbursementPollMaxBackoff.
+	DisbursementPollBackoff    time.Duration
+	DisbursementPollMaxBackoff time.Duration
+	// DisbursementReviewDeadline is how long after its submission a disbursement without outcome goes to manual review.
+	DisbursementReviewDeadline time.Duration
+	// DisbursementPendingTimeout is how long a disbursement may stay pending before it is polled like a submitted one.
+	DisbursementPendingTimeout time.Duration
+}
+
+type FXConfig struct {
+	// RatesFile is a JSON file with the exchange rates loaded at startup, empty loads none.
+	RatesFile string
+	// QuoteTTL is how long a quote locks its rate.
+	QuoteTTL time.Duration
+}
+
+type PayoutConfig struct {
+	// Providers are tried in order, a disbursement goes to the first healthy provider whose
+	// route matches it. Empty routes everything to the bank1 client above.
+	Providers []PayoutProviderConfig
+	// UnhealthyThreshold is the number of consecutive failed calls after which a provider is
+	// skipped for UnhealthyCooldown.
+	UnhealthyThreshold int
+	UnhealthyCooldown  time.Duration
+}
+
+type PayoutProviderConfig struct {
+	Name string
+	// Type selects the client, only "bank1" exists so far.
+	Type  string
+	Bank1 Bank1Config
+	// BankCodes and Currencies limit the route to these destination banks and currencies,
+	// empty matches all of them.
+	BankCodes  []string
+	Currencies []string
+	// MinAmount and MaxAmount limit the route to amounts in this range, zero is unbounded.
+	MinAmount int64
+	MaxAmount int64
 }
 
 var (
@@ -1 +1,305 @@
 package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout          = 5 * time.Second
+	defaultMaxRetries       = 2
+	defaultBaseBackoff      = 200 * time.Millisecond
+	defaultMaxBackoff       = 2 * time.Second
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+
+	// IdempotencyKeyHeader marks a request as safe to retry whatever its method.
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// ErrCircuitOpen is returned without sending the request while the circuit of its host is open.
+var ErrCircuitOpen = errors.New("httpclient: circuit open")
+
+// Config tunes a Client. Zero values take the defaults, a negative MaxRetries disables retries.
+type Config struct {
+	// Timeout bounds every attempt on its own, the context bounds the whole call.
+	Timeout     time.Duration
+	MaxRetries  int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// BreakerThreshold failed attempts in a row to a host open its circuit for BreakerCooldown.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// Hooks are called around every attempt, e.g. to log it. Either can be nil.
+type Hooks struct {
+	OnRequest func(req *http.Request, attempt int)
+	// OnResponse gets the response or the error of the attempt. The body of res must not be read.
+	OnResponse func(req *http.Request, res *http.Response, err error, attempt int, elapsed time.Duration)
+}
+
+// LogHooks log every attempt with the method and URL, never headers or bodies, behind name.
+func LogHooks(name string) Hooks {
+	return Hooks{
+		OnResponse: func(req *http.Request, res *http.Response, err error, attempt int, elapsed time.Duration) {
+			if err != nil {
+				log.Printf("[%s] %s %s attempt %d err: %v (%s)", name, req.Method, req.URL.Redacted(), attempt, err, elapsed)
+				return
+			}
+			log.Printf("[%s] %s %s attempt %d: %d (%s)", name, req.Method, req.URL.Redacted(), attempt, res.StatusCode, elapsed)
+		},
+	}
+}
+
+type Option func(*Client)
+
+// WithTransport sends the requests with transport instead of http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.httpClient.Transport = transport
+	}
+}
+
+func WithHooks(hooks Hooks) Option {
+	return func(c *Client) {
+		c.hooks = hooks
+	}
+}
+
+// Client sends HTTP requests, retrying the idempotent ones on network errors and on
+// responses that say the server may answer later, with exponential backoff and full jitter.
+// Every host has its own circuit: after BreakerThreshold failed attempts in a row, be it a
+// network error or a 5xx response, requests to it fail with ErrCircuitOpen until the cooldown
+// has passed. Then a single request is let through, which closes the circuit on success and
+// opens it again on failure.
+type Client struct {
+	httpClient *http.Client
+	config     Config
+	hooks      Hooks
+
+	now    func() time.Time
+	jitter func() float64
+
+	mu       sync.Mutex
+	breakers map[string]*breaker
+}
+
+func New(config Config, options ...Option) *Client {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = defaultMaxRetries
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = defaultBaseBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+	if config.MaxBackoff < config.BaseBackoff {
+		config.MaxBackoff = config.BaseBackoff
+	}
+	if config.BreakerThreshold <= 0 {
+		config.BreakerThreshold = defaultBreakerThreshold
+	}
+	if config.BreakerCooldown <= 0 {
+		config.BreakerCooldown = defaultBreakerCooldown
+	}
+
+	c := &Client{
+		httpClient: &http.Client{
+			Transport: http.DefaultTransport,
+			Timeout:   config.Timeout,
+		},
+		config:   config,
+		now:      time.Now,
+		jitter:   rand.Float64,
+		breakers: map[string]*breaker{},
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+// Do sends req like http.Client.Do. A request with a body is only retried when it can be
+// rewound through GetBody, as for the bodies http.NewRequest is given as a bytes.Reader.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	breaker := c.breaker(req.URL.Host)
+	retryable := isIdempotent(req) && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil)
+
+	for attempt := 1; ; attempt++ {
+		if !breaker.allow(c.now()) {
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, req.URL.Host)
+		}
+
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				breaker.release()
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		if c.hooks.OnRequest != nil {
+			c.hooks.OnRequest(req, attempt)
+		}
+		start := c.now()
+		res, err := c.httpClient.Do(req)
+		if c.hooks.OnResponse != nil {
+			c.hooks.OnResponse(req, res, err, attempt, c.now().Sub(start))
+		}
+
+		// a call the caller gave up on says nothing about the host
+		if ctx.Err() != nil {
+			breaker.release()
+			return res, err
+		}
+		breaker.record(err != nil || res.StatusCode >= http.StatusInternalServerError, c.now())
+
+		if !retryable || attempt > c.config.MaxRetries || !shouldRetry(res, err) {
+			return res, err
+		}
+
+		wait := c.backoff(attempt, res)
+		if res != nil {
+			// the connection is only reused once the body was read to the end
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff is the wait before the retry after attempt: a random duration up to
+// BaseBackoff doubled for every earlier attempt, capped at MaxBackoff. A server asking to
+// retry after a number of seconds is waited for, up to MaxBackoff.
+func (c *Client) backoff(attempt int, res *http.Response) time.Duration {
+	if res != nil {
+		if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			if wait := time.Duration(seconds) * time.Second; wait < c.config.MaxBackoff {
+				return wait
+			}
+			return c.config.MaxBackoff
+		}
+	}
+
+	ceiling := c.config.BaseBackoff
+	for i := 1; i < attempt && ceiling < c.config.MaxBackoff; i++ {
+		ceiling *= 2
+	}
+	if ceiling > c.config.MaxBackoff {
+		ceiling = c.config.MaxBackoff
+	}
+
+	return time.Duration(c.jitter() * float64(ceiling))
+}
+
+func (c *Client) breaker(host string) *breaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, ok := c.breakers[host]
+	if !ok {
+		b = &breaker{threshold: c.config.BreakerThreshold, cooldown: c.config.BreakerCooldown}
+		c.breakers[host] = b
+	}
+
+	return b
+}
+
+// isIdempotent reports whether sending req twice has the same effect as sending it once.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+func shouldRetry(res *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// breaker is the circuit of one host. While open only a single request is let through after
+// the cooldown, the others keep failing until its outcome is known.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+
+	consecutiveFailures int
+	openUntil           time.Time
+	probing             bool
+}
+
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.consecutiveFailures < b.threshold {
+		return true
+	}
+	if b.probing || now.Before(b.openUntil) {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+func (b *breaker) record(failed bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !failed {
+		b.consecutiveFailures = 0
+		return
+	}
+
+	b.consecutiveFailures++
+	if b.consecutiveFailures >= b.threshold {
+		b.openUntil = now.Add(b.cooldown)
+	}
+}
+
+// release lets another request probe the host when the probe ended without an outcome.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
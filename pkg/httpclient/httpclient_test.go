@@ -0,0 +1,236 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// stubTransport answers the requests in order with statuses, a zero status standing for a
+// network error, and records the body of every request it got.
+type stubTransport struct {
+	statuses []int
+	bodies   []string
+}
+
+func (s *stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body string
+	if req.Body != nil {
+		b, _ := io.ReadAll(req.Body)
+		body = string(b)
+	}
+	s.bodies = append(s.bodies, body)
+
+	status := s.statuses[0]
+	if len(s.statuses) > 1 {
+		s.statuses = s.statuses[1:]
+	}
+	if status == 0 {
+		return nil, errors.New("connection refused")
+	}
+
+	return &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+}
+
+func newTestClient(config Config, transport http.RoundTripper) *Client {
+	client := New(config, WithTransport(transport))
+	client.jitter = func() float64 { return 0 }
+
+	return client
+}
+
+func newRequest(t *testing.T, method, url, body string) *http.Request {
+	req, err := http.NewRequest(method, url, bytes.NewReader([]byte(body)))
+	assert.NoError(t, err)
+
+	return req
+}
+
+func TestClient_Do_Retries(t *testing.T) {
+	testCases := []struct {
+		name           string
+		method         string
+		idempotencyKey string
+		statuses       []int
+		expectedStatus int
+		expectedErr    bool
+		expectedCalls  int
+	}{
+		{"GetUntilSuccess", http.MethodGet, "", []int{503, 0, 200}, 200, false, 3},
+		{"GetGivesUp", http.MethodGet, "", []int{502}, 502, false, 3},
+		{"GetNetworkErrorGivesUp", http.MethodGet, "", []int{0}, 0, true, 3},
+		{"GetNotOnClientError", http.MethodGet, "", []int{404, 200}, 404, false, 1},
+		{"GetNotOnInternalServerError", http.MethodGet, "", []int{500, 200}, 500, false, 1},
+		{"PostNotRetried", http.MethodPost, "", []int{503, 200}, 503, false, 1},
+		{"PostNetworkErrorNotRetried", http.MethodPost, "", []int{0, 200}, 0, true, 1},
+		{"PostWithIdempotencyKey", http.MethodPost, "key-1", []int{429, 200}, 200, false, 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport := &stubTransport{statuses: tc.statuses}
+			client := newTestClient(Config{MaxRetries: 2}, transport)
+
+			req := newRequest(t, tc.method, "http://bank.test/disbursements", `{"amount":100}`)
+			if tc.idempotencyKey != "" {
+				req.Header.Set(IdempotencyKeyHeader, tc.idempotencyKey)
+			}
+
+			res, err := client.Do(req)
+			if tc.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedStatus, res.StatusCode)
+			}
+			assert.Len(t, transport.bodies, tc.expectedCalls)
+
+			// every attempt sends the whole body again
+			for _, body := range transport.bodies {
+				assert.Equal(t, `{"amount":100}`, body)
+			}
+		})
+	}
+}
+
+func TestClient_Do_RetriesDisabled(t *testing.T) {
+	transport := &stubTransport{statuses: []int{503, 200}}
+	client := newTestClient(Config{MaxRetries: -1}, transport)
+
+	res, err := client.Do(newRequest(t, http.MethodGet, "http://bank.test/disbursements/1", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, 503, res.StatusCode)
+	assert.Len(t, transport.bodies, 1)
+}
+
+func TestClient_Do_ContextCanceledDuringBackoff(t *testing.T) {
+	transport := &stubTransport{statuses: []int{503}}
+	client := New(Config{BaseBackoff: time.Hour, MaxBackoff: time.Hour}, WithTransport(transport))
+	client.jitter = func() float64 { return 1 }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	req := newRequest(t, http.MethodGet, "http://bank.test/disbursements/1", "").WithContext(ctx)
+	_, err := client.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, transport.bodies, 1)
+}
+
+func TestClient_Backoff(t *testing.T) {
+	client := New(Config{BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second})
+	client.jitter = func() float64 { return 1 }
+
+	assert.Equal(t, 100*time.Millisecond, client.backoff(1, nil))
+	assert.Equal(t, 200*time.Millisecond, client.backoff(2, nil))
+	assert.Equal(t, 800*time.Millisecond, client.backoff(4, nil))
+	assert.Equal(t, time.Second, client.backoff(5, nil))
+	assert.Equal(t, time.Second, client.backoff(60, nil))
+
+	client.jitter = func() float64 { return 0.5 }
+	assert.Equal(t, 100*time.Millisecond, client.backoff(2, nil))
+
+	t.Run("RetryAfter", func(t *testing.T) {
+		res := &http.Response{Header: http.Header{"Retry-After": []string{"0"}}}
+		assert.Equal(t, time.Duration(0), client.backoff(3, res))
+
+		res.Header.Set("Retry-After", "120")
+		assert.Equal(t, time.Second, client.backoff(1, res))
+	})
+}
+
+func TestClient_Do_CircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	calls := map[string]int{}
+	failing := true
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		calls[req.URL.Host]++
+		if failing && req.URL.Host == "bank.test" {
+			return nil, errors.New("connection refused")
+		}
+		return &http.Response{StatusCode: 200, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+	})
+
+	client := newTestClient(Config{MaxRetries: -1, BreakerThreshold: 2, BreakerCooldown: time.Minute}, transport)
+	client.now = func() time.Time { return now }
+
+	get := func(host string) error {
+		_, err := client.Do(newRequest(t, http.MethodGet, "http://"+host+"/disbursements/1", ""))
+		return err
+	}
+
+	assert.Error(t, get("bank.test"))
+	assert.Error(t, get("bank.test"))
+
+	// open: failing fast without calling the host, other hosts are not affected
+	assert.ErrorIs(t, get("bank.test"), ErrCircuitOpen)
+	assert.Equal(t, 2, calls["bank.test"])
+	assert.NoError(t, get("other.test"))
+
+	// after the cooldown a failing probe opens it again
+	now = now.Add(time.Minute)
+	assert.Error(t, get("bank.test"))
+	assert.ErrorIs(t, get("bank.test"), ErrCircuitOpen)
+	assert.Equal(t, 3, calls["bank.test"])
+
+	// a successful probe closes it
+	now = now.Add(time.Minute)
+	failing = false
+	assert.NoError(t, get("bank.test"))
+	assert.NoError(t, get("bank.test"))
+	assert.Equal(t, 5, calls["bank.test"])
+}
+
+func TestBreaker_SingleProbe(t *testing.T) {
+	now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	b := &breaker{threshold: 1, cooldown: time.Minute}
+
+	b.record(true, now)
+	assert.False(t, b.allow(now))
+
+	// only one request probes the host after the cooldown
+	now = now.Add(time.Minute)
+	assert.True(t, b.allow(now))
+	assert.False(t, b.allow(now))
+
+	// a probe without an outcome lets the next one through
+	b.release()
+	assert.True(t, b.allow(now))
+	b.record(false, now)
+	assert.True(t, b.allow(now))
+	assert.True(t, b.allow(now))
+}
+
+func TestClient_Do_Hooks(t *testing.T) {
+	transport := &stubTransport{statuses: []int{503, 200}}
+
+	var requested, responded []int
+	client := newTestClient(Config{}, transport)
+	client.hooks = Hooks{
+		OnRequest: func(req *http.Request, attempt int) {
+			requested = append(requested, attempt)
+		},
+		OnResponse: func(req *http.Request, res *http.Response, err error, attempt int, elapsed time.Duration) {
+			assert.NoError(t, err)
+			responded = append(responded, res.StatusCode)
+		},
+	}
+
+	_, err := client.Do(newRequest(t, http.MethodGet, "http://bank.test/disbursements/1", ""))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, requested)
+	assert.Equal(t, []int{503, 200}, responded)
+}
@@ -0,0 +1,103 @@
+package refid
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+	"time"
+)
+
+// crockford base32, lexicographic order of the alphabet matches numeric order
+const encoding = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const (
+	timestampSize = 6
+	entropySize   = 10
+	encodedSize   = 26
+)
+
+// Generator produces ULID-style reference IDs: a millisecond timestamp followed by
+// random bits, Crockford base32 encoded behind a fixed prefix. IDs sort by creation
+// time, and IDs generated within the same millisecond stay strictly increasing.
+type Generator struct {
+	prefix  string
+	now     func() time.Time
+	entropy io.Reader
+
+	mu          sync.Mutex
+	lastMs      uint64
+	lastEntropy [entropySize]byte
+}
+
+func NewGenerator(prefix string) *Generator {
+	return &Generator{
+		prefix:  prefix,
+		now:     time.Now,
+		entropy: rand.Reader,
+	}
+}
+
+func (g *Generator) Generate() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := uint64(g.now().UnixMilli())
+	if ms <= g.lastMs {
+		// same millisecond (or clock went backwards): keep ordering by incrementing the
+		// previous entropy, carrying over into the timestamp on overflow
+		ms = g.lastMs
+		if !increment(g.lastEntropy[:]) {
+			ms++
+		}
+	} else if _, err := io.ReadFull(g.entropy, g.lastEntropy[:]); err != nil {
+		panic("refid: reading entropy: " + err.Error())
+	}
+	g.lastMs = ms
+
+	var id [timestampSize + entropySize]byte
+	for i := 0; i < timestampSize; i++ {
+		id[i] = byte(ms >> (8 * (timestampSize - 1 - i)))
+	}
+	copy(id[timestampSize:], g.lastEntropy[:])
+
+	return g.prefix + "-" + encode(id)
+}
+
+// increment adds one to b as a big-endian number, reporting false on overflow.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func encode(id [timestampSize + entropySize]byte) string {
+	// 128 bits are encoded as 26 characters of 5 bits, the first one only carries 3
+	var out [encodedSize]byte
+	for i := encodedSize - 1; i >= 0; i-- {
+		out[i] = encoding[bitsAt(id, (encodedSize-1-i)*5)]
+	}
+
+	return string(out[:])
+}
+
+// bitsAt returns the 5 bits of id starting at offset, counted from the least significant bit.
+func bitsAt(id [timestampSize + entropySize]byte, offset int) byte {
+	var value byte
+	for bit := 0; bit < 5; bit++ {
+		position := offset + bit
+		if position >= len(id)*8 {
+			break
+		}
+		byteIndex := len(id) - 1 - position/8
+		if id[byteIndex]&(1<<(position%8)) != 0 {
+			value |= 1 << bit
+		}
+	}
+
+	return value
+}
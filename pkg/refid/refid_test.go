@@ -0,0 +1,96 @@
+package refid
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerator_Generate_Format(t *testing.T) {
+	generator := NewGenerator("WLT")
+
+	id := generator.Generate()
+
+	assert.True(t, strings.HasPrefix(id, "WLT-"))
+	assert.Len(t, id, len("WLT-")+encodedSize)
+	for _, c := range strings.TrimPrefix(id, "WLT-") {
+		assert.True(t, strings.ContainsRune(encoding, c), "unexpected character %q", c)
+	}
+}
+
+func TestGenerator_Generate_KnownValue(t *testing.T) {
+	generator := NewGenerator("WLT")
+	generator.now = func() time.Time { return time.UnixMilli(1469918176385) }
+	generator.entropy = bytes.NewReader(make([]byte, entropySize))
+
+	// timestamp part matches the reference ULID encoding of 1469918176385
+	assert.Equal(t, "WLT-01ARYZ6S410000000000000000", generator.Generate())
+}
+
+func TestGenerator_Generate_SortsByTime(t *testing.T) {
+	generator := NewGenerator("WLT")
+	now := time.UnixMilli(1700000000000)
+	generator.now = func() time.Time { return now }
+
+	first := generator.Generate()
+	now = now.Add(time.Millisecond)
+	second := generator.Generate()
+	now = now.Add(time.Hour)
+	third := generator.Generate()
+
+	assert.Less(t, first, second)
+	assert.Less(t, second, third)
+}
+
+func TestGenerator_Generate_MonotonicWithinMillisecond(t *testing.T) {
+	generator := NewGenerator("WLT")
+	generator.now = func() time.Time { return time.UnixMilli(1700000000000) }
+
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = generator.Generate()
+	}
+
+	assert.True(t, sort.StringsAreSorted(ids))
+}
+
+func TestGenerator_Generate_EntropyOverflow(t *testing.T) {
+	generator := NewGenerator("WLT")
+	generator.now = func() time.Time { return time.UnixMilli(1700000000000) }
+	generator.entropy = bytes.NewReader(bytes.Repeat([]byte{0xff}, entropySize))
+
+	first := generator.Generate()
+	second := generator.Generate()
+
+	assert.Less(t, first, second)
+}
+
+func TestGenerator_Generate_Concurrent(t *testing.T) {
+	generator := NewGenerator("WLT")
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		seen = make(map[string]struct{})
+	)
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				id := generator.Generate()
+				mu.Lock()
+				seen[id] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, seen, 8*500)
+}